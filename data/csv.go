@@ -0,0 +1,214 @@
+package data
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Rmehta-sudo/neural-net/labels"
+)
+
+// CSVOptions configures FromCSV.
+type CSVOptions struct {
+	Targets  []string // Header names of the target columns
+	Features []string // Header names of the input columns; nil uses every column that is not a target
+	Comma    rune     // Field separator; 0 means ','
+
+	// Standardize rescales every input to zero mean and unit variance over
+	// the file; the fitted scaler is kept in CSVDataset.Scaler so the same
+	// rescaling can be applied to new data.
+	Standardize bool
+}
+
+// CSVDataset is a Dataset read by FromCSV, with the meaning of its values.
+type CSVDataset struct {
+	*SliceDataset
+
+	InputNames  []string // Name of every input value; one-hot inputs are named "column=value"
+	TargetNames []string // Name of every target value
+
+	// Categories holds the values of every column that is neither numeric
+	// nor boolean, sorted, in the order of their encoding.
+	Categories map[string][]string
+
+	Scaler *StandardScaler // Scaler applied to the inputs; nil without Standardize
+}
+
+// FromCSV reads a dataset from CSV with a header row naming the columns.
+// Values are converted by column: numeric columns are parsed as floats,
+// boolean columns (true/false) become 1 and 0, and any other column is
+// categorical, one-hot encoded as inputs and encoded as the index of its
+// category as a target (the class label expected by the classification
+// losses). Empty fields are an error.
+func FromCSV(r io.Reader, opts CSVOptions) (*CSVDataset, error) {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("data: reading CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("data: CSV has no header")
+	}
+	header, rows := records[0], records[1:]
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, dup := index[name]; dup {
+			return nil, fmt.Errorf("data: duplicate CSV column %q", name)
+		}
+		index[name] = i
+	}
+
+	if len(opts.Targets) == 0 {
+		return nil, fmt.Errorf("data: no target columns")
+	}
+	targets, err := columnIndices(index, opts.Targets)
+	if err != nil {
+		return nil, err
+	}
+	features := opts.Features
+	if features == nil {
+		isTarget := map[string]bool{}
+		for _, name := range opts.Targets {
+			isTarget[name] = true
+		}
+		for _, name := range header {
+			if name = strings.TrimSpace(name); !isTarget[name] {
+				features = append(features, name)
+			}
+		}
+	}
+	inputs, err := columnIndices(index, features)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &CSVDataset{
+		SliceDataset: NewSliceDataset(make([][]float64, len(rows)), make([][]float64, len(rows))),
+		Categories:   map[string][]string{},
+	}
+	for k, col := range inputs {
+		c, err := parseColumn(rows, col, features[k])
+		if err != nil {
+			return nil, err
+		}
+		if c.categories != nil {
+			ds.Categories[features[k]] = c.categories
+			for _, cat := range c.categories {
+				ds.InputNames = append(ds.InputNames, features[k]+"="+cat)
+			}
+		} else {
+			ds.InputNames = append(ds.InputNames, features[k])
+		}
+		for i := range rows {
+			ds.Inputs[i] = append(ds.Inputs[i], c.encode(i, true)...)
+		}
+	}
+	for k, col := range targets {
+		c, err := parseColumn(rows, col, opts.Targets[k])
+		if err != nil {
+			return nil, err
+		}
+		if c.categories != nil {
+			ds.Categories[opts.Targets[k]] = c.categories
+		}
+		ds.TargetNames = append(ds.TargetNames, opts.Targets[k])
+		for i := range rows {
+			ds.Targets[i] = append(ds.Targets[i], c.encode(i, false)...)
+		}
+	}
+
+	if opts.Standardize && len(rows) > 0 {
+		ds.Scaler = &StandardScaler{}
+		if err := ds.Scaler.Fit(ds.Inputs); err != nil {
+			return nil, err
+		}
+		for i, x := range ds.Inputs {
+			ds.Inputs[i] = ds.Scaler.Transform(x)
+		}
+	}
+	return ds, nil
+}
+
+// columnIndices returns the position of every named column.
+func columnIndices(index map[string]int, names []string) ([]int, error) {
+	cols := make([]int, len(names))
+	for i, name := range names {
+		col, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("data: no CSV column %q", name)
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// csvColumn is one parsed column: numbers, or the category of every row.
+type csvColumn struct {
+	values     []float64
+	categories []string // Nil for numeric and boolean columns
+}
+
+// parseColumn converts column col of rows, choosing its type from the values.
+func parseColumn(rows [][]string, col int, name string) (csvColumn, error) {
+	fields := make([]string, len(rows))
+	for i, row := range rows {
+		fields[i] = strings.TrimSpace(row[col])
+		if fields[i] == "" {
+			return csvColumn{}, fmt.Errorf("data: CSV row %d has no value for %q", i+2, name)
+		}
+	}
+	if c, ok := parseFields(fields, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }); ok {
+		return c, nil
+	}
+	if c, ok := parseFields(fields, parseBool); ok {
+		return c, nil
+	}
+
+	enc := labels.FitEncoder(fields)
+	classes, _ := enc.Encode(fields) // Every field is a class of enc
+	c := csvColumn{values: make([]float64, len(fields)), categories: enc.Classes()}
+	for i, class := range classes {
+		c.values[i] = float64(class)
+	}
+	return c, nil
+}
+
+// parseFields converts every field with parse and reports whether all succeeded.
+func parseFields(fields []string, parse func(string) (float64, error)) (csvColumn, bool) {
+	c := csvColumn{values: make([]float64, len(fields))}
+	for i, f := range fields {
+		v, err := parse(f)
+		if err != nil {
+			return csvColumn{}, false
+		}
+		c.values[i] = v
+	}
+	return c, true
+}
+
+// parseBool converts "true" and "false", in any case, to 1 and 0.
+func parseBool(s string) (float64, error) {
+	switch strings.ToLower(s) {
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+	return 0, fmt.Errorf("not a boolean: %q", s)
+}
+
+// encode returns the values of row i: the category one-hot encoded if
+// oneHot is set, otherwise the single value or category index.
+func (c csvColumn) encode(i int, oneHot bool) []float64 {
+	if c.categories == nil || !oneHot {
+		return []float64{c.values[i]}
+	}
+	return labels.OneHot(int(c.values[i]), len(c.categories))
+}
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// CurriculumSampler serves only the easiest examples of a dataset, in random
+// order: the Fraction of examples with the lowest difficulty Scores. Raising
+// Fraction over the epochs (see train.Curriculum) starts training on easy
+// examples and gradually introduces the hard ones.
+type CurriculumSampler struct {
+	Scores   []float64 // Difficulty of every example of the dataset; lower is easier
+	Fraction float64   // Share of the examples served, from the easiest; at least one is served
+}
+
+// NewCurriculumSampler creates a sampler over examples with the given
+// difficulty scores, initially serving all of them.
+func NewCurriculumSampler(scores []float64) *CurriculumSampler {
+	return &CurriculumSampler{Scores: scores, Fraction: 1}
+}
+
+// Len returns the number of examples served per epoch.
+func (s *CurriculumSampler) Len() int {
+	if s.Fraction < 0 || s.Fraction > 1 {
+		panic(fmt.Sprintf("data: curriculum fraction %v outside [0, 1]", s.Fraction))
+	}
+	n := int(math.Ceil(s.Fraction * float64(len(s.Scores))))
+	return min(max(n, 1), len(s.Scores))
+}
+
+// Sample returns the indices of the easiest examples in random order. Ties in
+// difficulty are broken by index, so the selection is deterministic.
+func (s *CurriculumSampler) Sample(r *rand.Rand) []int {
+	order := make([]int, len(s.Scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return s.Scores[order[i]] < s.Scores[order[j]] })
+	pool := order[:s.Len()]
+	r.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	return pool
+}
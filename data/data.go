@@ -0,0 +1,55 @@
+// Package data provides datasets of input/target examples and a Loader that
+// groups them into shuffled mini-batches for training.
+package data
+
+import "fmt"
+
+// Example is a single input vector with its target vector.
+type Example struct {
+	Input  []float64
+	Target []float64
+}
+
+// Dataset is an indexable collection of examples.
+type Dataset interface {
+	Len() int
+	Get(i int) Example
+}
+
+// SliceDataset is an in-memory Dataset backed by parallel input and target slices.
+type SliceDataset struct {
+	Inputs  [][]float64
+	Targets [][]float64
+}
+
+// NewSliceDataset creates a dataset from one target vector per input.
+// It panics if the slices have different lengths.
+func NewSliceDataset(inputs, targets [][]float64) *SliceDataset {
+	if len(inputs) != len(targets) {
+		panic(fmt.Sprintf("data: %d inputs but %d targets", len(inputs), len(targets)))
+	}
+	return &SliceDataset{
+		Inputs:  inputs,
+		Targets: targets,
+	}
+}
+
+// NewScalarDataset creates a dataset with a single-value target per input,
+// the common case for regression and binary classification.
+func NewScalarDataset(inputs [][]float64, targets []float64) *SliceDataset {
+	wrapped := make([][]float64, len(targets))
+	for i, t := range targets {
+		wrapped[i] = []float64{t}
+	}
+	return NewSliceDataset(inputs, wrapped)
+}
+
+// Len returns the number of examples.
+func (ds *SliceDataset) Len() int {
+	return len(ds.Inputs)
+}
+
+// Get returns example i.
+func (ds *SliceDataset) Get(i int) Example {
+	return Example{Input: ds.Inputs[i], Target: ds.Targets[i]}
+}
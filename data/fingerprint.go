@@ -0,0 +1,40 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math"
+)
+
+// Fingerprint returns a SHA-256 digest, in hex, of the examples of ds in
+// order. Datasets with the same inputs and targets have the same fingerprint,
+// so it identifies the data a model was trained on (see
+// engine.ModelMetadata.DatasetFingerprint) without storing it.
+func Fingerprint(ds Dataset) string {
+	h := sha256.New()
+	writeUint(h, uint64(ds.Len()))
+	for i := 0; i < ds.Len(); i++ {
+		ex := ds.Get(i)
+		writeFloats(h, ex.Input)
+		writeFloats(h, ex.Target)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeFloats hashes the length and the bits of every value of xs, so the
+// boundaries between inputs and targets are part of the digest.
+func writeFloats(h hash.Hash, xs []float64) {
+	writeUint(h, uint64(len(xs)))
+	for _, x := range xs {
+		writeUint(h, math.Float64bits(x))
+	}
+}
+
+// writeUint hashes v as 8 little-endian bytes.
+func writeUint(h hash.Hash, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	h.Write(b[:])
+}
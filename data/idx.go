@@ -0,0 +1,155 @@
+package data
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// IDXArray is an array read from an IDX file, the format of the MNIST and
+// Fashion-MNIST images and labels.
+type IDXArray struct {
+	Shape []int     // Dimensions, e.g. [60000 28 28] for images or [60000] for labels
+	Data  []float64 // Values in row-major order
+	Bytes bool      // Whether the file stored unsigned bytes (0-255), as MNIST does
+}
+
+// Size returns the number of values of an element of the first dimension,
+// e.g. the pixels of one image.
+func (a *IDXArray) Size() int {
+	if len(a.Shape) == 0 {
+		return 0
+	}
+	n := 1
+	for _, d := range a.Shape[1:] {
+		n *= d
+	}
+	return n
+}
+
+// ReadIDX reads an IDX array from r, decompressing it first if it is gzipped
+// (as the files are distributed, e.g. train-images-idx3-ubyte.gz).
+func ReadIDX(r io.Reader) (*IDXArray, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("data: IDX: %w", err)
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	var head [4]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		return nil, fmt.Errorf("data: IDX header: %w", err)
+	}
+	if head[0] != 0 || head[1] != 0 || head[3] == 0 {
+		return nil, fmt.Errorf("data: not an IDX file")
+	}
+	a := &IDXArray{Shape: make([]int, head[3])}
+	size := 1
+	for i := range a.Shape {
+		var d uint32
+		if err := binary.Read(br, binary.BigEndian, &d); err != nil {
+			return nil, fmt.Errorf("data: IDX header: %w", err)
+		}
+		a.Shape[i] = int(d)
+		size *= int(d)
+	}
+
+	var width int
+	var decode func(b []byte) float64
+	switch head[2] {
+	case 0x08:
+		width, decode, a.Bytes = 1, func(b []byte) float64 { return float64(b[0]) }, true
+	case 0x09:
+		width, decode = 1, func(b []byte) float64 { return float64(int8(b[0])) }
+	case 0x0B:
+		width, decode = 2, func(b []byte) float64 { return float64(int16(binary.BigEndian.Uint16(b))) }
+	case 0x0C:
+		width, decode = 4, func(b []byte) float64 { return float64(int32(binary.BigEndian.Uint32(b))) }
+	case 0x0D:
+		width, decode = 4, func(b []byte) float64 { return float64(math.Float32frombits(binary.BigEndian.Uint32(b))) }
+	case 0x0E:
+		width, decode = 8, func(b []byte) float64 { return math.Float64frombits(binary.BigEndian.Uint64(b)) }
+	default:
+		return nil, fmt.Errorf("data: unknown IDX data type 0x%02x", head[2])
+	}
+	raw := make([]byte, size*width)
+	if _, err := io.ReadFull(br, raw); err != nil {
+		return nil, fmt.Errorf("data: IDX data: %w", err)
+	}
+	a.Data = make([]float64, size)
+	for i := range a.Data {
+		a.Data[i] = decode(raw[i*width:])
+	}
+	return a, nil
+}
+
+// OpenIDX reads the IDX file at path, gzipped or not.
+func OpenIDX(path string) (*IDXArray, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadIDX(f)
+}
+
+// NewIDXDataset pairs images with labels: every image is flattened into one
+// input vector, scaled from 0-255 to [0, 1] if it is stored as bytes. With
+// numClasses > 0 the targets are one-hot vectors of that length, otherwise the
+// label itself as a single value.
+func NewIDXDataset(images, labels *IDXArray, numClasses int) (*SliceDataset, error) {
+	if len(images.Shape) == 0 || len(labels.Shape) != 1 {
+		return nil, fmt.Errorf("data: IDX images of shape %v and labels of shape %v", images.Shape, labels.Shape)
+	}
+	n := images.Shape[0]
+	if labels.Shape[0] != n {
+		return nil, fmt.Errorf("data: %d IDX images but %d labels", n, labels.Shape[0])
+	}
+	size := images.Size()
+	scale := 1.0
+	if images.Bytes {
+		scale = 1.0 / 255
+	}
+	inputs, targets := make([][]float64, n), make([][]float64, n)
+	for i := range inputs {
+		inputs[i] = make([]float64, size)
+		for j, v := range images.Data[i*size : (i+1)*size] {
+			inputs[i][j] = v * scale
+		}
+		label := labels.Data[i]
+		if numClasses <= 0 {
+			targets[i] = []float64{label}
+			continue
+		}
+		if label < 0 || int(label) >= numClasses || label != math.Trunc(label) {
+			return nil, fmt.Errorf("data: IDX label %v of example %d is not a class below %d", label, i, numClasses)
+		}
+		targets[i] = make([]float64, numClasses)
+		targets[i][int(label)] = 1
+	}
+	return NewSliceDataset(inputs, targets), nil
+}
+
+// LoadMNIST reads an MNIST-style pair of IDX files, e.g.
+// train-images-idx3-ubyte.gz and train-labels-idx1-ubyte.gz, into a dataset
+// of 784 inputs in [0, 1] per image, with targets as in NewIDXDataset
+// (numClasses is 10 for one-hot digits, 0 for the digit itself).
+func LoadMNIST(imagesPath, labelsPath string, numClasses int) (*SliceDataset, error) {
+	images, err := OpenIDX(imagesPath)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := OpenIDX(labelsPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewIDXDataset(images, labels, numClasses)
+}
@@ -0,0 +1,172 @@
+package data
+
+import "math/rand"
+
+// Batch is a group of examples processed together in one optimizer step.
+type Batch []Example
+
+// Loader splits a Dataset into mini-batches, drawing a new random order of
+// the examples every epoch when Shuffle is set. By default the order comes
+// from the global random source; after SetSeed it is derived from the seed
+// and the epoch number alone, so any epoch can be reproduced (see SetEpoch).
+type Loader struct {
+	Dataset   Dataset
+	BatchSize int  // Examples per batch; 0 puts the whole dataset in one batch
+	Shuffle   bool // Reorder the examples every epoch
+	DropLast  bool // Skip a final batch smaller than BatchSize
+
+	// Sampler, if set, chooses the examples of every epoch instead of a pass
+	// over the whole dataset (e.g. a WeightedSampler); Shuffle is then ignored.
+	Sampler Sampler
+
+	// Transform, if set, is applied to every example as it is served (e.g.
+	// augmentation built with Compose); the dataset itself is not changed.
+	Transform Transform
+	// BatchTransform, if set, is applied to every batch after Transform
+	// (e.g. Mixup).
+	BatchTransform BatchTransform
+
+	seed   int64
+	seeded bool
+	epoch  int   // Epoch served by the next call to Batches
+	order  []int // Order of the examples in the last epoch served
+}
+
+// LoaderState is the part of a Loader that determines the batches it serves:
+// with a seed, Seed and Epoch fix the order of every following epoch.
+type LoaderState struct {
+	Seed   int64 `json:"seed"`
+	Seeded bool  `json:"seeded"`
+	Epoch  int   `json:"epoch"`           // Epoch served by the next call to Batches
+	Order  []int `json:"order,omitempty"` // Order of the examples in the last epoch served, for inspection
+}
+
+// NewLoader creates a shuffling Loader over ds with the given batch size.
+func NewLoader(ds Dataset, batchSize int) *Loader {
+	return &Loader{
+		Dataset:   ds,
+		BatchSize: batchSize,
+		Shuffle:   true,
+	}
+}
+
+// SetSeed makes the shuffled order of every epoch a function of seed and the epoch number.
+func (l *Loader) SetSeed(seed int64) {
+	l.seed, l.seeded = seed, true
+}
+
+// State returns the seed, epoch and last order of the loader.
+func (l *Loader) State() LoaderState {
+	return LoaderState{Seed: l.seed, Seeded: l.seeded, Epoch: l.epoch, Order: append([]int(nil), l.order...)}
+}
+
+// LoadState restores a state returned by State.
+func (l *Loader) LoadState(s LoaderState) {
+	l.seed, l.seeded, l.epoch = s.Seed, s.Seeded, s.Epoch
+	l.order = append([]int(nil), s.Order...)
+}
+
+// Order returns the order in which the examples were served in the last epoch.
+func (l *Loader) Order() []int {
+	return l.order
+}
+
+// SetEpoch sets the epoch whose batches the next call to Batches returns.
+// With a seed this reproduces that epoch's order exactly, e.g. when resuming training.
+func (l *Loader) SetEpoch(epoch int) {
+	l.epoch = epoch
+}
+
+// Batches returns the batches of one epoch and advances to the next epoch.
+func (l *Loader) Batches() []Batch {
+	var batches []Batch
+	l.next()(func(b Batch) bool {
+		batches = append(batches, b)
+		return true
+	})
+	return batches
+}
+
+// next advances to the next epoch and returns a function that builds the
+// batches of the epoch just started one at a time and passes them to yield,
+// stopping early if yield returns false.
+func (l *Loader) next() func(yield func(Batch) bool) {
+	rng := l.rng()
+	order := l.epochOrder(rng)
+	l.epoch++
+	l.order = order
+
+	size := l.BatchSize
+	if size <= 0 {
+		size = len(order)
+	}
+	return func(yield func(Batch) bool) {
+		for start := 0; start < len(order); start += size {
+			end := min(start+size, len(order))
+			if l.DropLast && end-start < size {
+				return
+			}
+			batch := make(Batch, end-start)
+			for i, idx := range order[start:end] {
+				batch[i] = l.Dataset.Get(idx)
+				if l.Transform != nil {
+					batch[i] = l.Transform(batch[i], rng)
+				}
+			}
+			if l.BatchTransform != nil {
+				batch = l.BatchTransform(batch, rng)
+			}
+			if !yield(batch) {
+				return
+			}
+		}
+	}
+}
+
+// rng returns the generator of the current epoch: derived from the seed and
+// the epoch number if the loader is seeded, seeded from the global source otherwise.
+func (l *Loader) rng() *rand.Rand {
+	if l.seeded {
+		return rand.New(rand.NewSource(l.seed + int64(l.epoch)))
+	}
+	return rand.New(rand.NewSource(rand.Int63()))
+}
+
+// epochOrder returns the indices of the examples of the current epoch, in order.
+func (l *Loader) epochOrder(rng *rand.Rand) []int {
+	if l.Sampler != nil {
+		return l.Sampler.Sample(rng)
+	}
+
+	order := make([]int, l.Dataset.Len())
+	for i := range order {
+		order[i] = i
+	}
+	if l.Shuffle {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	return order
+}
+
+// numExamples returns the number of examples served per epoch.
+func (l *Loader) numExamples() int {
+	if l.Sampler != nil {
+		return l.Sampler.Len()
+	}
+	return l.Dataset.Len()
+}
+
+// NumBatches returns the number of batches per epoch.
+func (l *Loader) NumBatches() int {
+	n := l.numExamples()
+	if l.BatchSize <= 0 {
+		if n == 0 {
+			return 0
+		}
+		return 1
+	}
+	if l.DropLast {
+		return n / l.BatchSize
+	}
+	return (n + l.BatchSize - 1) / l.BatchSize
+}
@@ -0,0 +1,100 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Rmehta-sudo/neural-net/labels"
+)
+
+// BatchTransform turns a batch into a (randomly) modified batch, drawing any
+// randomness from r. Like a Transform it must not modify the examples it is
+// given. Set Loader.BatchTransform to apply one to every batch served.
+type BatchTransform func(b Batch, r *rand.Rand) Batch
+
+// Mixup returns a BatchTransform implementing mixup: every example of a batch
+// is blended with another example of the same batch (a random permutation),
+// inputs and targets alike, as lambda * x_i + (1 - lambda) * x_j with lambda
+// drawn from Beta(alpha, alpha) once per batch. Training on these convex
+// combinations regularizes the model towards linear behaviour between
+// examples; alpha around 0.2 to 0.4 is typical.
+//
+// The blended targets are soft, so the loss must accept target vectors, such
+// as losses.MSELoss or losses.CrossEntropyLoss. If numClasses is positive,
+// targets are single class indices which are one-hot encoded before mixing;
+// otherwise they are mixed as they are.
+func Mixup(alpha float64, numClasses int) BatchTransform {
+	if alpha <= 0 {
+		panic(fmt.Sprintf("data: mixup alpha %v must be positive", alpha))
+	}
+	return func(b Batch, r *rand.Rand) Batch {
+		lambda := betaSample(r, alpha, alpha)
+		perm := r.Perm(len(b))
+		out := make(Batch, len(b))
+		for i, ex := range b {
+			other := b[perm[i]]
+			out[i] = Example{
+				Input:  blend(ex.Input, other.Input, lambda),
+				Target: blend(mixupTarget(ex.Target, numClasses), mixupTarget(other.Target, numClasses), lambda),
+			}
+		}
+		return out
+	}
+}
+
+// mixupTarget returns target one-hot encoded if numClasses is positive.
+func mixupTarget(target []float64, numClasses int) []float64 {
+	if numClasses <= 0 {
+		return target
+	}
+	if len(target) != 1 {
+		panic(fmt.Sprintf("data: mixup expects a single class index as target, got %d values", len(target)))
+	}
+	c := int(target[0])
+	if c < 0 || c >= numClasses {
+		panic(fmt.Sprintf("data: class %d outside [0, %d)", c, numClasses))
+	}
+	return labels.OneHot(c, numClasses)
+}
+
+// blend returns lambda * a + (1 - lambda) * b.
+func blend(a, b []float64, lambda float64) []float64 {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("data: cannot mix vectors of lengths %d and %d", len(a), len(b)))
+	}
+	out := make([]float64, len(a))
+	for i := range a {
+		out[i] = lambda*a[i] + (1-lambda)*b[i]
+	}
+	return out
+}
+
+// betaSample draws from the Beta(a, b) distribution as X / (X + Y) with
+// X ~ Gamma(a) and Y ~ Gamma(b).
+func betaSample(r *rand.Rand, a, b float64) float64 {
+	x := gammaSample(r, a)
+	return x / (x + gammaSample(r, b))
+}
+
+// gammaSample draws from the Gamma(shape, 1) distribution with the method of
+// Marsaglia and Tsang, boosting shapes below 1 by Gamma(shape+1) * U^(1/shape).
+func gammaSample(r *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return gammaSample(r, shape+1) * math.Pow(r.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := r.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := r.Float64()
+		if math.Log(u) < x*x/2+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package data
+
+import "context"
+
+// Prefetch serves the batches of the loader's next epoch through a channel,
+// prepared ahead of time on background goroutines so the consumer never waits
+// for data: one goroutine loads and transforms up to depth batches ahead, the
+// way Batches does, and a second passes them through convert (e.g. turning
+// the floats into engine Values) into the returned channel, which also
+// buffers depth results. The batches arrive in order and, for a seeded
+// loader, are identical to those Batches would return.
+//
+// The loader advances to the next epoch immediately. It must not be modified
+// until the channel is closed, which happens after the last batch or, if ctx
+// is cancelled first, once the goroutines have stopped; the Dataset must
+// allow Get while the consumer runs.
+func Prefetch[T any](ctx context.Context, l *Loader, depth int, convert func(Batch) T) <-chan T {
+	depth = max(depth, 1)
+	serve := l.next()
+
+	loaded := make(chan Batch, depth)
+	go func() {
+		defer close(loaded)
+		serve(func(b Batch) bool {
+			select {
+			case loaded <- b:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	out := make(chan T, depth)
+	go func() {
+		defer close(out)
+		for b := range loaded {
+			select {
+			case out <- convert(b):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
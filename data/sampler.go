@@ -0,0 +1,100 @@
+package data
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Sampler chooses the examples of an epoch, and their order, as indices into
+// a Loader's dataset. Setting Loader.Sampler replaces the plain (shuffled)
+// pass over every example.
+type Sampler interface {
+	Sample(r *rand.Rand) []int // Indices of one epoch, drawn from r
+	Len() int                  // Number of indices per epoch
+}
+
+// WeightedSampler draws NumSamples examples per epoch with replacement, each
+// with probability proportional to its weight. Giving rare classes larger
+// weights oversamples them, so that batches are balanced even when the
+// dataset is not.
+type WeightedSampler struct {
+	Weights    []float64 // Non-negative weight of every example
+	NumSamples int       // Examples per epoch; 0 means len(Weights)
+}
+
+// NewWeightedSampler creates a sampler drawing as many examples per epoch as
+// there are weights.
+func NewWeightedSampler(weights []float64) *WeightedSampler {
+	total := 0.0
+	for i, w := range weights {
+		if w < 0 {
+			panic(fmt.Sprintf("data: negative sampling weight %v for example %d", w, i))
+		}
+		total += w
+	}
+	if total == 0 {
+		panic("data: sampling weights sum to zero")
+	}
+	return &WeightedSampler{Weights: weights}
+}
+
+// NewClassWeightedSampler creates a sampler that weights every example of ds
+// by the weight of its class, as given by label. With nil classWeights the
+// balanced weights of ClassWeights are used, so every class is drawn equally
+// often on average.
+func NewClassWeightedSampler(ds Dataset, label func(Example) int, classWeights map[int]float64) *WeightedSampler {
+	if classWeights == nil {
+		classWeights = ClassWeights(ds, label)
+	}
+	weights := make([]float64, ds.Len())
+	for i := range weights {
+		c := label(ds.Get(i))
+		w, ok := classWeights[c]
+		if !ok {
+			panic(fmt.Sprintf("data: no sampling weight for class %d", c))
+		}
+		weights[i] = w
+	}
+	return NewWeightedSampler(weights)
+}
+
+// ClassWeights returns balanced class weights for ds: n / (k * count(c)) for
+// n examples in k classes, so that rare classes weigh more. They also suit
+// weighting the loss of each class.
+func ClassWeights(ds Dataset, label func(Example) int) map[int]float64 {
+	counts := map[int]int{}
+	for i := 0; i < ds.Len(); i++ {
+		counts[label(ds.Get(i))]++
+	}
+	weights := make(map[int]float64, len(counts))
+	for c, count := range counts {
+		weights[c] = float64(ds.Len()) / float64(len(counts)*count)
+	}
+	return weights
+}
+
+// Len returns the number of examples drawn per epoch.
+func (s *WeightedSampler) Len() int {
+	if s.NumSamples > 0 {
+		return s.NumSamples
+	}
+	return len(s.Weights)
+}
+
+// Sample draws the indices of one epoch.
+func (s *WeightedSampler) Sample(r *rand.Rand) []int {
+	cumulative := make([]float64, len(s.Weights))
+	total := 0.0
+	for i, w := range s.Weights {
+		total += w
+		cumulative[i] = total
+	}
+
+	indices := make([]int, s.Len())
+	for i := range indices {
+		u := r.Float64() * total
+		indices[i] = sort.Search(len(cumulative), func(j int) bool { return cumulative[j] > u })
+	}
+	return indices
+}
@@ -0,0 +1,261 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// Scaler rescales feature vectors with statistics learned from training data,
+// so the same normalization can be applied at serving time and undone on
+// outputs. Scalers are plain structs that encode to JSON; SaveScaler and
+// LoadScaler also record which kind of scaler was saved.
+type Scaler interface {
+	// Fit learns the statistics of every column of rows.
+	Fit(rows [][]float64) error
+	// Transform returns x rescaled; x is not modified.
+	Transform(x []float64) []float64
+	// InverseTransform undoes Transform.
+	InverseTransform(x []float64) []float64
+}
+
+// StandardScaler rescales every feature to zero mean and unit variance.
+// Constant features are only centred.
+type StandardScaler struct {
+	Mean []float64 `json:"mean"`
+	Std  []float64 `json:"std"`
+}
+
+// Fit computes the mean and (population) standard deviation of every column.
+func (s *StandardScaler) Fit(rows [][]float64) error {
+	width, err := checkRows(rows)
+	if err != nil {
+		return err
+	}
+	s.Mean, s.Std = make([]float64, width), make([]float64, width)
+	n := float64(len(rows))
+	for _, row := range rows {
+		for j, x := range row {
+			s.Mean[j] += x / n
+		}
+	}
+	for _, row := range rows {
+		for j, x := range row {
+			s.Std[j] += (x - s.Mean[j]) * (x - s.Mean[j]) / n
+		}
+	}
+	for j := range s.Std {
+		s.Std[j] = math.Sqrt(s.Std[j])
+	}
+	return nil
+}
+
+// Transform returns (x - Mean) / Std.
+func (s *StandardScaler) Transform(x []float64) []float64 {
+	checkWidth(x, len(s.Mean))
+	out := make([]float64, len(x))
+	for j, v := range x {
+		out[j] = v - s.Mean[j]
+		if s.Std[j] > 0 {
+			out[j] /= s.Std[j]
+		}
+	}
+	return out
+}
+
+// InverseTransform returns x * Std + Mean.
+func (s *StandardScaler) InverseTransform(x []float64) []float64 {
+	checkWidth(x, len(s.Mean))
+	out := make([]float64, len(x))
+	for j, v := range x {
+		if s.Std[j] > 0 {
+			v *= s.Std[j]
+		}
+		out[j] = v + s.Mean[j]
+	}
+	return out
+}
+
+// MinMaxScaler rescales every feature linearly from its range in the training
+// data to [Low, High], or [0, 1] if both are zero. Constant features map to Low.
+// Low and High must differ unless both are zero.
+type MinMaxScaler struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+
+	Min []float64 `json:"min"`
+	Max []float64 `json:"max"`
+}
+
+// NewMinMaxScaler creates a MinMaxScaler to the range [low, high].
+func NewMinMaxScaler(low, high float64) *MinMaxScaler {
+	return &MinMaxScaler{Low: low, High: high}
+}
+
+// Fit computes the minimum and maximum of every column.
+func (s *MinMaxScaler) Fit(rows [][]float64) error {
+	if err := s.checkBounds(); err != nil {
+		return err
+	}
+	if _, err := checkRows(rows); err != nil {
+		return err
+	}
+	s.Min = append([]float64(nil), rows[0]...)
+	s.Max = append([]float64(nil), rows[0]...)
+	for _, row := range rows[1:] {
+		for j, x := range row {
+			s.Min[j] = math.Min(s.Min[j], x)
+			s.Max[j] = math.Max(s.Max[j], x)
+		}
+	}
+	return nil
+}
+
+// checkBounds returns an error if the target range is empty.
+func (s *MinMaxScaler) checkBounds() error {
+	if low, high := s.bounds(); low == high {
+		return fmt.Errorf("data: min-max scaler range [%v, %v] is empty", low, high)
+	}
+	return nil
+}
+
+// bounds returns the target range.
+func (s *MinMaxScaler) bounds() (low, high float64) {
+	if s.Low == 0 && s.High == 0 {
+		return 0, 1
+	}
+	return s.Low, s.High
+}
+
+// Transform maps [Min, Max] to [Low, High] in every column.
+func (s *MinMaxScaler) Transform(x []float64) []float64 {
+	checkWidth(x, len(s.Min))
+	low, high := s.bounds()
+	out := make([]float64, len(x))
+	for j, v := range x {
+		out[j] = low
+		if span := s.Max[j] - s.Min[j]; span > 0 {
+			out[j] += (v - s.Min[j]) / span * (high - low)
+		}
+	}
+	return out
+}
+
+// InverseTransform maps [Low, High] back to [Min, Max] in every column. With
+// an empty range [Low, High] every value maps to Min.
+func (s *MinMaxScaler) InverseTransform(x []float64) []float64 {
+	checkWidth(x, len(s.Min))
+	low, high := s.bounds()
+	out := make([]float64, len(x))
+	for j, v := range x {
+		out[j] = s.Min[j]
+		if high != low {
+			out[j] += (v - low) / (high - low) * (s.Max[j] - s.Min[j])
+		}
+	}
+	return out
+}
+
+// checkRows returns the width of rows, or an error if there are none or they
+// differ in length.
+func checkRows(rows [][]float64) (int, error) {
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("data: cannot fit a scaler to no rows")
+	}
+	for i, row := range rows {
+		if len(row) != len(rows[0]) {
+			return 0, fmt.Errorf("data: row %d has %d values, expected %d", i, len(row), len(rows[0]))
+		}
+	}
+	return len(rows[0]), nil
+}
+
+// checkWidth panics if x does not have the width the scaler was fitted to.
+func checkWidth(x []float64, width int) {
+	if len(x) != width {
+		panic(fmt.Sprintf("data: scaler fitted to %d features, got %d", width, len(x)))
+	}
+}
+
+// Inputs returns the input vector of every example of ds, e.g. to fit a
+// Scaler to the training data.
+func Inputs(ds Dataset) [][]float64 {
+	rows := make([][]float64, ds.Len())
+	for i := range rows {
+		rows[i] = ds.Get(i).Input
+	}
+	return rows
+}
+
+// ScaleInputs returns a Transform applying s to the input of every example,
+// to set as Loader.Transform (or compose with augmentation).
+func ScaleInputs(s Scaler) Transform {
+	return func(ex Example, r *rand.Rand) Example {
+		return Example{Input: s.Transform(ex.Input), Target: ex.Target}
+	}
+}
+
+// savedScaler is the JSON form written by SaveScaler.
+type savedScaler struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params"`
+}
+
+// SaveScaler writes s to w as JSON tagged with its kind ("standard" or
+// "minmax"), to be stored next to the model and read back with LoadScaler.
+func SaveScaler(w io.Writer, s Scaler) error {
+	var kind string
+	switch s.(type) {
+	case *StandardScaler:
+		kind = "standard"
+	case *MinMaxScaler:
+		kind = "minmax"
+	default:
+		return fmt.Errorf("data: cannot save scaler of type %T", s)
+	}
+	params, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("data: encoding scaler: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(savedScaler{Type: kind, Params: params})
+}
+
+// LoadScaler reads a Scaler written by SaveScaler from r. It returns an error
+// if the statistics of the columns differ in number or the min-max range is
+// empty.
+func LoadScaler(r io.Reader) (Scaler, error) {
+	var saved savedScaler
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return nil, fmt.Errorf("data: decoding scaler: %w", err)
+	}
+	var s Scaler
+	switch saved.Type {
+	case "standard":
+		s = &StandardScaler{}
+	case "minmax":
+		s = &MinMaxScaler{}
+	default:
+		return nil, fmt.Errorf("data: unknown scaler type %q", saved.Type)
+	}
+	if err := json.Unmarshal(saved.Params, s); err != nil {
+		return nil, fmt.Errorf("data: decoding %s scaler: %w", saved.Type, err)
+	}
+	switch s := s.(type) {
+	case *StandardScaler:
+		if len(s.Mean) != len(s.Std) {
+			return nil, fmt.Errorf("data: standard scaler has %d means and %d deviations", len(s.Mean), len(s.Std))
+		}
+	case *MinMaxScaler:
+		if len(s.Min) != len(s.Max) {
+			return nil, fmt.Errorf("data: min-max scaler has %d minimums and %d maximums", len(s.Min), len(s.Max))
+		}
+		if err := s.checkBounds(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
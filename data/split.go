@@ -0,0 +1,124 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Subset is a view of selected examples of another Dataset.
+type Subset struct {
+	Dataset Dataset
+	Indices []int // Indices into Dataset, in the order they are served
+}
+
+// Len returns the number of selected examples.
+func (s *Subset) Len() int {
+	return len(s.Indices)
+}
+
+// Get returns the i-th selected example.
+func (s *Subset) Get(i int) Example {
+	return s.Dataset.Get(s.Indices[i])
+}
+
+// Split randomly partitions ds into a training and a validation subset, with
+// valFraction of the examples (rounded) in the validation subset. The same
+// seed always produces the same partition.
+func Split(ds Dataset, valFraction float64, seed int64) (train, val *Subset) {
+	if valFraction < 0 || valFraction > 1 {
+		panic(fmt.Sprintf("data: validation fraction %v outside [0, 1]", valFraction))
+	}
+	order := rand.New(rand.NewSource(seed)).Perm(ds.Len())
+	numVal := int(math.Round(valFraction * float64(ds.Len())))
+	return &Subset{Dataset: ds, Indices: order[numVal:]},
+		&Subset{Dataset: ds, Indices: order[:numVal]}
+}
+
+// StratifiedSplit partitions ds like Split but separately within every class,
+// so the training and validation subsets keep the class proportions of ds.
+// label returns the class of an example. Every class with at least two
+// examples contributes at least one to each subset when 0 < valFraction < 1,
+// so small classes never vanish from validation.
+func StratifiedSplit(ds Dataset, valFraction float64, seed int64, label func(Example) int) (train, val *Subset) {
+	if valFraction < 0 || valFraction > 1 {
+		panic(fmt.Sprintf("data: validation fraction %v outside [0, 1]", valFraction))
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	var classes []int // In order of first appearance, for a deterministic result
+	byClass := map[int][]int{}
+	for i := 0; i < ds.Len(); i++ {
+		c := label(ds.Get(i))
+		if _, ok := byClass[c]; !ok {
+			classes = append(classes, c)
+		}
+		byClass[c] = append(byClass[c], i)
+	}
+
+	train, val = &Subset{Dataset: ds}, &Subset{Dataset: ds}
+	for _, c := range classes {
+		idx := byClass[c]
+		rng.Shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+
+		numVal := int(math.Round(valFraction * float64(len(idx))))
+		if len(idx) >= 2 && valFraction > 0 && valFraction < 1 {
+			numVal = max(1, min(numVal, len(idx)-1))
+		}
+		val.Indices = append(val.Indices, idx[:numVal]...)
+		train.Indices = append(train.Indices, idx[numVal:]...)
+	}
+
+	// Mix the classes so neither subset is sorted by class
+	for _, s := range []*Subset{train, val} {
+		rng.Shuffle(len(s.Indices), func(i, j int) { s.Indices[i], s.Indices[j] = s.Indices[j], s.Indices[i] })
+	}
+	return train, val
+}
+
+// Fold is one train/validation partition of a k-fold split.
+type Fold struct {
+	Train, Val *Subset
+}
+
+// KFold randomly partitions ds into k folds of (almost) equal size and returns,
+// for every fold, that fold as validation subset and the rest as training
+// subset. The same seed always produces the same folds.
+func KFold(ds Dataset, k int, seed int64) []Fold {
+	if k < 2 || k > ds.Len() {
+		panic(fmt.Sprintf("data: cannot split %d examples into %d folds", ds.Len(), k))
+	}
+	order := rand.New(rand.NewSource(seed)).Perm(ds.Len())
+
+	folds := make([]Fold, k)
+	for f := range folds {
+		start, end := f*len(order)/k, (f+1)*len(order)/k
+		train := append(append([]int(nil), order[:start]...), order[end:]...)
+		folds[f] = Fold{
+			Train: &Subset{Dataset: ds, Indices: train},
+			Val:   &Subset{Dataset: ds, Indices: order[start:end]},
+		}
+	}
+	return folds
+}
+
+// Bootstrap draws a bootstrap resample of ds: as many examples as ds has,
+// chosen uniformly with replacement using seed. The examples never drawn,
+// about 37% of them, form the out-of-bag subset, a free validation set.
+func Bootstrap(ds Dataset, seed int64) (sample, outOfBag *Subset) {
+	rng := rand.New(rand.NewSource(seed))
+	n := ds.Len()
+	drawn := make([]bool, n)
+	sample, outOfBag = &Subset{Dataset: ds, Indices: make([]int, n)}, &Subset{Dataset: ds}
+	for i := range sample.Indices {
+		idx := rng.Intn(n)
+		sample.Indices[i] = idx
+		drawn[idx] = true
+	}
+	for i, d := range drawn {
+		if !d {
+			outOfBag.Indices = append(outOfBag.Indices, i)
+		}
+	}
+	return sample, outOfBag
+}
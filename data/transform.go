@@ -0,0 +1,68 @@
+package data
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Transform turns an example into a (randomly) modified copy, drawing any
+// randomness from r. Transforms must not modify the example they are given,
+// which belongs to the dataset. Set Loader.Transform to apply one to every
+// example as it is loaded, e.g. for data augmentation.
+type Transform func(ex Example, r *rand.Rand) Example
+
+// Compose chains transforms, applying them in the given order.
+func Compose(transforms ...Transform) Transform {
+	return func(ex Example, r *rand.Rand) Example {
+		for _, t := range transforms {
+			ex = t(ex, r)
+		}
+		return ex
+	}
+}
+
+// mapInput returns a copy of ex whose input features are f(i, x) for every feature x.
+func mapInput(ex Example, f func(i int, x float64) float64) Example {
+	in := make([]float64, len(ex.Input))
+	for i, x := range ex.Input {
+		in[i] = f(i, x)
+	}
+	return Example{Input: in, Target: ex.Target}
+}
+
+// Jitter adds independent Gaussian noise with the given standard deviation to
+// every input feature.
+func Jitter(stddev float64) Transform {
+	return func(ex Example, r *rand.Rand) Example {
+		return mapInput(ex, func(_ int, x float64) float64 { return x + r.NormFloat64()*stddev })
+	}
+}
+
+// Scale multiplies all input features of an example by one factor drawn
+// uniformly from [min, max].
+func Scale(min, max float64) Transform {
+	if min > max {
+		panic(fmt.Sprintf("data: scale range [%v, %v] is empty", min, max))
+	}
+	return func(ex Example, r *rand.Rand) Example {
+		factor := min + r.Float64()*(max-min)
+		return mapInput(ex, func(_ int, x float64) float64 { return x * factor })
+	}
+}
+
+// FeatureDropout sets every input feature to zero with probability p, as if
+// it were missing. Unlike dropout inside a network the remaining features are
+// not rescaled.
+func FeatureDropout(p float64) Transform {
+	if p < 0 || p > 1 {
+		panic(fmt.Sprintf("data: feature dropout probability %v outside [0, 1]", p))
+	}
+	return func(ex Example, r *rand.Rand) Example {
+		return mapInput(ex, func(_ int, x float64) float64 {
+			if r.Float64() < p {
+				return 0
+			}
+			return x
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package engine
+
+import "fmt"
+
+// Activation selects the non-linearity a Neuron applies to its weighted sum.
+// The zero value is Tanh, so neurons built without an explicit activation keep
+// the package's original behaviour.
+type Activation int
+
+const (
+	Tanh    Activation = iota // Hyperbolic tangent (default)
+	Linear                    // Identity: no non-linearity, e.g. for logits or regression outputs
+	ReLU                      // max(0, x)
+	Sigmoid                   // 1 / (1 + e^-x)
+)
+
+// String returns the lower-case name of the activation.
+func (act Activation) String() string {
+	switch act {
+	case Tanh:
+		return "tanh"
+	case Linear:
+		return "linear"
+	case ReLU:
+		return "relu"
+	case Sigmoid:
+		return "sigmoid"
+	}
+	return fmt.Sprintf("Activation(%d)", int(act))
+}
+
+// ParseActivation returns the activation with the given name, as returned by String.
+func ParseActivation(name string) (Activation, error) {
+	for _, act := range []Activation{Tanh, Linear, ReLU, Sigmoid} {
+		if act.String() == name {
+			return act, nil
+		}
+	}
+	return 0, fmt.Errorf("engine: unknown activation %q", name)
+}
+
+// MarshalText encodes the activation by name, e.g. in JSON.
+func (act Activation) MarshalText() ([]byte, error) {
+	return []byte(act.String()), nil
+}
+
+// UnmarshalText decodes an activation name written by MarshalText.
+func (act *Activation) UnmarshalText(text []byte) error {
+	a, err := ParseActivation(string(text))
+	if err != nil {
+		return err
+	}
+	*act = a
+	return nil
+}
+
+// Apply applies the activation function to v and returns the result.
+func (act Activation) Apply(v *Value) *Value {
+	switch act {
+	case Linear:
+		return v
+	case ReLU:
+		return v.ReLU()
+	case Sigmoid:
+		return v.Sigmoid()
+	}
+	return v.Tanh()
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Autoencoder pairs an encoder MLP with a mirrored decoder MLP that maps the
+// latent code back to the input space. With tied weights, every decoder layer
+// reuses the transposed weight Values of the matching encoder layer (only the
+// decoder biases are separate), halving the number of weights to train.
+type Autoencoder struct {
+	Encoder *MLP
+	Decoder *MLP
+	Tied    bool
+}
+
+// autoencoderConfig collects the settings applied by AutoencoderOptions.
+type autoencoderConfig struct {
+	tied             bool
+	outputActivation Activation
+	rand             *rand.Rand
+}
+
+// AutoencoderOption configures NewAutoencoder.
+type AutoencoderOption func(*autoencoderConfig)
+
+// WithTiedWeights makes the decoder share the transposed encoder weights.
+func WithTiedWeights() AutoencoderOption {
+	return func(c *autoencoderConfig) {
+		c.tied = true
+	}
+}
+
+// WithOutputActivation sets the activation of the decoder's final layer
+// (Linear by default, suitable for unbounded inputs; use Sigmoid for data in [0, 1]).
+func WithOutputActivation(act Activation) AutoencoderOption {
+	return func(c *autoencoderConfig) {
+		c.outputActivation = act
+	}
+}
+
+// WithRand draws the initial weights from r instead of the global math/rand source.
+func WithRand(r *rand.Rand) AutoencoderOption {
+	return func(c *autoencoderConfig) {
+		c.rand = r
+	}
+}
+
+// NewAutoencoder builds an autoencoder for inputs of size numIn.
+// encoderSizes lists the encoder layer sizes, the last one being the latent size;
+// the decoder mirrors them back up to numIn, e.g. sizes {8, 4, 2} with 16 inputs
+// give a 16 -> 8 -> 4 -> 2 encoder and a 2 -> 4 -> 8 -> 16 decoder.
+func NewAutoencoder(encoderSizes []int, numIn int, opts ...AutoencoderOption) *Autoencoder {
+	cfg := autoencoderConfig{outputActivation: Linear}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	decoderSizes := make([]int, len(encoderSizes))
+	for i := range encoderSizes[:len(encoderSizes)-1] {
+		decoderSizes[i] = encoderSizes[len(encoderSizes)-2-i]
+	}
+	decoderSizes[len(decoderSizes)-1] = numIn
+
+	ae := Autoencoder{
+		Encoder: NewMLPRand(encoderSizes, numIn, cfg.rand),
+		Decoder: NewMLPRand(decoderSizes, encoderSizes[len(encoderSizes)-1], cfg.rand),
+		Tied:    cfg.tied,
+	}
+	ae.Decoder.Layers[len(ae.Decoder.Layers)-1].WithActivation(cfg.outputActivation)
+	if ae.Tied {
+		ae.tie()
+	}
+	return &ae
+}
+
+// tie replaces the decoder weights by the transposed encoder weights:
+// weight i of neuron k in decoder layer j is weight k of neuron i in encoder layer L-1-j.
+func (ae *Autoencoder) tie() {
+	n := len(ae.Encoder.Layers)
+	for j, dec := range ae.Decoder.Layers {
+		enc := ae.Encoder.Layers[n-1-j]
+		for k, neuron := range dec.Neurons {
+			for i := range neuron.Weights {
+				neuron.Weights[i] = enc.Neurons[i].Weights[k]
+			}
+		}
+	}
+}
+
+// Clone returns a deep copy of the autoencoder, preserving weight tying.
+func (ae *Autoencoder) Clone() *Autoencoder {
+	c := Autoencoder{
+		Encoder: ae.Encoder.Clone(),
+		Decoder: ae.Decoder.Clone(),
+		Tied:    ae.Tied,
+	}
+	if c.Tied {
+		c.tie()
+	}
+	return &c
+}
+
+// String provides a formatted string representation of an Autoencoder.
+func (ae *Autoencoder) String() string {
+	return fmt.Sprintf("Autoencoder(tied=%t)\nEncoder: %sDecoder: %s", ae.Tied, ae.Encoder, ae.Decoder)
+}
+
+// Encode maps inputs to their latent code.
+func (ae *Autoencoder) Encode(ins []*Value) []*Value {
+	return ae.Encoder.Output(ins)
+}
+
+// Decode maps a latent code back to the input space.
+func (ae *Autoencoder) Decode(code []*Value) []*Value {
+	return ae.Decoder.Output(code)
+}
+
+// Reconstruct encodes and then decodes the inputs.
+func (ae *Autoencoder) Reconstruct(ins []*Value) []*Value {
+	return ae.Decode(ae.Encode(ins))
+}
+
+// Output is the same as Reconstruct, so an Autoencoder can be used as a Module.
+func (ae *Autoencoder) Output(ins []*Value) []*Value {
+	return ae.Reconstruct(ins)
+}
+
+// Parameters returns the encoder parameters followed by the decoder parameters.
+// With tied weights the shared weights are listed only once.
+func (ae *Autoencoder) Parameters() []*Value {
+	p := ae.Encoder.Parameters()
+	if !ae.Tied {
+		return append(p, ae.Decoder.Parameters()...)
+	}
+	for _, layer := range ae.Decoder.Layers {
+		for _, neuron := range layer.Neurons {
+			p = append(p, neuron.Bias) // Decoder weights belong to the encoder
+		}
+	}
+	return p
+}
+
+// ReconstructionLoss returns the mean squared error between the reconstruction
+// of ins and ins itself.
+func (ae *Autoencoder) ReconstructionLoss(ins []*Value) *Value {
+	rec := ae.Reconstruct(ins)
+	loss := NewValue(0.0, "")
+	for i := range rec {
+		diff := rec[i].Sub(ins[i])
+		loss = loss.Add(diff.Mul(diff))
+	}
+	loss = loss.Div(NewValue(float64(len(rec)), ""))
+	loss.Label = "reconstruction_loss"
+	return loss
+}
@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// BayesianLinear is a variational fully-connected layer: every weight and bias
+// is a Gaussian with a trainable mean and log-variance instead of a single
+// number. In training mode each forward pass samples fresh weights using the
+// reparameterization trick (w = mu + sigma * eps), so gradients reach both the
+// means and the variances; in evaluation mode the means are used directly.
+// Add KL() to the data loss to keep the weight distributions close to the prior.
+type BayesianLinear struct {
+	In, Out      int
+	WeightMu     [][]*Value // Out rows of In weight means
+	WeightLogVar [][]*Value // Out rows of In weight log-variances
+	BiasMu       []*Value
+	BiasLogVar   []*Value
+	PriorStd     float64    // Standard deviation of the zero-mean Gaussian prior
+	Activation   Activation // Applied to each output (Linear by default)
+	Training     bool
+	Rand         *rand.Rand // Source of the weight samples; nil uses the global math/rand source
+}
+
+// NewBayesianLinear creates a Bayesian layer with 'in' inputs and 'out' outputs in training mode.
+// Means are initialized with random values between -1 and 1 and log-variances to -6
+// (i.e. almost deterministic weights), with a standard normal prior.
+func NewBayesianLinear(in, out int) *BayesianLinear {
+	return NewBayesianLinearRand(in, out, nil)
+}
+
+// NewBayesianLinearRand is like NewBayesianLinear but draws the initial means
+// from r. The weight samples of the forward passes are drawn from Rand (see SetRand).
+func NewBayesianLinearRand(in, out int, r *rand.Rand) *BayesianLinear {
+	bl := BayesianLinear{
+		In:           in,
+		Out:          out,
+		WeightMu:     make([][]*Value, out),
+		WeightLogVar: make([][]*Value, out),
+		BiasMu:       make([]*Value, out),
+		BiasLogVar:   make([]*Value, out),
+		PriorStd:     1.0,
+		Activation:   Linear,
+		Training:     true,
+	}
+
+	for o := 0; o < out; o++ {
+		bl.WeightMu[o] = make([]*Value, in)
+		bl.WeightLogVar[o] = make([]*Value, in)
+		for i := 0; i < in; i++ {
+			bl.WeightMu[o][i] = NewValue(uniform(r), fmt.Sprintf("w_mu%d_%d", o+1, i+1))
+			bl.WeightLogVar[o][i] = NewValue(-6.0, fmt.Sprintf("w_logvar%d_%d", o+1, i+1))
+		}
+		bl.BiasMu[o] = NewValue(uniform(r), fmt.Sprintf("b_mu%d", o+1))
+		bl.BiasLogVar[o] = NewValue(-6.0, fmt.Sprintf("b_logvar%d", o+1))
+	}
+	return &bl
+}
+
+// Clone returns a deep copy of the layer's distributions and configuration.
+func (bl *BayesianLinear) Clone() *BayesianLinear {
+	c := *bl
+	c.WeightMu = make([][]*Value, len(bl.WeightMu))
+	c.WeightLogVar = make([][]*Value, len(bl.WeightLogVar))
+	for o := range bl.WeightMu {
+		c.WeightMu[o] = leafCopies(bl.WeightMu[o])
+		c.WeightLogVar[o] = leafCopies(bl.WeightLogVar[o])
+	}
+	c.BiasMu = leafCopies(bl.BiasMu)
+	c.BiasLogVar = leafCopies(bl.BiasLogVar)
+	return &c
+}
+
+// String provides a formatted string representation of a BayesianLinear layer.
+func (bl *BayesianLinear) String() string {
+	return fmt.Sprintf("BayesianLinear(in=%d, out=%d, prior_std=%.4f, activation=%s, training=%t)",
+		bl.In, bl.Out, bl.PriorStd, bl.Activation, bl.Training)
+}
+
+// SetRand makes the weight samples be drawn from r.
+func (bl *BayesianLinear) SetRand(r *rand.Rand) {
+	bl.Rand = r
+}
+
+// SetTraining enables (true) or disables (false) weight sampling.
+func (bl *BayesianLinear) SetTraining(training bool) {
+	bl.Training = training
+}
+
+// sample returns mu in evaluation mode, and mu + exp(logVar/2) * eps with
+// eps ~ N(0, 1) in training mode.
+func (bl *BayesianLinear) sample(mu, logVar *Value) *Value {
+	if !bl.Training {
+		return mu
+	}
+	sigma := logVar.Mul(NewValue(0.5, "")).Exp()
+	return mu.Add(sigma.Mul(NewValue(normal(bl.Rand), "eps")))
+}
+
+// Output computes the layer outputs with freshly sampled (training) or mean (evaluation) weights.
+func (bl *BayesianLinear) Output(ins []*Value) []*Value {
+	out := make([]*Value, bl.Out)
+	for o := 0; o < bl.Out; o++ {
+		sum := bl.sample(bl.BiasMu[o], bl.BiasLogVar[o])
+		for i := 0; i < bl.In; i++ {
+			w := bl.sample(bl.WeightMu[o][i], bl.WeightLogVar[o][i])
+			sum = sum.Add(w.Mul(ins[i]))
+		}
+		out[o] = bl.Activation.Apply(sum)
+		out[o].Label = fmt.Sprintf("bayes_output_%d", o+1)
+	}
+	return out
+}
+
+// Parameters returns all weight means and log-variances followed by the bias means and log-variances.
+func (bl *BayesianLinear) Parameters() []*Value {
+	var p []*Value
+	for o := range bl.WeightMu {
+		p = append(p, bl.WeightMu[o]...)
+		p = append(p, bl.WeightLogVar[o]...)
+	}
+	p = append(p, bl.BiasMu...)
+	return append(p, bl.BiasLogVar...)
+}
+
+// KL returns the Kullback-Leibler divergence between the layer's weight
+// distributions and the N(0, PriorStd^2) prior, summed over all weights and
+// biases, as a graph node that can be added to the loss:
+//
+//	KL = sum( (sigma^2 + mu^2) / (2 prior^2) - logVar/2 + log(prior) - 1/2 )
+func (bl *BayesianLinear) KL() *Value {
+	priorVar := bl.PriorStd * bl.PriorStd
+	constant := math.Log(bl.PriorStd) - 0.5
+
+	kl := NewValue(0.0, "")
+	term := func(mu, logVar *Value) {
+		varPlusMu2 := logVar.Exp().Add(mu.Mul(mu))
+		t := varPlusMu2.Mul(NewValue(1/(2*priorVar), "")).
+			Sub(logVar.Mul(NewValue(0.5, ""))).
+			Add(NewValue(constant, ""))
+		kl = kl.Add(t)
+	}
+	for o := range bl.WeightMu {
+		for i := range bl.WeightMu[o] {
+			term(bl.WeightMu[o][i], bl.WeightLogVar[o][i])
+		}
+		term(bl.BiasMu[o], bl.BiasLogVar[o])
+	}
+	kl.Label = "kl"
+	return kl
+}
+
+// PredictMC estimates the predictive mean and standard deviation of each output
+// of m by running 'samples' stochastic forward passes (Monte Carlo sampling).
+// m should be in training mode so that stochastic layers actually sample.
+func PredictMC(m Module, ins []*Value, samples int) ([]float64, []float64) {
+	var sum, sumSq []float64
+	for s := 0; s < samples; s++ {
+		out := m.Output(ins)
+		if sum == nil {
+			sum = make([]float64, len(out))
+			sumSq = make([]float64, len(out))
+		}
+		for i, o := range out {
+			sum[i] += o.Data
+			sumSq[i] += o.Data * o.Data
+		}
+	}
+
+	mean := make([]float64, len(sum))
+	std := make([]float64, len(sum))
+	for i := range sum {
+		mean[i] = sum[i] / float64(samples)
+		std[i] = math.Sqrt(math.Max(0, sumSq[i]/float64(samples)-mean[i]*mean[i]))
+	}
+	return mean, std
+}
@@ -0,0 +1,56 @@
+package engine
+
+import "fmt"
+
+// ModuleCloner is implemented by user-defined modules that know how to deep-copy
+// themselves, so CloneModule can handle them inside containers.
+type ModuleCloner interface {
+	CloneModule() Module
+}
+
+// CloneModule returns a deep copy of m: same architecture and parameter data,
+// but independent Values. It supports every module in this package and any
+// module implementing ModuleCloner, and panics for anything else.
+func CloneModule(m Module) Module {
+	switch m := m.(type) {
+	case *Layer:
+		return m.Clone()
+	case *MLP:
+		return m.Clone()
+	case *Sequential:
+		return m.Clone()
+	case *GaussianNoise:
+		return m.Clone()
+	case *Dropout:
+		return m.Clone()
+	case *MultiHead:
+		return m.Clone()
+	case *Siamese:
+		return m.Clone()
+	case *MoE:
+		return m.Clone()
+	case *BayesianLinear:
+		return m.Clone()
+	case *Autoencoder:
+		return m.Clone()
+	case *WeightNorm:
+		return m.Clone()
+	case *SpectralNorm:
+		return m.Clone()
+	case *DepthwiseConv2D:
+		return m.Clone()
+	case *PointwiseConv2D:
+		return m.Clone()
+	case *DepthwiseSeparableConv2D:
+		return m.Clone()
+	case *ConvTranspose2D:
+		return m.Clone()
+	case *Upsample:
+		return m.Clone()
+	case *Ensemble:
+		return m.Clone()
+	case ModuleCloner:
+		return m.CloneModule()
+	}
+	panic(fmt.Sprintf("engine: cannot clone module of type %T", m))
+}
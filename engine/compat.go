@@ -0,0 +1,169 @@
+package engine
+
+import "fmt"
+
+// CompatibleArch reports whether a and b have identical structure and parameter
+// shapes, so that parameters can be copied or averaged between them position by
+// position. Layers must also share their activations, and parameter-free
+// modules such as Upsample the shapes they map between. It returns nil if they are compatible and a descriptive error otherwise.
+// Modules this package does not know about are compared by type and parameter count.
+func CompatibleArch(a, b Module) error {
+	if err := compatibleArch(a, b); err != nil {
+		return fmt.Errorf("engine: incompatible architectures: %w", err)
+	}
+	return nil
+}
+
+// compatibleArch does the recursive work for CompatibleArch.
+func compatibleArch(a, b Module) error {
+	if fmt.Sprintf("%T", a) != fmt.Sprintf("%T", b) {
+		return fmt.Errorf("module types differ (%T vs %T)", a, b)
+	}
+
+	switch a := a.(type) {
+	case *Layer:
+		return compatibleLayers(a, b.(*Layer))
+	case *MLP:
+		b := b.(*MLP)
+		if len(a.Layers) != len(b.Layers) {
+			return fmt.Errorf("MLP has %d layers vs %d", len(a.Layers), len(b.Layers))
+		}
+		for i := range a.Layers {
+			if err := compatibleLayers(a.Layers[i], b.Layers[i]); err != nil {
+				return fmt.Errorf("layer %d: %w", i+1, err)
+			}
+		}
+		return nil
+	case *Sequential:
+		b := b.(*Sequential)
+		if len(a.Modules) != len(b.Modules) {
+			return fmt.Errorf("Sequential has %d modules vs %d", len(a.Modules), len(b.Modules))
+		}
+		for i := range a.Modules {
+			if err := compatibleArch(a.Modules[i], b.Modules[i]); err != nil {
+				return fmt.Errorf("module %d: %w", i+1, err)
+			}
+		}
+		return nil
+	case *MultiHead:
+		b := b.(*MultiHead)
+		if err := compatibleArch(a.Trunk, b.Trunk); err != nil {
+			return fmt.Errorf("trunk: %w", err)
+		}
+		if len(a.Heads) != len(b.Heads) {
+			return fmt.Errorf("MultiHead has %d heads vs %d", len(a.Heads), len(b.Heads))
+		}
+		for i := range a.Heads {
+			if err := compatibleArch(a.Heads[i], b.Heads[i]); err != nil {
+				return fmt.Errorf("head %d: %w", i+1, err)
+			}
+		}
+		return nil
+	case *Siamese:
+		return compatibleArch(a.Tower, b.(*Siamese).Tower)
+	case *MoE:
+		b := b.(*MoE)
+		if err := compatibleLayers(a.Gate, b.Gate); err != nil {
+			return fmt.Errorf("gate: %w", err)
+		}
+		if len(a.Experts) != len(b.Experts) {
+			return fmt.Errorf("MoE has %d experts vs %d", len(a.Experts), len(b.Experts))
+		}
+		for i := range a.Experts {
+			if err := compatibleArch(a.Experts[i], b.Experts[i]); err != nil {
+				return fmt.Errorf("expert %d: %w", i+1, err)
+			}
+		}
+		return nil
+	case *BayesianLinear:
+		b := b.(*BayesianLinear)
+		return sameDims("BayesianLinear", []int{a.In, a.Out}, []int{b.In, b.Out})
+	case *Autoencoder:
+		b := b.(*Autoencoder)
+		if a.Tied != b.Tied {
+			return fmt.Errorf("autoencoder weight tying differs (%t vs %t)", a.Tied, b.Tied)
+		}
+		if err := compatibleArch(a.Encoder, b.Encoder); err != nil {
+			return fmt.Errorf("encoder: %w", err)
+		}
+		if err := compatibleArch(a.Decoder, b.Decoder); err != nil {
+			return fmt.Errorf("decoder: %w", err)
+		}
+		return nil
+	case *WeightNorm:
+		return compatibleLayers(a.Layer, b.(*WeightNorm).Layer)
+	case *SpectralNorm:
+		return compatibleLayers(a.Layer, b.(*SpectralNorm).Layer)
+	case *DepthwiseConv2D:
+		b := b.(*DepthwiseConv2D)
+		return sameDims("DepthwiseConv2D",
+			[]int{a.Channels, a.Height, a.Width, a.KernelSize, a.Stride, a.Padding},
+			[]int{b.Channels, b.Height, b.Width, b.KernelSize, b.Stride, b.Padding})
+	case *PointwiseConv2D:
+		b := b.(*PointwiseConv2D)
+		return sameDims("PointwiseConv2D",
+			[]int{a.InChannels, a.OutChannels, a.Height, a.Width},
+			[]int{b.InChannels, b.OutChannels, b.Height, b.Width})
+	case *DepthwiseSeparableConv2D:
+		b := b.(*DepthwiseSeparableConv2D)
+		if err := compatibleArch(a.Depthwise, b.Depthwise); err != nil {
+			return err
+		}
+		return compatibleArch(a.Pointwise, b.Pointwise)
+	case *Ensemble:
+		b := b.(*Ensemble)
+		if len(a.Members) != len(b.Members) {
+			return fmt.Errorf("Ensemble has %d members vs %d", len(a.Members), len(b.Members))
+		}
+		for i := range a.Members {
+			if err := compatibleArch(a.Members[i], b.Members[i]); err != nil {
+				return fmt.Errorf("member %d: %w", i+1, err)
+			}
+		}
+		return nil
+	case *ConvTranspose2D:
+		b := b.(*ConvTranspose2D)
+		return sameDims("ConvTranspose2D",
+			[]int{a.InChannels, a.OutChannels, a.Height, a.Width, a.KernelSize, a.Stride, a.Padding},
+			[]int{b.InChannels, b.OutChannels, b.Height, b.Width, b.KernelSize, b.Stride, b.Padding})
+	case *Upsample:
+		b := b.(*Upsample)
+		return sameDims("Upsample",
+			[]int{a.Channels, a.Height, a.Width, a.Scale, int(a.Mode)},
+			[]int{b.Channels, b.Height, b.Width, b.Scale, int(b.Mode)})
+	case *Dropout, *GaussianNoise:
+		return nil // Keep the shape of any input; their rates do not affect the structure
+	}
+
+	if na, nb := len(a.Parameters()), len(b.Parameters()); na != nb {
+		return fmt.Errorf("%T has %d parameters vs %d", a, na, nb)
+	}
+	return nil
+}
+
+// compatibleLayers checks that two layers have the same number of neurons and
+// the same number of weights and activation per neuron.
+func compatibleLayers(a, b *Layer) error {
+	if len(a.Neurons) != len(b.Neurons) {
+		return fmt.Errorf("layer has %d neurons vs %d", len(a.Neurons), len(b.Neurons))
+	}
+	for i := range a.Neurons {
+		if na, nb := len(a.Neurons[i].Weights), len(b.Neurons[i].Weights); na != nb {
+			return fmt.Errorf("neuron %d has %d weights vs %d", i+1, na, nb)
+		}
+		if aa, ab := a.Neurons[i].Activation, b.Neurons[i].Activation; aa != ab {
+			return fmt.Errorf("neuron %d has %v activation vs %v", i+1, aa, ab)
+		}
+	}
+	return nil
+}
+
+// sameDims checks that two modules of the given kind share the same dimensions.
+func sameDims(kind string, a, b []int) error {
+	for i := range a {
+		if a[i] != b[i] {
+			return fmt.Errorf("%s dimensions differ (%v vs %v)", kind, a, b)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// ModelConfig is a declarative description of a feed-forward network, e.g.
+//
+//	{
+//	  "inputs": 4,
+//	  "seed": 42,
+//	  "init": "xavier",
+//	  "layers": [
+//	    {"type": "dense", "units": 16, "activation": "relu", "init": "he"},
+//	    {"type": "dropout", "rate": 0.2},
+//	    {"type": "dense", "units": 1, "activation": "linear"}
+//	  ]
+//	}
+//
+// It can be read from a file with BuildFromConfig or filled in by code, e.g.
+// to sweep over architectures.
+type ModelConfig struct {
+	Inputs int           `json:"inputs"`
+	Seed   *int64        `json:"seed,omitempty"` // Seeds initialization and stochastic layers; nil uses the global source
+	Init   string        `json:"init,omitempty"` // Default initialization of dense layers, see LayerConfig.Init
+	Layers []LayerConfig `json:"layers"`
+}
+
+// LayerConfig describes one layer of a ModelConfig. Type selects the layer
+// and which of the other fields apply:
+//
+//   - "dense": Units neurons with Activation ("tanh" by default, see
+//     ParseActivation) and Init, one of "uniform" (weights and biases drawn
+//     from U(-1, 1) as by NewLayer, the default), "xavier" (Glorot uniform,
+//     zero biases) or "he" (normal with variance 2/inputs, zero biases).
+//   - "dropout": a Dropout with Rate.
+//   - "noise": a GaussianNoise with Stddev.
+type LayerConfig struct {
+	Type       string  `json:"type"`
+	Units      int     `json:"units,omitempty"`
+	Activation string  `json:"activation,omitempty"`
+	Init       string  `json:"init,omitempty"`
+	Rate       float64 `json:"rate,omitempty"`
+	Stddev     float64 `json:"stddev,omitempty"`
+}
+
+// BuildFromConfig reads a JSON ModelConfig from r and builds the network it
+// describes, see ModelConfig.Build. Unknown fields are rejected, so typos in
+// a config file do not go unnoticed.
+func BuildFromConfig(r io.Reader) (Module, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var cfg ModelConfig
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("engine: decoding model config: %w", err)
+	}
+	return cfg.Build()
+}
+
+// Build creates the network described by the config: an *MLP if all layers
+// are dense, so it can be saved with MLP.Save, and a *Sequential otherwise.
+func (cfg ModelConfig) Build() (Module, error) {
+	if cfg.Inputs <= 0 {
+		return nil, fmt.Errorf("engine: model config needs a positive number of inputs")
+	}
+	if len(cfg.Layers) == 0 {
+		return nil, fmt.Errorf("engine: model config has no layers")
+	}
+	var r *rand.Rand
+	if cfg.Seed != nil {
+		r = rand.New(rand.NewSource(*cfg.Seed))
+	}
+
+	modules := make([]Module, len(cfg.Layers))
+	var layers []*Layer
+	numIn := cfg.Inputs
+	for i, lc := range cfg.Layers {
+		switch lc.Type {
+		case "dense":
+			layer, err := lc.dense(numIn, cfg.Init, r)
+			if err != nil {
+				return nil, fmt.Errorf("engine: layer %d: %w", i+1, err)
+			}
+			modules[i] = layer
+			layers = append(layers, layer)
+			numIn = lc.Units
+		case "dropout":
+			if lc.Rate < 0 || lc.Rate >= 1 {
+				return nil, fmt.Errorf("engine: layer %d: dropout rate %v outside [0, 1)", i+1, lc.Rate)
+			}
+			d := NewDropout(lc.Rate)
+			d.Rand = r
+			modules[i] = d
+		case "noise":
+			if lc.Stddev < 0 {
+				return nil, fmt.Errorf("engine: layer %d: negative noise stddev %v", i+1, lc.Stddev)
+			}
+			gn := NewGaussianNoise(lc.Stddev)
+			gn.Rand = r
+			modules[i] = gn
+		default:
+			return nil, fmt.Errorf("engine: layer %d: unknown layer type %q", i+1, lc.Type)
+		}
+	}
+
+	if len(layers) == len(modules) {
+		return &MLP{Layers: layers}, nil
+	}
+	return NewSequential(modules...), nil
+}
+
+// dense builds a dense layer with numIn inputs, initialized by lc.Init or, if
+// that is empty, by defaultInit.
+func (lc LayerConfig) dense(numIn int, defaultInit string, r *rand.Rand) (*Layer, error) {
+	if lc.Units <= 0 {
+		return nil, fmt.Errorf("dense layer needs a positive number of units")
+	}
+	act := Tanh
+	if lc.Activation != "" {
+		var err error
+		if act, err = ParseActivation(lc.Activation); err != nil {
+			return nil, err
+		}
+	}
+	init := lc.Init
+	if init == "" {
+		init = defaultInit
+	}
+
+	layer := NewLayerRand(numIn, lc.Units, r).WithActivation(act)
+	var draw func() float64
+	switch init {
+	case "", "uniform":
+		return layer, nil
+	case "xavier":
+		limit := math.Sqrt(6 / float64(numIn+lc.Units))
+		draw = func() float64 { return uniform(r) * limit }
+	case "he":
+		stddev := math.Sqrt(2 / float64(numIn))
+		draw = func() float64 { return normal(r) * stddev }
+	default:
+		return nil, fmt.Errorf("unknown initialization %q", init)
+	}
+	for _, neuron := range layer.Neurons {
+		for _, w := range neuron.Weights {
+			w.Data = draw()
+		}
+		neuron.Bias.Data = 0
+	}
+	return layer, nil
+}
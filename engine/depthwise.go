@@ -0,0 +1,244 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Convolution modules operate on flattened feature maps: a slice of
+// channels*height*width Values laid out channel by channel, row by row
+// (index c*H*W + y*W + x). Their outputs use the same layout.
+
+// convOutSize returns the spatial output size of a convolution along one axis.
+func convOutSize(in, kernel, stride, padding int) int {
+	return (in+2*padding-kernel)/stride + 1
+}
+
+// DepthwiseConv2D convolves every input channel with its own KxK kernel,
+// keeping channels separate. It is the cheap spatial half of a
+// depthwise-separable convolution.
+type DepthwiseConv2D struct {
+	Channels, Height, Width int
+	KernelSize              int
+	Stride, Padding         int
+	Kernels                 [][]*Value // One KernelSize*KernelSize kernel per channel
+	Biases                  []*Value   // One bias per channel
+}
+
+// NewDepthwiseConv2D creates a depthwise convolution over channels x height x width
+// inputs. Kernels and biases are initialized with random values between -1 and 1.
+func NewDepthwiseConv2D(channels, height, width, kernelSize, stride, padding int) *DepthwiseConv2D {
+	return NewDepthwiseConv2DRand(channels, height, width, kernelSize, stride, padding, nil)
+}
+
+// NewDepthwiseConv2DRand is like NewDepthwiseConv2D but draws the initial
+// kernels and biases from r.
+func NewDepthwiseConv2DRand(channels, height, width, kernelSize, stride, padding int, r *rand.Rand) *DepthwiseConv2D {
+	dw := DepthwiseConv2D{
+		Channels:   channels,
+		Height:     height,
+		Width:      width,
+		KernelSize: kernelSize,
+		Stride:     stride,
+		Padding:    padding,
+		Kernels:    make([][]*Value, channels),
+		Biases:     make([]*Value, channels),
+	}
+
+	for c := range dw.Kernels {
+		dw.Kernels[c] = make([]*Value, kernelSize*kernelSize)
+		for k := range dw.Kernels[c] {
+			dw.Kernels[c][k] = NewValue(uniform(r), fmt.Sprintf("k%d_%d", c+1, k+1))
+		}
+		dw.Biases[c] = NewValue(uniform(r), fmt.Sprintf("b%d", c+1))
+	}
+	return &dw
+}
+
+// Clone returns a deep copy of the depthwise convolution.
+func (dw *DepthwiseConv2D) Clone() *DepthwiseConv2D {
+	c := *dw
+	c.Kernels = make([][]*Value, len(dw.Kernels))
+	for i := range dw.Kernels {
+		c.Kernels[i] = leafCopies(dw.Kernels[i])
+	}
+	c.Biases = leafCopies(dw.Biases)
+	return &c
+}
+
+// String provides a formatted string representation of a DepthwiseConv2D.
+func (dw *DepthwiseConv2D) String() string {
+	outH, outW := dw.OutShape()
+	return fmt.Sprintf("DepthwiseConv2D(%dx%dx%d -> %dx%dx%d, kernel=%d, stride=%d, padding=%d)",
+		dw.Channels, dw.Height, dw.Width, dw.Channels, outH, outW, dw.KernelSize, dw.Stride, dw.Padding)
+}
+
+// OutShape returns the spatial size (height, width) of the output feature maps.
+func (dw *DepthwiseConv2D) OutShape() (int, int) {
+	return convOutSize(dw.Height, dw.KernelSize, dw.Stride, dw.Padding),
+		convOutSize(dw.Width, dw.KernelSize, dw.Stride, dw.Padding)
+}
+
+// Output convolves each channel of the flattened input with its own kernel.
+func (dw *DepthwiseConv2D) Output(ins []*Value) []*Value {
+	outH, outW := dw.OutShape()
+	out := make([]*Value, 0, dw.Channels*outH*outW)
+
+	for c := 0; c < dw.Channels; c++ {
+		for oy := 0; oy < outH; oy++ {
+			for ox := 0; ox < outW; ox++ {
+				sum := dw.Biases[c]
+				for ky := 0; ky < dw.KernelSize; ky++ {
+					for kx := 0; kx < dw.KernelSize; kx++ {
+						y := oy*dw.Stride + ky - dw.Padding
+						x := ox*dw.Stride + kx - dw.Padding
+						if y < 0 || y >= dw.Height || x < 0 || x >= dw.Width {
+							continue // Zero padding contributes nothing
+						}
+						in := ins[c*dw.Height*dw.Width+y*dw.Width+x]
+						sum = sum.Add(dw.Kernels[c][ky*dw.KernelSize+kx].Mul(in))
+					}
+				}
+				out = append(out, sum)
+			}
+		}
+	}
+	return out
+}
+
+// Parameters returns all kernels followed by the biases.
+func (dw *DepthwiseConv2D) Parameters() []*Value {
+	var p []*Value
+	for _, k := range dw.Kernels {
+		p = append(p, k...)
+	}
+	return append(p, dw.Biases...)
+}
+
+// PointwiseConv2D is a 1x1 convolution: it mixes channels at every spatial
+// position independently, using the same weights everywhere.
+type PointwiseConv2D struct {
+	InChannels, OutChannels int
+	Height, Width           int
+	Weights                 [][]*Value // OutChannels rows of InChannels weights
+	Biases                  []*Value   // One bias per output channel
+}
+
+// NewPointwiseConv2D creates a 1x1 convolution mapping inChannels to outChannels
+// over height x width feature maps. Weights and biases are initialized with
+// random values between -1 and 1.
+func NewPointwiseConv2D(inChannels, outChannels, height, width int) *PointwiseConv2D {
+	return NewPointwiseConv2DRand(inChannels, outChannels, height, width, nil)
+}
+
+// NewPointwiseConv2DRand is like NewPointwiseConv2D but draws the initial
+// weights and biases from r.
+func NewPointwiseConv2DRand(inChannels, outChannels, height, width int, r *rand.Rand) *PointwiseConv2D {
+	pw := PointwiseConv2D{
+		InChannels:  inChannels,
+		OutChannels: outChannels,
+		Height:      height,
+		Width:       width,
+		Weights:     make([][]*Value, outChannels),
+		Biases:      make([]*Value, outChannels),
+	}
+
+	for o := range pw.Weights {
+		pw.Weights[o] = make([]*Value, inChannels)
+		for i := range pw.Weights[o] {
+			pw.Weights[o][i] = NewValue(uniform(r), fmt.Sprintf("w%d_%d", o+1, i+1))
+		}
+		pw.Biases[o] = NewValue(uniform(r), fmt.Sprintf("b%d", o+1))
+	}
+	return &pw
+}
+
+// Clone returns a deep copy of the pointwise convolution.
+func (pw *PointwiseConv2D) Clone() *PointwiseConv2D {
+	c := *pw
+	c.Weights = make([][]*Value, len(pw.Weights))
+	for i := range pw.Weights {
+		c.Weights[i] = leafCopies(pw.Weights[i])
+	}
+	c.Biases = leafCopies(pw.Biases)
+	return &c
+}
+
+// String provides a formatted string representation of a PointwiseConv2D.
+func (pw *PointwiseConv2D) String() string {
+	return fmt.Sprintf("PointwiseConv2D(%dx%dx%d -> %dx%dx%d)",
+		pw.InChannels, pw.Height, pw.Width, pw.OutChannels, pw.Height, pw.Width)
+}
+
+// Output mixes the channels of the flattened input at every spatial position.
+func (pw *PointwiseConv2D) Output(ins []*Value) []*Value {
+	plane := pw.Height * pw.Width
+	out := make([]*Value, 0, pw.OutChannels*plane)
+
+	for o := 0; o < pw.OutChannels; o++ {
+		for pos := 0; pos < plane; pos++ {
+			sum := pw.Biases[o]
+			for i := 0; i < pw.InChannels; i++ {
+				sum = sum.Add(pw.Weights[o][i].Mul(ins[i*plane+pos]))
+			}
+			out = append(out, sum)
+		}
+	}
+	return out
+}
+
+// Parameters returns all weights followed by the biases.
+func (pw *PointwiseConv2D) Parameters() []*Value {
+	var p []*Value
+	for _, w := range pw.Weights {
+		p = append(p, w...)
+	}
+	return append(p, pw.Biases...)
+}
+
+// DepthwiseSeparableConv2D chains a DepthwiseConv2D and a PointwiseConv2D,
+// approximating a full convolution with far fewer parameters and multiplications.
+type DepthwiseSeparableConv2D struct {
+	Depthwise *DepthwiseConv2D
+	Pointwise *PointwiseConv2D
+}
+
+// NewDepthwiseSeparableConv2D creates a depthwise-separable convolution mapping
+// inChannels x height x width inputs to outChannels feature maps.
+func NewDepthwiseSeparableConv2D(inChannels, outChannels, height, width, kernelSize, stride, padding int) *DepthwiseSeparableConv2D {
+	return NewDepthwiseSeparableConv2DRand(inChannels, outChannels, height, width, kernelSize, stride, padding, nil)
+}
+
+// NewDepthwiseSeparableConv2DRand is like NewDepthwiseSeparableConv2D but
+// draws the initial kernels and biases of both convolutions from r.
+func NewDepthwiseSeparableConv2DRand(inChannels, outChannels, height, width, kernelSize, stride, padding int, r *rand.Rand) *DepthwiseSeparableConv2D {
+	dw := NewDepthwiseConv2DRand(inChannels, height, width, kernelSize, stride, padding, r)
+	outH, outW := dw.OutShape()
+	return &DepthwiseSeparableConv2D{
+		Depthwise: dw,
+		Pointwise: NewPointwiseConv2DRand(inChannels, outChannels, outH, outW, r),
+	}
+}
+
+// Clone returns a deep copy of both halves of the convolution.
+func (ds *DepthwiseSeparableConv2D) Clone() *DepthwiseSeparableConv2D {
+	return &DepthwiseSeparableConv2D{
+		Depthwise: ds.Depthwise.Clone(),
+		Pointwise: ds.Pointwise.Clone(),
+	}
+}
+
+// String provides a formatted string representation of a DepthwiseSeparableConv2D.
+func (ds *DepthwiseSeparableConv2D) String() string {
+	return fmt.Sprintf("DepthwiseSeparableConv2D(\n  %s\n  %s\n)", ds.Depthwise, ds.Pointwise)
+}
+
+// Output applies the depthwise convolution followed by the pointwise convolution.
+func (ds *DepthwiseSeparableConv2D) Output(ins []*Value) []*Value {
+	return ds.Pointwise.Output(ds.Depthwise.Output(ins))
+}
+
+// Parameters returns the depthwise parameters followed by the pointwise parameters.
+func (ds *DepthwiseSeparableConv2D) Parameters() []*Value {
+	return append(ds.Depthwise.Parameters(), ds.Pointwise.Parameters()...)
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Dropout zeroes every activation passing through it with probability Rate
+// in training mode and scales the survivors by 1/(1-Rate), so the expected
+// activation is unchanged (inverted dropout); in evaluation mode it passes its
+// inputs through unchanged. It is a regularizer with no trainable parameters.
+type Dropout struct {
+	Rate     float64
+	Training bool
+	Rand     *rand.Rand // Source of the masks; nil uses the global math/rand source
+}
+
+// NewDropout creates a Dropout module in training mode. It panics unless
+// 0 <= rate < 1.
+func NewDropout(rate float64) *Dropout {
+	if rate < 0 || rate >= 1 {
+		panic(fmt.Sprintf("engine: dropout rate %v outside [0, 1)", rate))
+	}
+	return &Dropout{
+		Rate:     rate,
+		Training: true,
+	}
+}
+
+// Clone returns a copy of the dropout configuration and mode.
+func (d *Dropout) Clone() *Dropout {
+	c := *d
+	return &c
+}
+
+// String provides a formatted string representation of a Dropout module.
+func (d *Dropout) String() string {
+	return fmt.Sprintf("Dropout(rate=%.4f, training=%t)", d.Rate, d.Training)
+}
+
+// SetRand makes the masks be drawn from r.
+func (d *Dropout) SetRand(r *rand.Rand) {
+	d.Rand = r
+}
+
+// SetTraining enables (true) or disables (false) dropout.
+func (d *Dropout) SetTraining(training bool) {
+	d.Training = training
+}
+
+// Output drops each input with probability Rate and scales the rest in
+// training mode, and returns the inputs unchanged otherwise.
+func (d *Dropout) Output(ins []*Value) []*Value {
+	if !d.Training || d.Rate == 0 {
+		return ins
+	}
+	scale := NewValue(1/(1-d.Rate), "dropout_scale")
+	out := make([]*Value, len(ins))
+	for i, in := range ins {
+		if unit(d.Rand) < d.Rate {
+			out[i] = in.Mul(NewValue(0, "dropped"))
+		} else {
+			out[i] = in.Mul(scale)
+		}
+	}
+	return out
+}
+
+// Parameters returns nil; Dropout has nothing to train.
+func (d *Dropout) Parameters() []*Value {
+	return nil
+}
@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Combination selects how an Ensemble combines the outputs of its members.
+type Combination int
+
+const (
+	// CombineMean averages the member outputs.
+	CombineMean Combination = iota
+	// CombineWeighted averages the member outputs weighted by Ensemble.Weights.
+	CombineWeighted
+	// CombineVote counts the class each member predicts: the output holds the
+	// (weighted) share of votes for every class, or, for single-output members,
+	// the share of members whose output exceeds Ensemble.Threshold. Votes are
+	// constants, so no gradient flows through a voting ensemble.
+	CombineVote
+)
+
+// String returns the name of the combination.
+func (c Combination) String() string {
+	switch c {
+	case CombineMean:
+		return "mean"
+	case CombineWeighted:
+		return "weighted"
+	case CombineVote:
+		return "vote"
+	}
+	return fmt.Sprintf("Combination(%d)", int(c))
+}
+
+// Ensemble combines the predictions of several models with the same output
+// size, which usually generalizes better than any single member. Members are
+// typically trained separately (e.g. by train.Bagging).
+type Ensemble struct {
+	Members   []Module
+	Combine   Combination
+	Weights   []float64 // Weight of every member for CombineWeighted (and CombineVote if set)
+	Threshold float64   // Decision threshold of single-output members for CombineVote
+}
+
+// NewEnsemble creates an ensemble averaging the outputs of the members.
+func NewEnsemble(members ...Module) *Ensemble {
+	return &Ensemble{Members: members}
+}
+
+// Clone returns a deep copy of every member; see CloneModule.
+func (e *Ensemble) Clone() *Ensemble {
+	c := *e
+	c.Members = make([]Module, len(e.Members))
+	for i := range e.Members {
+		c.Members[i] = CloneModule(e.Members[i])
+	}
+	c.Weights = append([]float64(nil), e.Weights...)
+	return &c
+}
+
+// String provides a formatted string representation of an Ensemble, detailing each member.
+func (e *Ensemble) String() string {
+	s := fmt.Sprintf("Ensemble of %d members (%v):\n", len(e.Members), e.Combine)
+	for i, m := range e.Members {
+		s += fmt.Sprintf("  Member %d:\n%v\n", i+1, m)
+	}
+	return s
+}
+
+// weight returns the weight of member i: Weights[i] if weights are in use, 1 otherwise.
+func (e *Ensemble) weight(i int) float64 {
+	if e.Combine == CombineMean || e.Weights == nil {
+		return 1
+	}
+	if len(e.Weights) != len(e.Members) {
+		panic(fmt.Sprintf("engine: ensemble has %d weights for %d members", len(e.Weights), len(e.Members)))
+	}
+	return e.Weights[i]
+}
+
+// Output combines the outputs of all members according to Combine.
+func (e *Ensemble) Output(ins []*Value) []*Value {
+	if len(e.Members) == 0 {
+		panic("engine: ensemble has no members")
+	}
+	if e.Combine == CombineVote {
+		return e.vote(ins)
+	}
+
+	var out []*Value
+	total := 0.0
+	for j, m := range e.Members {
+		w := e.weight(j)
+		total += w
+		memberOut := m.Output(ins)
+		if out == nil {
+			out = make([]*Value, len(memberOut))
+			for i := range out {
+				out[i] = NewValue(0.0, "")
+			}
+		}
+		for i := range memberOut {
+			out[i] = out[i].Add(memberOut[i].Mul(NewValue(w, "")))
+		}
+	}
+	for i := range out {
+		out[i] = out[i].Mul(NewValue(1/total, ""))
+		out[i].Label = fmt.Sprintf("ensemble_output_%d", i+1)
+	}
+	return out
+}
+
+// vote returns the share of the votes every class receives.
+func (e *Ensemble) vote(ins []*Value) []*Value {
+	var votes []float64
+	total := 0.0
+	for j, m := range e.Members {
+		w := e.weight(j)
+		total += w
+		memberOut := m.Output(ins)
+		if votes == nil {
+			votes = make([]float64, len(memberOut))
+		}
+		if len(memberOut) == 1 {
+			if memberOut[0].Data > e.Threshold {
+				votes[0] += w
+			}
+			continue
+		}
+		best := 0
+		for i, v := range memberOut {
+			if v.Data > memberOut[best].Data {
+				best = i
+			}
+		}
+		votes[best] += w
+	}
+	out := make([]*Value, len(votes))
+	for i, v := range votes {
+		out[i] = NewValue(v/total, fmt.Sprintf("ensemble_votes_%d", i+1))
+	}
+	return out
+}
+
+// Parameters returns the parameters of every member in order.
+func (e *Ensemble) Parameters() []*Value {
+	var p []*Value
+	for _, m := range e.Members {
+		p = append(p, m.Parameters()...)
+	}
+	return p
+}
+
+// SetTraining forwards the mode to every member.
+func (e *Ensemble) SetTraining(training bool) {
+	for _, m := range e.Members {
+		SetTraining(m, training)
+	}
+}
+
+// SetRand forwards the random number generator to every member.
+func (e *Ensemble) SetRand(r *rand.Rand) {
+	for _, m := range e.Members {
+		SetRand(m, r)
+	}
+}
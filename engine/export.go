@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ExportCSV writes one row per scalar parameter of m with the columns name,
+// value and, if includeGrads is set, grad. Names are those of
+// NamedParameters followed by the position within the tensor, e.g.
+// "layers.0.weight[2][1]" for the weight from input 1 to neuron 2 of the
+// first layer, so the rows can be filtered by layer or tensor in a
+// spreadsheet or with pandas.
+func ExportCSV(w io.Writer, m Module, includeGrads bool) error {
+	cw := csv.NewWriter(w)
+	header := []string{"name", "value"}
+	if includeGrads {
+		header = append(header, "grad")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, np := range NamedParameters(m) {
+		for i, v := range np.Values {
+			row := []string{np.Name + tensorIndex(np.Shape, i), strconv.FormatFloat(v.Data, 'g', -1, 64)}
+			if includeGrads {
+				row = append(row, strconv.FormatFloat(v.Grad, 'g', -1, 64))
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportCSV writes the parameters of the MLP to w, see the function ExportCSV.
+func (mlp *MLP) ExportCSV(w io.Writer, includeGrads bool) error {
+	return ExportCSV(w, mlp, includeGrads)
+}
+
+// tensorIndex formats the position of the i-th value in row-major order of shape
+// as "[a][b]...".
+func tensorIndex(shape []int, i int) string {
+	coords := make([]int, len(shape))
+	for d := len(shape) - 1; d >= 0; d-- {
+		coords[d] = i % shape[d]
+		i /= shape[d]
+	}
+	var sb strings.Builder
+	for _, c := range coords {
+		fmt.Fprintf(&sb, "[%d]", c)
+	}
+	return sb.String()
+}
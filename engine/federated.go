@@ -0,0 +1,96 @@
+package engine
+
+import "fmt"
+
+// AverageModels returns a new MLP whose parameters are the weighted average of
+// the parameters of models, position by position, as in federated averaging
+// (FedAvg) where every client trains a copy of the same network on its own data.
+// weights are typically the clients' example counts; nil weighs all models
+// equally. The models must have compatible architectures (see CompatibleArch)
+// and are not modified.
+func AverageModels(models []*MLP, weights []float64) (*MLP, error) {
+	if len(models) == 0 {
+		return nil, fmt.Errorf("engine: no models to average")
+	}
+	modules := make([]Module, len(models))
+	for i, m := range models {
+		modules[i] = m
+	}
+	avg := models[0].Clone()
+	if err := averageInto(avg, modules, weights); err != nil {
+		return nil, err
+	}
+	return avg, nil
+}
+
+// averageInto overwrites the parameters of dst with the weighted average of
+// the parameters of models.
+func averageInto(dst Module, models []Module, weights []float64) error {
+	if weights != nil && len(weights) != len(models) {
+		return fmt.Errorf("engine: %d weights for %d models", len(weights), len(models))
+	}
+	total := 0.0
+	for i, m := range models {
+		if err := compatibleArch(dst, m); err != nil {
+			return fmt.Errorf("engine: model %d has an incompatible architecture: %w", i, err)
+		}
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		if w < 0 {
+			return fmt.Errorf("engine: negative weight %v for model %d", w, i)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("engine: model weights sum to zero")
+	}
+
+	params := dst.Parameters()
+	sums := make([]float64, len(params))
+	for i, m := range models {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		for j, p := range m.Parameters() {
+			sums[j] += w * p.Data
+		}
+	}
+	for j, p := range params {
+		p.Data = sums[j] / total
+	}
+	return nil
+}
+
+// Delta returns the change of every parameter from base to updated, in the
+// order of Parameters, e.g. the update a federated client sends back after
+// training its copy of the global model. The models must have compatible
+// architectures.
+func Delta(updated, base Module) ([]float64, error) {
+	if err := CompatibleArch(updated, base); err != nil {
+		return nil, err
+	}
+	basePs := base.Parameters()
+	delta := make([]float64, len(basePs))
+	for i, p := range updated.Parameters() {
+		delta[i] = p.Data - basePs[i].Data
+	}
+	return delta, nil
+}
+
+// ApplyDelta adds scale times delta to the parameters of m, in the order of
+// Parameters. With scale 1 it applies an update returned by Delta; averaging
+// the deltas of several clients first (or scaling by a server learning rate)
+// gives the usual federated update of the global model.
+func ApplyDelta(m Module, delta []float64, scale float64) error {
+	params := m.Parameters()
+	if len(delta) != len(params) {
+		return fmt.Errorf("engine: delta has %d values for %d parameters", len(delta), len(params))
+	}
+	for i, p := range params {
+		p.Data += scale * delta[i]
+	}
+	return nil
+}
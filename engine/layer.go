@@ -24,16 +24,41 @@ func (l *Layer) String() string {
 // NewLayer creates and returns a new Layer with 'outs' number of neurons,
 // each having 'ins' input connections.
 func NewLayer(ins, outs int) *Layer {
+	return NewLayerRand(ins, outs, nil)
+}
+
+// NewLayerRand is like NewLayer but draws the initial weights from r.
+func NewLayerRand(ins, outs int, r *rand.Rand) *Layer {
 	l := Layer{
 		Neurons: make([]*Neuron, outs),
 	}
 
 	for i := range l.Neurons {
-		l.Neurons[i] = NewNeuron(ins) // Create each neuron in the layer
+		l.Neurons[i] = NewNeuronRand(ins, r) // Create each neuron in the layer
 	}
 	return &l
 }
 
+// WithActivation sets the activation function of every neuron in the layer
+// and returns the layer, so it can be chained onto NewLayer.
+func (l *Layer) WithActivation(act Activation) *Layer {
+	for _, neuron := range l.Neurons {
+		neuron.Activation = act
+	}
+	return l
+}
+
+// Clone returns a deep copy of the layer; see Neuron.Clone.
+func (l *Layer) Clone() *Layer {
+	c := Layer{
+		Neurons: make([]*Neuron, len(l.Neurons)),
+	}
+	for i := range l.Neurons {
+		c.Neurons[i] = l.Neurons[i].Clone()
+	}
+	return &c
+}
+
 // Output computes the outputs of all neurons in the layer given a slice of input Values.
 // It returns a slice of Value objects, one for each neuron's output.
 func (l *Layer) Output(inputs []*Value) []*Value {
@@ -60,9 +85,9 @@ func (l *Layer) Parameters() []*Value {
 func TestLayer() {
 	fmt.Println("--- Testing Layer ---")
 	// For reproducibility in this example
-	rand.Seed(42)
+	r := rand.New(rand.NewSource(42))
 
-	l := NewLayer(3, 2) // A layer with 3 inputs and 2 output neurons
+	l := NewLayerRand(3, 2, r) // A layer with 3 inputs and 2 output neurons
 	xs := []*Value{
 		NewValue(1.0, "x1"),
 		NewValue(-2.0, "x2"),
@@ -113,4 +138,3 @@ func TestLayer() {
 	fmt.Println("--- End TestLayer ---")
 	fmt.Println()
 }
-
@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+)
+
+// discardHandler is an slog.Handler that drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// DiscardLogger returns a logger that drops everything, the default for all
+// loggers in this module.
+func DiscardLogger() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+// logger receives the engine's debug output; see SetLogger.
+var logger = DiscardLogger()
+
+// SetLogger routes the engine's debug messages (such as the size of every
+// graph passed to FullBackward) to l. Passing nil discards them again.
+// It is not safe to call while other goroutines use the engine.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = DiscardLogger()
+	}
+	logger = l
+}
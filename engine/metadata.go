@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+// ModelMetadata describes how a saved model was produced. It is written by
+// MLP.SaveWithMetadata and MLP.SaveGobWithMetadata and read back, without the
+// weights, by InspectModel; package modelfile stores it too. Every field is
+// optional.
+type ModelMetadata struct {
+	PackageVersion string `json:"package_version,omitempty"` // Version of this module that saved the model
+
+	// Training holds the training configuration by name, e.g. "optimizer",
+	// "learning_rate", "epochs" and "batch_size".
+	Training map[string]string `json:"training,omitempty"`
+
+	// DatasetFingerprint identifies the training data, e.g. as computed by
+	// data.Fingerprint, so a model can be traced back to what it was trained on.
+	DatasetFingerprint string `json:"dataset_fingerprint,omitempty"`
+
+	Metrics map[string]float64 `json:"metrics,omitempty"` // Final metrics, e.g. "loss" and "val_accuracy"
+
+	TrainingStarted  time.Time `json:"training_started"`
+	TrainingFinished time.Time `json:"training_finished"`
+	Saved            time.Time `json:"saved"`
+}
+
+// modulePath is the path of this module, looked up in the build information.
+const modulePath = "github.com/Rmehta-sudo/neural-net"
+
+// PackageVersion returns the version of this module linked into the running
+// program, as recorded by the go command, or "(devel)" if it is unknown
+// (e.g. when it is the main module).
+func PackageVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
+
+// ModelInfo summarizes a saved MLP without its weights.
+type ModelInfo struct {
+	Format      string // "json" or "gob", or "modelfile" from modelfile.Inspect
+	Version     int    // Version of the saved schema
+	Inputs      int
+	LayerSizes  []int // Outputs of every layer
+	Activations []Activation
+	Parameters  int            // Number of weights and biases
+	Metadata    *ModelMetadata // Nil if the model was saved without metadata
+}
+
+// inspectedMLP is savedMLP without the weights, which the JSON and gob
+// decoders skip instead of allocating them.
+type inspectedMLP struct {
+	Version int              `json:"version"`
+	Inputs  int              `json:"inputs"`
+	Layers  []inspectedLayer `json:"layers"`
+
+	Metadata *ModelMetadata `json:"metadata,omitempty"`
+}
+
+// inspectedLayer is savedLayer without the weights.
+type inspectedLayer struct {
+	Outputs    int        `json:"outputs"`
+	Activation Activation `json:"activation"`
+}
+
+// InspectModel reads the architecture and metadata of the MLP saved at path
+// by MLP.Save, MLP.SaveGob or their WithMetadata variants, detecting the
+// encoding, without building the network or keeping its weights. Files of
+// package modelfile are not recognised; use modelfile.Inspect for them.
+func InspectModel(path string) (*ModelInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	info := &ModelInfo{Format: "gob"}
+	var s inspectedMLP
+	if isJSON(r) {
+		info.Format = "json"
+		err = json.NewDecoder(r).Decode(&s)
+	} else {
+		err = gob.NewDecoder(r).Decode(&s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("engine: decoding %s: %w", path, err)
+	}
+	if s.Version != savedMLPVersion {
+		return nil, fmt.Errorf("engine: unsupported MLP format version %d", s.Version)
+	}
+
+	info.Version, info.Inputs, info.Metadata = s.Version, s.Inputs, s.Metadata
+	numIn := s.Inputs
+	for _, l := range s.Layers {
+		info.LayerSizes = append(info.LayerSizes, l.Outputs)
+		info.Activations = append(info.Activations, l.Activation)
+		info.Parameters += l.Outputs * (numIn + 1)
+		numIn = l.Outputs
+	}
+	return info, nil
+}
+
+// isJSON reports whether the first non-space byte of r opens a JSON object.
+func isJSON(r *bufio.Reader) bool {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return false
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		r.UnreadByte()
+		return b == '{'
+	}
+}
@@ -1,6 +1,9 @@
 package engine
 
-import "fmt"
+import (
+	"fmt"
+	"math/rand"
+)
 
 // MLP represents a Multi-Layer Perceptron neural network.
 // It consists of a slice of Layer objects.
@@ -12,21 +15,39 @@ type MLP struct {
 // numOuts specifies the number of neurons in each hidden and output layer.
 // numIn specifies the number of input features for the first layer.
 func NewMLP(numOuts []int, numIn int) *MLP {
+	return NewMLPRand(numOuts, numIn, nil)
+}
+
+// NewMLPRand is like NewMLP but draws the initial weights from r, so that the
+// network is reproducible independently of any other use of randomness.
+func NewMLPRand(numOuts []int, numIn int, r *rand.Rand) *MLP {
 	mlp := MLP{
 		Layers: make([]*Layer, len(numOuts)),
 	}
 
 	for i := range numOuts {
 		if i == 0 {
-			mlp.Layers[i] = NewLayer(numIn, numOuts[0]) // First layer connects to input features
+			mlp.Layers[i] = NewLayerRand(numIn, numOuts[0], r) // First layer connects to input features
 		} else {
 			// Subsequent layers connect to the output of the previous layer
-			mlp.Layers[i] = NewLayer(numOuts[i-1], numOuts[i])
+			mlp.Layers[i] = NewLayerRand(numOuts[i-1], numOuts[i], r)
 		}
 	}
 	return &mlp
 }
 
+// Clone returns a deep copy of the MLP with the same architecture and weights,
+// but independent Values, e.g. for target networks or weight snapshots.
+func (mlp *MLP) Clone() *MLP {
+	c := MLP{
+		Layers: make([]*Layer, len(mlp.Layers)),
+	}
+	for i := range mlp.Layers {
+		c.Layers[i] = mlp.Layers[i].Clone()
+	}
+	return &c
+}
+
 // String provides a formatted string representation of an MLP,
 // detailing each layer and its neurons.
 func (mlp *MLP) String() string {
@@ -188,7 +209,8 @@ func TestMLP() {
 					fmt.Print(", ")
 				}
 			}
-			fmt.Println("]\n")
+			fmt.Println("]")
+			fmt.Println()
 		}
 	}
 
@@ -0,0 +1,17 @@
+package engine
+
+// TrainingModer is implemented by modules that behave differently during
+// training and evaluation (e.g. noise injection). Containers implement it by
+// forwarding the mode to all of their sub-modules.
+type TrainingModer interface {
+	SetTraining(training bool)
+}
+
+// SetTraining switches m into training (true) or evaluation (false) mode.
+// Modules that do not implement TrainingModer behave the same in both modes
+// and are left untouched.
+func SetTraining(m Module, training bool) {
+	if tm, ok := m.(TrainingModer); ok {
+		tm.SetTraining(training)
+	}
+}
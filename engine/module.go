@@ -0,0 +1,9 @@
+package engine
+
+// Module is implemented by every network component that maps a slice of input
+// Values to a slice of output Values and owns a set of trainable parameters.
+// Layer and MLP both satisfy it, so containers can be built from either.
+type Module interface {
+	Output(ins []*Value) []*Value
+	Parameters() []*Value
+}
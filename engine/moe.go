@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// MoE is a mixture-of-experts layer: a trainable gating layer produces one
+// logit per expert, a softmax turns them into mixing weights, and the output is
+// the gate-weighted sum of all expert outputs. Gradients flow into both the
+// gate and the experts. All experts must produce outputs of the same length.
+type MoE struct {
+	Gate    *Layer
+	Experts []Module
+}
+
+// NewMoE creates a mixture of the given experts for inputs of size numIn.
+// The gate is a linear layer with one output per expert.
+func NewMoE(numIn int, experts ...Module) *MoE {
+	return &MoE{
+		Gate:    NewLayer(numIn, len(experts)).WithActivation(Linear),
+		Experts: experts,
+	}
+}
+
+// Clone returns a deep copy of the gate and every expert; see CloneModule.
+func (moe *MoE) Clone() *MoE {
+	c := MoE{
+		Gate:    moe.Gate.Clone(),
+		Experts: make([]Module, len(moe.Experts)),
+	}
+	for i := range moe.Experts {
+		c.Experts[i] = CloneModule(moe.Experts[i])
+	}
+	return &c
+}
+
+// String provides a formatted string representation of a MoE layer,
+// detailing the gate and each expert.
+func (moe *MoE) String() string {
+	s := fmt.Sprintf("MoE with %d experts:\n", len(moe.Experts))
+	s += fmt.Sprintf("  Gate:\n%s", moe.Gate.String())
+	for i, expert := range moe.Experts {
+		s += fmt.Sprintf("  Expert %d:\n%v\n", i+1, expert)
+	}
+	return s
+}
+
+// Gates returns the softmax mixing weights the gate assigns to each expert for the inputs.
+func (moe *MoE) Gates(ins []*Value) []*Value {
+	return Softmax(moe.Gate.Output(ins))
+}
+
+// Output computes the gate-weighted sum of the expert outputs.
+func (moe *MoE) Output(ins []*Value) []*Value {
+	gates := moe.Gates(ins)
+
+	var out []*Value
+	for j, expert := range moe.Experts {
+		expertOut := expert.Output(ins)
+		if out == nil {
+			out = make([]*Value, len(expertOut))
+			for i := range out {
+				out[i] = NewValue(0.0, "")
+			}
+		}
+		for i := range expertOut {
+			out[i] = out[i].Add(gates[j].Mul(expertOut[i])) // Weighted contribution of expert j
+		}
+	}
+	for i := range out {
+		out[i].Label = fmt.Sprintf("moe_output_%d", i+1)
+	}
+	return out
+}
+
+// Parameters returns the gate parameters followed by those of every expert.
+func (moe *MoE) Parameters() []*Value {
+	p := moe.Gate.Parameters()
+	for _, expert := range moe.Experts {
+		p = append(p, expert.Parameters()...) // Collect parameters from each expert
+	}
+	return p
+}
+
+// SetTraining forwards the training mode to every sub-module.
+func (moe *MoE) SetTraining(training bool) {
+	for _, expert := range moe.Experts {
+		SetTraining(expert, training)
+	}
+}
+
+// SetRand forwards the random number generator to every expert.
+func (moe *MoE) SetRand(r *rand.Rand) {
+	for _, expert := range moe.Experts {
+		SetRand(expert, r)
+	}
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// MultiHead is a container that feeds the output of a shared trunk into several
+// independent heads, e.g. a classification head and a regression head.
+// Each head produces its own outputs (and therefore its own loss); summing the
+// head losses and calling FullBackward on the sum merges their gradients in the
+// shared trunk parameters.
+type MultiHead struct {
+	Trunk Module
+	Heads []Module
+}
+
+// NewMultiHead creates and returns a new MultiHead with the given trunk and heads.
+func NewMultiHead(trunk Module, heads ...Module) *MultiHead {
+	return &MultiHead{
+		Trunk: trunk,
+		Heads: heads,
+	}
+}
+
+// Clone returns a deep copy of the trunk and every head; see CloneModule.
+func (mh *MultiHead) Clone() *MultiHead {
+	c := MultiHead{
+		Trunk: CloneModule(mh.Trunk),
+		Heads: make([]Module, len(mh.Heads)),
+	}
+	for i := range mh.Heads {
+		c.Heads[i] = CloneModule(mh.Heads[i])
+	}
+	return &c
+}
+
+// String provides a formatted string representation of a MultiHead,
+// detailing the trunk and each of its heads.
+func (mh *MultiHead) String() string {
+	s := fmt.Sprintf("MultiHead with %d heads:\n", len(mh.Heads))
+	s += fmt.Sprintf("  Trunk:\n%v\n", mh.Trunk)
+	for i, head := range mh.Heads {
+		s += fmt.Sprintf("  Head %d:\n%v\n", i+1, head)
+	}
+	return s
+}
+
+// HeadOutputs runs the trunk once on the inputs and returns the outputs of
+// every head, in the same order as Heads.
+func (mh *MultiHead) HeadOutputs(ins []*Value) [][]*Value {
+	shared := mh.Trunk.Output(ins) // Trunk is evaluated only once for all heads
+	outs := make([][]*Value, len(mh.Heads))
+	for i, head := range mh.Heads {
+		outs[i] = head.Output(shared)
+	}
+	return outs
+}
+
+// Output computes the outputs of all heads and concatenates them into a single
+// slice, so a MultiHead can be used anywhere a Module is expected.
+func (mh *MultiHead) Output(ins []*Value) []*Value {
+	var out []*Value
+	for _, headOut := range mh.HeadOutputs(ins) {
+		out = append(out, headOut...)
+	}
+	return out
+}
+
+// Parameters returns the trainable parameters of the trunk followed by those of every head.
+func (mh *MultiHead) Parameters() []*Value {
+	p := mh.Trunk.Parameters()
+	for _, head := range mh.Heads {
+		p = append(p, head.Parameters()...) // Collect parameters from each head
+	}
+	return p
+}
+
+// SumLosses adds the per-head losses into a single Value. Calling FullBackward
+// on the result propagates every head's gradient back into the shared trunk.
+func SumLosses(losses []*Value) *Value {
+	total := NewValue(0.0, "total_loss")
+	for _, l := range losses {
+		total = total.Add(l)
+	}
+	total.Label = "total_loss"
+	return total
+}
+
+// SetTraining forwards the training mode to every sub-module.
+func (mh *MultiHead) SetTraining(training bool) {
+	SetTraining(mh.Trunk, training)
+	for _, head := range mh.Heads {
+		SetTraining(head, training)
+	}
+}
+
+// SetRand forwards the random number generator to the trunk and every head.
+func (mh *MultiHead) SetRand(r *rand.Rand) {
+	SetRand(mh.Trunk, r)
+	for _, head := range mh.Heads {
+		SetRand(head, r)
+	}
+}
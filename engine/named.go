@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamedParam is one logical parameter tensor of a module, such as a layer's
+// weight matrix, with a hierarchical name (e.g. "layers.0.weight"). Values
+// holds the underlying scalar parameters in row-major order of Shape.
+type NamedParam struct {
+	Name   string
+	Shape  []int
+	Values []*Value
+}
+
+// NamedModule is implemented by user-defined modules that name their own
+// parameters, so NamedParameters can handle them inside containers.
+type NamedModule interface {
+	NamedParameters() []NamedParam
+}
+
+// NamedParameters returns the parameters of m grouped into named tensors.
+// Names follow PyTorch conventions where possible ("weight", "bias",
+// "layers.0.weight", "0.weight" inside a Sequential, ...) so weights can be
+// matched against other frameworks. Together the tensors cover exactly the
+// Values returned by m.Parameters(), though not necessarily in the same order.
+// Unknown modules not implementing NamedModule yield a single "params" tensor.
+func NamedParameters(m Module) []NamedParam {
+	switch m := m.(type) {
+	case *Layer:
+		return layerParams(m)
+	case *MLP:
+		var p []NamedParam
+		for i, layer := range m.Layers {
+			p = append(p, prefixed(fmt.Sprintf("layers.%d.", i), layerParams(layer))...)
+		}
+		return p
+	case *Sequential:
+		var p []NamedParam
+		for i, sub := range m.Modules {
+			p = append(p, prefixed(fmt.Sprintf("%d.", i), NamedParameters(sub))...)
+		}
+		return p
+	case *MultiHead:
+		p := prefixed("trunk.", NamedParameters(m.Trunk))
+		for i, head := range m.Heads {
+			p = append(p, prefixed(fmt.Sprintf("heads.%d.", i), NamedParameters(head))...)
+		}
+		return p
+	case *Siamese:
+		return prefixed("tower.", NamedParameters(m.Tower))
+	case *MoE:
+		p := prefixed("gate.", layerParams(m.Gate))
+		for i, expert := range m.Experts {
+			p = append(p, prefixed(fmt.Sprintf("experts.%d.", i), NamedParameters(expert))...)
+		}
+		return p
+	case *Ensemble:
+		var p []NamedParam
+		for i, member := range m.Members {
+			p = append(p, prefixed(fmt.Sprintf("members.%d.", i), NamedParameters(member))...)
+		}
+		return p
+	case *Autoencoder:
+		p := prefixed("encoder.", NamedParameters(m.Encoder))
+		if !m.Tied {
+			return append(p, prefixed("decoder.", NamedParameters(m.Decoder))...)
+		}
+		for i, layer := range m.Decoder.Layers {
+			// Tied decoder weights belong to the encoder; only the biases are separate
+			bias := layerParams(layer)[1]
+			bias.Name = fmt.Sprintf("decoder.layers.%d.bias", i)
+			p = append(p, bias)
+		}
+		return p
+	case *BayesianLinear:
+		return []NamedParam{
+			{Name: "weight_mu", Shape: []int{m.Out, m.In}, Values: flatten(m.WeightMu)},
+			{Name: "weight_logvar", Shape: []int{m.Out, m.In}, Values: flatten(m.WeightLogVar)},
+			{Name: "bias_mu", Shape: []int{m.Out}, Values: m.BiasMu},
+			{Name: "bias_logvar", Shape: []int{m.Out}, Values: m.BiasLogVar},
+		}
+	case *WeightNorm:
+		lp := layerParams(m.Layer)
+		return []NamedParam{
+			{Name: "weight_g", Shape: []int{len(m.G)}, Values: m.G},
+			{Name: "weight_v", Shape: lp[0].Shape, Values: flatten(m.V)},
+			lp[1],
+		}
+	case *SpectralNorm:
+		return layerParams(m.Layer)
+	case *DepthwiseConv2D:
+		return []NamedParam{
+			{Name: "weight", Shape: []int{m.Channels, 1, m.KernelSize, m.KernelSize}, Values: flatten(m.Kernels)},
+			{Name: "bias", Shape: []int{m.Channels}, Values: m.Biases},
+		}
+	case *PointwiseConv2D:
+		return []NamedParam{
+			{Name: "weight", Shape: []int{m.OutChannels, m.InChannels, 1, 1}, Values: flatten(m.Weights)},
+			{Name: "bias", Shape: []int{m.OutChannels}, Values: m.Biases},
+		}
+	case *DepthwiseSeparableConv2D:
+		return append(prefixed("depthwise.", NamedParameters(m.Depthwise)),
+			prefixed("pointwise.", NamedParameters(m.Pointwise))...)
+	case *ConvTranspose2D:
+		// Stored as [out][in][k*k] but named in the conventional [in, out, k, k] layout
+		var w []*Value
+		for i := 0; i < m.InChannels; i++ {
+			for o := 0; o < m.OutChannels; o++ {
+				w = append(w, m.Kernels[o][i]...)
+			}
+		}
+		return []NamedParam{
+			{Name: "weight", Shape: []int{m.InChannels, m.OutChannels, m.KernelSize, m.KernelSize}, Values: w},
+			{Name: "bias", Shape: []int{m.OutChannels}, Values: m.Biases},
+		}
+	case NamedModule:
+		return m.NamedParameters()
+	}
+
+	params := m.Parameters()
+	if len(params) == 0 {
+		return nil
+	}
+	return []NamedParam{{Name: "params", Shape: []int{len(params)}, Values: params}}
+}
+
+// layerParams names a layer's weights as an [out, in] "weight" matrix and its biases as "bias".
+func layerParams(l *Layer) []NamedParam {
+	var w, b []*Value
+	numIn := 0
+	for _, neuron := range l.Neurons {
+		w = append(w, neuron.Weights...)
+		b = append(b, neuron.Bias)
+		numIn = len(neuron.Weights)
+	}
+	return []NamedParam{
+		{Name: "weight", Shape: []int{len(l.Neurons), numIn}, Values: w},
+		{Name: "bias", Shape: []int{len(l.Neurons)}, Values: b},
+	}
+}
+
+// prefixed prepends prefix to the name of every parameter.
+func prefixed(prefix string, params []NamedParam) []NamedParam {
+	for i := range params {
+		params[i].Name = prefix + params[i].Name
+	}
+	return params
+}
+
+// flatten concatenates the rows of a 2D slice of Values.
+func flatten(rows [][]*Value) []*Value {
+	var out []*Value
+	for _, row := range rows {
+		out = append(out, row...)
+	}
+	return out
+}
+
+// ParametersWithPrefix returns the Values of all named parameters of m whose
+// names start with one of the given prefixes, e.g. "layers.0." for the first
+// layer of an MLP or "trunk." for the shared trunk of a MultiHead.
+func ParametersWithPrefix(m Module, prefixes ...string) []*Value {
+	var p []*Value
+	for _, np := range NamedParameters(m) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(np.Name, prefix) {
+				p = append(p, np.Values...)
+				break
+			}
+		}
+	}
+	return p
+}
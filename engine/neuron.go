@@ -6,10 +6,12 @@ import (
 )
 
 // Neuron represents a single neuron in a neural network layer.
-// It contains a slice of weights and a bias, both as Value objects.
+// It contains a slice of weights and a bias, both as Value objects,
+// and the activation function applied to their weighted sum (Tanh by default).
 type Neuron struct {
-	Weights []*Value
-	Bias    *Value
+	Weights    []*Value
+	Bias       *Value
+	Activation Activation
 }
 
 // String provides a formatted string representation of a Neuron.
@@ -26,36 +28,58 @@ func (n *Neuron) String() string {
 }
 
 // NewNeuron creates and returns a new Neuron with 'numIn' input connections.
-// Weights and bias are initialized with random values between -1 and 1.
+// Weights and bias are initialized with random values between -1 and 1
+// drawn from the global math/rand source.
 func NewNeuron(numIn int) *Neuron {
+	return NewNeuronRand(numIn, nil)
+}
+
+// NewNeuronRand is like NewNeuron but draws the initial weights and bias from r.
+func NewNeuronRand(numIn int, r *rand.Rand) *Neuron {
 	neur := Neuron{
 		Weights: make([]*Value, numIn),
-		Bias:    NewValue(rand.Float64()*2-1, "b"), // Bias initialized randomly
+		Bias:    NewValue(uniform(r), "b"), // Bias initialized randomly
 	}
 
 	for i := 0; i < numIn; i++ {
-		neur.Weights[i] = NewValue(rand.Float64()*2-1, fmt.Sprintf("w%d", i+1)) // Weights initialized randomly
+		neur.Weights[i] = NewValue(uniform(r), fmt.Sprintf("w%d", i+1)) // Weights initialized randomly
 	}
 
 	return &neur
 }
 
+// Clone returns a deep copy of the neuron whose weights and bias are
+// independent Values with the same data.
+func (neur *Neuron) Clone() *Neuron {
+	return &Neuron{
+		Weights:    leafCopies(neur.Weights),
+		Bias:       neur.Bias.leafCopy(),
+		Activation: neur.Activation,
+	}
+}
+
 // Output computes the output of the neuron given a slice of input Values.
-// It calculates the weighted sum of inputs plus bias, then applies the Tanh activation.
+// It calculates the weighted sum of inputs plus bias, then applies the neuron's activation.
 func (neur *Neuron) Output(inputs []*Value) *Value {
 	// Note: Input validation (checking len(inputs) == len(neur.Weights)) is omitted here as per instructions,
 	// but would typically be added for robustness.
+	return neur.outputWith(neur.Weights, inputs)
+}
 
+// outputWith computes the neuron's activated output using the given weights in place
+// of neur.Weights. Wrappers that reparameterize the weights (e.g. weight normalization)
+// use it so they share the neuron's bias and activation.
+func (neur *Neuron) outputWith(weights []*Value, inputs []*Value) *Value {
 	out := neur.Bias // Start with bias
 
 	// Compute weighted sum
-	for i := range neur.Weights {
-		out = out.Add(neur.Weights[i].Mul(inputs[i]))
+	for i := range weights {
+		out = out.Add(weights[i].Mul(inputs[i]))
 	}
 	out.Label = "neuron_raw_output" // Label the raw sum before activation
 
-	// Apply Tanh activation
-	out = out.Tanh()
+	// Apply the activation function
+	out = neur.Activation.Apply(out)
 	out.Label = "neuron_output" // Label the final activated output
 	return out
 }
@@ -93,4 +117,3 @@ func TestNeuron() {
 	fmt.Println("--- End TestNeuron ---")
 	fmt.Println()
 }
-
@@ -0,0 +1,56 @@
+package engine
+
+// NoDecayParameters returns the parameters of m that should normally be
+// excluded from weight decay: biases and normalization scales (such as the
+// WeightNorm gains). Shrinking these towards zero constrains the model without
+// helping generalization. Modules this package does not know about contribute nothing.
+func NoDecayParameters(m Module) []*Value {
+	var p []*Value
+	switch m := m.(type) {
+	case *Layer:
+		for _, neuron := range m.Neurons {
+			p = append(p, neuron.Bias)
+		}
+	case *MLP:
+		for _, layer := range m.Layers {
+			p = append(p, NoDecayParameters(layer)...)
+		}
+	case *Sequential:
+		for _, sub := range m.Modules {
+			p = append(p, NoDecayParameters(sub)...)
+		}
+	case *MultiHead:
+		p = append(p, NoDecayParameters(m.Trunk)...)
+		for _, head := range m.Heads {
+			p = append(p, NoDecayParameters(head)...)
+		}
+	case *Siamese:
+		p = NoDecayParameters(m.Tower)
+	case *MoE:
+		p = NoDecayParameters(m.Gate)
+		for _, expert := range m.Experts {
+			p = append(p, NoDecayParameters(expert)...)
+		}
+	case *Autoencoder:
+		p = append(NoDecayParameters(m.Encoder), NoDecayParameters(m.Decoder)...)
+	case *Ensemble:
+		for _, member := range m.Members {
+			p = append(p, NoDecayParameters(member)...)
+		}
+	case *BayesianLinear:
+		p = append(append(p, m.BiasMu...), m.BiasLogVar...)
+	case *WeightNorm:
+		p = append(NoDecayParameters(m.Layer), m.G...)
+	case *SpectralNorm:
+		p = NoDecayParameters(m.Layer)
+	case *DepthwiseConv2D:
+		p = append(p, m.Biases...)
+	case *PointwiseConv2D:
+		p = append(p, m.Biases...)
+	case *DepthwiseSeparableConv2D:
+		p = append(NoDecayParameters(m.Depthwise), NoDecayParameters(m.Pointwise)...)
+	case *ConvTranspose2D:
+		p = append(p, m.Biases...)
+	}
+	return p
+}
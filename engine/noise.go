@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// GaussianNoise adds zero-mean Gaussian noise with standard deviation Stddev to
+// every activation passing through it, but only in training mode; in evaluation
+// mode it passes its inputs through unchanged. It acts as a regularizer and is
+// the corruption step of a denoising autoencoder. It has no trainable parameters.
+type GaussianNoise struct {
+	Stddev   float64
+	Training bool
+	Rand     *rand.Rand // Source of the noise; nil uses the global math/rand source
+}
+
+// NewGaussianNoise creates a GaussianNoise module in training mode.
+func NewGaussianNoise(stddev float64) *GaussianNoise {
+	return &GaussianNoise{
+		Stddev:   stddev,
+		Training: true,
+	}
+}
+
+// Clone returns a copy of the noise configuration and mode.
+func (gn *GaussianNoise) Clone() *GaussianNoise {
+	c := *gn
+	return &c
+}
+
+// String provides a formatted string representation of a GaussianNoise module.
+func (gn *GaussianNoise) String() string {
+	return fmt.Sprintf("GaussianNoise(stddev=%.4f, training=%t)", gn.Stddev, gn.Training)
+}
+
+// SetRand makes the noise be drawn from r.
+func (gn *GaussianNoise) SetRand(r *rand.Rand) {
+	gn.Rand = r
+}
+
+// SetTraining enables (true) or disables (false) the noise.
+func (gn *GaussianNoise) SetTraining(training bool) {
+	gn.Training = training
+}
+
+// Output adds freshly sampled noise to each input in training mode and returns
+// the inputs unchanged otherwise. The noise is a constant, so gradients pass
+// straight through to the inputs.
+func (gn *GaussianNoise) Output(ins []*Value) []*Value {
+	if !gn.Training || gn.Stddev == 0 {
+		return ins
+	}
+	out := make([]*Value, len(ins))
+	for i, in := range ins {
+		out[i] = in.Add(NewValue(normal(gn.Rand)*gn.Stddev, "noise"))
+	}
+	return out
+}
+
+// Parameters returns nil; GaussianNoise has nothing to train.
+func (gn *GaussianNoise) Parameters() []*Value {
+	return nil
+}
@@ -0,0 +1,43 @@
+package engine
+
+import "fmt"
+
+// Stepper is the part of an optimizer that incremental training needs. Every
+// optim.Optimizer implements it (closure-based ones such as SAM excepted,
+// whose Step panics).
+type Stepper interface {
+	Step()
+	ZeroGrad()
+}
+
+// PartialFit updates m with a single gradient step on one example, for
+// streaming applications that learn sample by sample without building a
+// dataset and a trainer. loss builds the loss of the predictions against the
+// targets; any losses.Loss can be passed as its Compute method. The loss
+// before the update is returned.
+func PartialFit(m Module, x, y []float64, loss func(preds, targets []*Value) *Value, opt Stepper) float64 {
+	l := loss(m.Output(ToValue1D(x)), ToValue1D(y))
+	l.FullBackward()
+	opt.Step()
+	opt.ZeroGrad()
+	return l.Data
+}
+
+// PartialFit updates the MLP with a single gradient step on one example,
+// minimizing the mean squared error of its outputs. The error before the
+// update is returned. Use the function PartialFit for other losses.
+func (mlp *MLP) PartialFit(x, y []float64, opt Stepper) float64 {
+	return PartialFit(mlp, x, y, meanSquaredError, opt)
+}
+
+// meanSquaredError returns the mean of the squared differences between preds and targets.
+func meanSquaredError(preds, targets []*Value) *Value {
+	if len(preds) != len(targets) {
+		panic(fmt.Sprintf("engine: PartialFit got %d outputs and %d targets", len(preds), len(targets)))
+	}
+	terms := make([]*Value, len(preds))
+	for i := range preds {
+		terms[i] = preds[i].Sub(targets[i]).Pow(2)
+	}
+	return Sum(terms).Mul(NewValue(1/float64(len(terms)), ""))
+}
@@ -0,0 +1,50 @@
+package engine
+
+import "math/rand"
+
+// Every source of randomness in the engine (weight initialization, noise,
+// weight sampling) can be given its own *rand.Rand, so that models built or
+// run in the same process draw independent, reproducible streams. A nil
+// generator falls back to the global math/rand source, which is safe for
+// concurrent use; a *rand.Rand is not, so models trained concurrently need
+// generators of their own (clones share the generator of the original).
+
+// uniform returns a random value between -1 and 1, the range weights are
+// initialized in, drawn from r or the global source if r is nil.
+func uniform(r *rand.Rand) float64 {
+	if r == nil {
+		return rand.Float64()*2 - 1
+	}
+	return r.Float64()*2 - 1
+}
+
+// unit returns a value in [0, 1) drawn from r or the global source if r is nil.
+func unit(r *rand.Rand) float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+	return r.Float64()
+}
+
+// normal returns a standard normal value drawn from r or the global source if r is nil.
+func normal(r *rand.Rand) float64 {
+	if r == nil {
+		return rand.NormFloat64()
+	}
+	return r.NormFloat64()
+}
+
+// RandSetter is implemented by modules that draw random numbers in their
+// forward pass (e.g. noise injection). Containers implement it by forwarding
+// the generator to all of their sub-modules.
+type RandSetter interface {
+	SetRand(r *rand.Rand)
+}
+
+// SetRand makes m draw the random numbers of its forward passes from r.
+// Modules that do not implement RandSetter are deterministic and left untouched.
+func SetRand(m Module, r *rand.Rand) {
+	if rs, ok := m.(RandSetter); ok {
+		rs.SetRand(r)
+	}
+}
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// savedMLP is the schema of an MLP written by MLP.Save (as JSON) and
+// MLP.SaveGob (as gob).
+type savedMLP struct {
+	Version int          `json:"version"`
+	Inputs  int          `json:"inputs"`
+	Layers  []savedLayer `json:"layers"`
+
+	// Metadata is optional, so files without it stay valid and readers that
+	// predate it ignore it.
+	Metadata *ModelMetadata `json:"metadata,omitempty"`
+}
+
+// savedLayer is the saved form of one Layer: its activation and its weights
+// as an [out][in] matrix with one bias per output.
+type savedLayer struct {
+	Outputs    int         `json:"outputs"`
+	Activation Activation  `json:"activation"`
+	Weights    [][]float64 `json:"weights"`
+	Biases     []float64   `json:"biases"`
+}
+
+// savedMLPVersion is the version of the schema written by Save and SaveGob.
+const savedMLPVersion = 1
+
+// Save writes the architecture (input size, layer sizes and activations) and
+// the weights of the MLP to w as JSON, to be read back with LoadMLP. All
+// neurons of a layer must share one activation, as with Layer.WithActivation.
+func (mlp *MLP) Save(w io.Writer) error {
+	return mlp.SaveWithMetadata(w, nil)
+}
+
+// SaveWithMetadata writes the MLP to w like Save, together with meta (which
+// may be nil), for InspectModel to read back. Empty PackageVersion and Saved
+// fields of meta are filled in.
+func (mlp *MLP) SaveWithMetadata(w io.Writer, meta *ModelMetadata) error {
+	s, err := mlp.saved(meta)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("engine: encoding MLP: %w", err)
+	}
+	return nil
+}
+
+// SaveGob writes the MLP to w like Save, but in the compact binary gob
+// encoding, which is much smaller and faster than JSON for large models.
+// Read it back with LoadMLPGob.
+func (mlp *MLP) SaveGob(w io.Writer) error {
+	return mlp.SaveGobWithMetadata(w, nil)
+}
+
+// SaveGobWithMetadata writes the MLP and meta to w like SaveWithMetadata, in
+// the gob encoding.
+func (mlp *MLP) SaveGobWithMetadata(w io.Writer, meta *ModelMetadata) error {
+	s, err := mlp.saved(meta)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(w).Encode(s); err != nil {
+		return fmt.Errorf("engine: encoding MLP: %w", err)
+	}
+	return nil
+}
+
+// saved converts the MLP and its optional metadata to the saved schema.
+func (mlp *MLP) saved(meta *ModelMetadata) (savedMLP, error) {
+	s := savedMLP{Version: savedMLPVersion, Layers: make([]savedLayer, len(mlp.Layers))}
+	if meta != nil {
+		m := *meta
+		if m.PackageVersion == "" {
+			m.PackageVersion = PackageVersion()
+		}
+		if m.Saved.IsZero() {
+			m.Saved = time.Now().UTC()
+		}
+		s.Metadata = &m
+	}
+	for i, layer := range mlp.Layers {
+		if len(layer.Neurons) == 0 {
+			return savedMLP{}, fmt.Errorf("engine: layer %d has no neurons", i+1)
+		}
+		if i == 0 {
+			s.Inputs = len(layer.Neurons[0].Weights)
+		}
+		sl := savedLayer{
+			Outputs:    len(layer.Neurons),
+			Activation: layer.Neurons[0].Activation,
+			Weights:    make([][]float64, len(layer.Neurons)),
+			Biases:     make([]float64, len(layer.Neurons)),
+		}
+		for j, neuron := range layer.Neurons {
+			if neuron.Activation != sl.Activation {
+				return savedMLP{}, fmt.Errorf("engine: layer %d mixes %v and %v activations", i+1, sl.Activation, neuron.Activation)
+			}
+			sl.Weights[j] = make([]float64, len(neuron.Weights))
+			for k, w := range neuron.Weights {
+				sl.Weights[j][k] = w.Data
+			}
+			sl.Biases[j] = neuron.Bias.Data
+		}
+		s.Layers[i] = sl
+	}
+	return s, nil
+}
+
+// LoadMLP reads an MLP written by MLP.Save from r, rebuilding its layers,
+// activations and weights.
+func LoadMLP(r io.Reader) (*MLP, error) {
+	var s savedMLP
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("engine: decoding MLP: %w", err)
+	}
+	return s.mlp()
+}
+
+// LoadMLPGob reads an MLP written by MLP.SaveGob from r.
+func LoadMLPGob(r io.Reader) (*MLP, error) {
+	var s savedMLP
+	if err := gob.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("engine: decoding MLP: %w", err)
+	}
+	return s.mlp()
+}
+
+// mlp rebuilds the saved MLP after checking the schema is consistent.
+func (s savedMLP) mlp() (*MLP, error) {
+	if s.Version != savedMLPVersion {
+		return nil, fmt.Errorf("engine: unsupported MLP format version %d", s.Version)
+	}
+	if len(s.Layers) == 0 {
+		return nil, fmt.Errorf("engine: saved MLP has no layers")
+	}
+
+	mlp := MLP{Layers: make([]*Layer, len(s.Layers))}
+	numIn := s.Inputs
+	for i, sl := range s.Layers {
+		if len(sl.Weights) != sl.Outputs || len(sl.Biases) != sl.Outputs {
+			return nil, fmt.Errorf("engine: layer %d has %d weight rows and %d biases for %d outputs",
+				i+1, len(sl.Weights), len(sl.Biases), sl.Outputs)
+		}
+		layer := Layer{Neurons: make([]*Neuron, sl.Outputs)}
+		for j, row := range sl.Weights {
+			if len(row) != numIn {
+				return nil, fmt.Errorf("engine: layer %d neuron %d has %d weights, expected %d", i+1, j+1, len(row), numIn)
+			}
+			neuron := Neuron{
+				Weights:    make([]*Value, numIn),
+				Bias:       NewValue(sl.Biases[j], "b"),
+				Activation: sl.Activation,
+			}
+			for k, w := range row {
+				neuron.Weights[k] = NewValue(w, fmt.Sprintf("w%d", k+1))
+			}
+			layer.Neurons[j] = &neuron
+		}
+		mlp.Layers[i] = &layer
+		numIn = sl.Outputs
+	}
+	return &mlp, nil
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Sequential chains modules so that the output of each one is the input of the next.
+// Unlike MLP it can hold any Module, e.g. layers interleaved with noise or
+// convolution modules.
+type Sequential struct {
+	Modules []Module
+}
+
+// NewSequential creates and returns a new Sequential container of the given modules.
+func NewSequential(modules ...Module) *Sequential {
+	return &Sequential{
+		Modules: modules,
+	}
+}
+
+// Clone returns a deep copy of every module in the chain; see CloneModule.
+func (s *Sequential) Clone() *Sequential {
+	c := Sequential{
+		Modules: make([]Module, len(s.Modules)),
+	}
+	for i := range s.Modules {
+		c.Modules[i] = CloneModule(s.Modules[i])
+	}
+	return &c
+}
+
+// String provides a formatted string representation of a Sequential container,
+// detailing each of its modules.
+func (s *Sequential) String() string {
+	str := fmt.Sprintf("Sequential with %d modules:\n", len(s.Modules))
+	for i, m := range s.Modules {
+		str += fmt.Sprintf("  Module %d:\n%v\n", i+1, m)
+	}
+	return str
+}
+
+// Output performs a forward pass through every module in order.
+func (s *Sequential) Output(ins []*Value) []*Value {
+	result := ins
+	for _, m := range s.Modules {
+		result = m.Output(result) // Each module processes the previous module's output
+	}
+	return result
+}
+
+// Parameters returns the trainable parameters of every module in order.
+func (s *Sequential) Parameters() []*Value {
+	var p []*Value
+	for _, m := range s.Modules {
+		p = append(p, m.Parameters()...) // Collect parameters from each module
+	}
+	return p
+}
+
+// SetTraining forwards the training mode to every module in the chain.
+func (s *Sequential) SetTraining(training bool) {
+	for _, m := range s.Modules {
+		SetTraining(m, training)
+	}
+}
+
+// SetRand forwards the random number generator to every module.
+func (s *Sequential) SetRand(r *rand.Rand) {
+	for _, m := range s.Modules {
+		SetRand(m, r)
+	}
+}
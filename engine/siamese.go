@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Siamese applies one shared-weight tower to several inputs, producing
+// embeddings that live in the same space. Because every branch reuses the
+// same parameters, gradients from all branches accumulate in the tower.
+// It is the usual setup for contrastive and triplet metric learning.
+type Siamese struct {
+	Tower Module
+}
+
+// NewSiamese creates and returns a new Siamese container around the given tower.
+func NewSiamese(tower Module) *Siamese {
+	return &Siamese{
+		Tower: tower,
+	}
+}
+
+// Clone returns a deep copy of the container and its tower; see CloneModule.
+func (s *Siamese) Clone() *Siamese {
+	return &Siamese{
+		Tower: CloneModule(s.Tower),
+	}
+}
+
+// String provides a formatted string representation of a Siamese container.
+func (s *Siamese) String() string {
+	return fmt.Sprintf("Siamese(\n%v\n)", s.Tower)
+}
+
+// Embed runs every input through the shared tower and returns one embedding per input.
+func (s *Siamese) Embed(inputs ...[]*Value) [][]*Value {
+	embeddings := make([][]*Value, len(inputs))
+	for i, in := range inputs {
+		embeddings[i] = s.Tower.Output(in)
+	}
+	return embeddings
+}
+
+// Pair embeds two inputs with the shared tower and returns both embeddings.
+func (s *Siamese) Pair(a, b []*Value) ([]*Value, []*Value) {
+	return s.Tower.Output(a), s.Tower.Output(b)
+}
+
+// Output embeds a single input, so a Siamese container can be used wherever a
+// Module is expected (e.g. for inference on one sample).
+func (s *Siamese) Output(ins []*Value) []*Value {
+	return s.Tower.Output(ins)
+}
+
+// Parameters returns the parameters of the shared tower.
+func (s *Siamese) Parameters() []*Value {
+	return s.Tower.Parameters()
+}
+
+// SetTraining forwards the training mode to every sub-module.
+func (s *Siamese) SetTraining(training bool) {
+	SetTraining(s.Tower, training)
+}
+
+// SetRand forwards the random number generator to the shared tower.
+func (s *Siamese) SetRand(r *rand.Rand) {
+	SetRand(s.Tower, r)
+}
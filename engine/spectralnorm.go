@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// SpectralNorm wraps a Layer and divides its weight matrix by an estimate of
+// its largest singular value, keeping the layer roughly 1-Lipschitz. This is
+// the usual way to stabilize GAN discriminators.
+// The estimate is refined by power iteration on every forward pass; U and V
+// hold the current left and right singular vector estimates and are not trained.
+type SpectralNorm struct {
+	Layer      *Layer
+	U          []float64 // Left singular vector estimate (one entry per neuron)
+	V          []float64 // Right singular vector estimate (one entry per input)
+	Iterations int       // Power iterations per forward pass
+}
+
+// NewSpectralNorm wraps the given layer with spectral normalization,
+// using one power iteration per forward pass.
+func NewSpectralNorm(l *Layer) *SpectralNorm {
+	return NewSpectralNormRand(l, nil)
+}
+
+// NewSpectralNormRand is like NewSpectralNorm but draws the starting vector of
+// the power iteration from r.
+func NewSpectralNormRand(l *Layer, r *rand.Rand) *SpectralNorm {
+	sn := SpectralNorm{
+		Layer:      l,
+		U:          make([]float64, len(l.Neurons)),
+		Iterations: 1,
+	}
+	if len(l.Neurons) > 0 {
+		sn.V = make([]float64, len(l.Neurons[0].Weights))
+	}
+
+	for i := range sn.U {
+		sn.U[i] = uniform(r) // Random starting vector for power iteration
+	}
+	normalize(sn.U)
+	return &sn
+}
+
+// Clone returns a deep copy of the wrapper, its wrapped layer and its
+// power-iteration state.
+func (sn *SpectralNorm) Clone() *SpectralNorm {
+	return &SpectralNorm{
+		Layer:      sn.Layer.Clone(),
+		U:          append([]float64(nil), sn.U...),
+		V:          append([]float64(nil), sn.V...),
+		Iterations: sn.Iterations,
+	}
+}
+
+// String provides a formatted string representation of a SpectralNorm layer.
+func (sn *SpectralNorm) String() string {
+	return fmt.Sprintf("SpectralNorm(sigma=%.4f,\n%s)", sn.Sigma(), sn.Layer.String())
+}
+
+// normalize scales x to unit length in place. A zero vector is left untouched.
+func normalize(x []float64) {
+	norm := 0.0
+	for _, xi := range x {
+		norm += xi * xi
+	}
+	norm = math.Sqrt(norm)
+	if norm == 0 {
+		return
+	}
+	for i := range x {
+		x[i] /= norm
+	}
+}
+
+// powerIteration refines U and V towards the top singular vectors of the weight matrix.
+func (sn *SpectralNorm) powerIteration() {
+	for it := 0; it < sn.Iterations; it++ {
+		// v = W^T u / ||W^T u||
+		for j := range sn.V {
+			sn.V[j] = 0
+			for i, neuron := range sn.Layer.Neurons {
+				sn.V[j] += neuron.Weights[j].Data * sn.U[i]
+			}
+		}
+		normalize(sn.V)
+
+		// u = W v / ||W v||
+		for i, neuron := range sn.Layer.Neurons {
+			sn.U[i] = 0
+			for j, w := range neuron.Weights {
+				sn.U[i] += w.Data * sn.V[j]
+			}
+		}
+		normalize(sn.U)
+	}
+}
+
+// sigma builds the spectral norm estimate u^T W v as a graph node, so gradients
+// flow through it back into the weights (u and v are treated as constants).
+func (sn *SpectralNorm) sigma() *Value {
+	s := NewValue(0.0, "")
+	for i, neuron := range sn.Layer.Neurons {
+		for j, w := range neuron.Weights {
+			s = s.Add(w.Mul(NewValue(sn.U[i]*sn.V[j], "")))
+		}
+	}
+	s.Label = "sigma"
+	return s
+}
+
+// Sigma returns the current estimate of the largest singular value of the weights.
+func (sn *SpectralNorm) Sigma() float64 {
+	return sn.sigma().Data
+}
+
+// Output runs the power iteration, then computes the outputs of all neurons
+// using the weights divided by the estimated spectral norm.
+func (sn *SpectralNorm) Output(inputs []*Value) []*Value {
+	sn.powerIteration()
+	sigma := sn.sigma()
+
+	out := make([]*Value, len(sn.Layer.Neurons))
+	for i, neuron := range sn.Layer.Neurons {
+		w := make([]*Value, len(neuron.Weights))
+		for j := range neuron.Weights {
+			w[j] = neuron.Weights[j].Div(sigma)
+		}
+		out[i] = neuron.outputWith(w, inputs)
+		out[i].Label = fmt.Sprintf("layer_neuron_%d_output", i+1)
+	}
+	return out
+}
+
+// Parameters returns the trainable parameters of the wrapped layer.
+func (sn *SpectralNorm) Parameters() []*Value {
+	return sn.Layer.Parameters()
+}
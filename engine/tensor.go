@@ -0,0 +1,114 @@
+package engine
+
+import "fmt"
+
+// Tensor is a batch of samples stored as a flat slice of Values.
+// Shape[0] is the batch size and the remaining dimensions describe a single
+// sample; Data holds the samples one after another in row-major order.
+type Tensor struct {
+	Data  []*Value
+	Shape []int
+}
+
+// NewTensor wraps data in a Tensor of the given shape.
+// It panics if the shape does not match the number of values.
+func NewTensor(data []*Value, shape ...int) *Tensor {
+	size := 1
+	for _, d := range shape {
+		size *= d
+	}
+	if size != len(data) {
+		panic(fmt.Sprintf("engine: tensor shape %v needs %d values, got %d", shape, size, len(data)))
+	}
+	return &Tensor{
+		Data:  data,
+		Shape: append([]int(nil), shape...),
+	}
+}
+
+// StackRows builds a batch Tensor from per-sample Value slices of equal length.
+func StackRows(rows [][]*Value) *Tensor {
+	if len(rows) == 0 {
+		return NewTensor(nil, 0, 0)
+	}
+	data := make([]*Value, 0, len(rows)*len(rows[0]))
+	for _, row := range rows {
+		if len(row) != len(rows[0]) {
+			panic(fmt.Sprintf("engine: cannot stack rows of length %d and %d", len(rows[0]), len(row)))
+		}
+		data = append(data, row...)
+	}
+	return NewTensor(data, len(rows), len(rows[0]))
+}
+
+// TensorFromFloats converts a 2D slice of float64 (one row per sample) into a batch Tensor.
+func TensorFromFloats(data [][]float64) *Tensor {
+	return StackRows(ToValue2D(data))
+}
+
+// String provides a formatted string representation of a Tensor's shape.
+func (t *Tensor) String() string {
+	return fmt.Sprintf("Tensor(shape=%v)", t.Shape)
+}
+
+// BatchSize returns the number of samples in the Tensor.
+func (t *Tensor) BatchSize() int {
+	if len(t.Shape) == 0 {
+		return 0
+	}
+	return t.Shape[0]
+}
+
+// Row returns the Values of sample i. The slice aliases the Tensor's data.
+func (t *Tensor) Row(i int) []*Value {
+	n := t.BatchSize()
+	if n == 0 {
+		return nil
+	}
+	stride := len(t.Data) / n
+	return t.Data[i*stride : (i+1)*stride]
+}
+
+// Rows returns the Values of every sample, one slice per sample.
+func (t *Tensor) Rows() [][]*Value {
+	rows := make([][]*Value, t.BatchSize())
+	for i := range rows {
+		rows[i] = t.Row(i)
+	}
+	return rows
+}
+
+// Floats returns the data of every sample as float64 slices.
+func (t *Tensor) Floats() [][]float64 {
+	rows := t.Rows()
+	out := make([][]float64, len(rows))
+	for i, row := range rows {
+		out[i] = make([]float64, len(row))
+		for j, v := range row {
+			out[i][j] = v.Data
+		}
+	}
+	return out
+}
+
+// BatchModule is a Module that can also process a whole batch at once.
+// Modules whose computation couples samples in a batch (e.g. batch statistics)
+// implement it; for all others Forward falls back to per-sample Output calls.
+type BatchModule interface {
+	Module
+	Forward(x *Tensor) *Tensor
+}
+
+// Forward runs a batch through m. If m implements BatchModule its Forward method
+// is used, otherwise every sample is passed through m.Output and the results
+// are stacked back into a Tensor.
+func Forward(m Module, x *Tensor) *Tensor {
+	if bm, ok := m.(BatchModule); ok {
+		return bm.Forward(x)
+	}
+	outs := make([][]*Value, x.BatchSize())
+	for i, row := range x.Rows() {
+		outs[i] = m.Output(row)
+	}
+	return StackRows(outs)
+}
@@ -0,0 +1,227 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// ConvTranspose2D is a transposed ("fractionally strided") convolution: every
+// input value scatters a weighted copy of its kernel into the output, growing
+// the feature maps. It is the usual learned upsampling step in decoders and
+// GAN generators. Inputs and outputs use the flattened layout of the other
+// convolution modules.
+type ConvTranspose2D struct {
+	InChannels, OutChannels int
+	Height, Width           int
+	KernelSize              int
+	Stride, Padding         int
+	Kernels                 [][][]*Value // [out][in] kernels of KernelSize*KernelSize weights
+	Biases                  []*Value     // One bias per output channel
+}
+
+// NewConvTranspose2D creates a transposed convolution mapping inChannels x height x width
+// inputs to outChannels feature maps. Kernels and biases are initialized with
+// random values between -1 and 1.
+func NewConvTranspose2D(inChannels, outChannels, height, width, kernelSize, stride, padding int) *ConvTranspose2D {
+	return NewConvTranspose2DRand(inChannels, outChannels, height, width, kernelSize, stride, padding, nil)
+}
+
+// NewConvTranspose2DRand is like NewConvTranspose2D but draws the initial
+// kernels and biases from r.
+func NewConvTranspose2DRand(inChannels, outChannels, height, width, kernelSize, stride, padding int, r *rand.Rand) *ConvTranspose2D {
+	ct := ConvTranspose2D{
+		InChannels:  inChannels,
+		OutChannels: outChannels,
+		Height:      height,
+		Width:       width,
+		KernelSize:  kernelSize,
+		Stride:      stride,
+		Padding:     padding,
+		Kernels:     make([][][]*Value, outChannels),
+		Biases:      make([]*Value, outChannels),
+	}
+
+	for o := range ct.Kernels {
+		ct.Kernels[o] = make([][]*Value, inChannels)
+		for i := range ct.Kernels[o] {
+			ct.Kernels[o][i] = make([]*Value, kernelSize*kernelSize)
+			for k := range ct.Kernels[o][i] {
+				ct.Kernels[o][i][k] = NewValue(uniform(r), fmt.Sprintf("k%d_%d_%d", o+1, i+1, k+1))
+			}
+		}
+		ct.Biases[o] = NewValue(uniform(r), fmt.Sprintf("b%d", o+1))
+	}
+	return &ct
+}
+
+// Clone returns a deep copy of the transposed convolution.
+func (ct *ConvTranspose2D) Clone() *ConvTranspose2D {
+	c := *ct
+	c.Kernels = make([][][]*Value, len(ct.Kernels))
+	for o := range ct.Kernels {
+		c.Kernels[o] = make([][]*Value, len(ct.Kernels[o]))
+		for i := range ct.Kernels[o] {
+			c.Kernels[o][i] = leafCopies(ct.Kernels[o][i])
+		}
+	}
+	c.Biases = leafCopies(ct.Biases)
+	return &c
+}
+
+// String provides a formatted string representation of a ConvTranspose2D.
+func (ct *ConvTranspose2D) String() string {
+	outH, outW := ct.OutShape()
+	return fmt.Sprintf("ConvTranspose2D(%dx%dx%d -> %dx%dx%d, kernel=%d, stride=%d, padding=%d)",
+		ct.InChannels, ct.Height, ct.Width, ct.OutChannels, outH, outW, ct.KernelSize, ct.Stride, ct.Padding)
+}
+
+// OutShape returns the spatial size (height, width) of the output feature maps.
+func (ct *ConvTranspose2D) OutShape() (int, int) {
+	return (ct.Height-1)*ct.Stride - 2*ct.Padding + ct.KernelSize,
+		(ct.Width-1)*ct.Stride - 2*ct.Padding + ct.KernelSize
+}
+
+// Output scatters every input value through the kernels into the output feature maps.
+func (ct *ConvTranspose2D) Output(ins []*Value) []*Value {
+	outH, outW := ct.OutShape()
+	plane := outH * outW
+	out := make([]*Value, ct.OutChannels*plane)
+	for o := 0; o < ct.OutChannels; o++ {
+		for pos := 0; pos < plane; pos++ {
+			out[o*plane+pos] = ct.Biases[o]
+		}
+	}
+
+	for i := 0; i < ct.InChannels; i++ {
+		for y := 0; y < ct.Height; y++ {
+			for x := 0; x < ct.Width; x++ {
+				in := ins[i*ct.Height*ct.Width+y*ct.Width+x]
+				for ky := 0; ky < ct.KernelSize; ky++ {
+					for kx := 0; kx < ct.KernelSize; kx++ {
+						oy := y*ct.Stride + ky - ct.Padding
+						ox := x*ct.Stride + kx - ct.Padding
+						if oy < 0 || oy >= outH || ox < 0 || ox >= outW {
+							continue // Cropped away by the padding
+						}
+						for o := 0; o < ct.OutChannels; o++ {
+							idx := o*plane + oy*outW + ox
+							out[idx] = out[idx].Add(ct.Kernels[o][i][ky*ct.KernelSize+kx].Mul(in))
+						}
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// Parameters returns all kernels followed by the biases.
+func (ct *ConvTranspose2D) Parameters() []*Value {
+	var p []*Value
+	for _, perOut := range ct.Kernels {
+		for _, k := range perOut {
+			p = append(p, k...)
+		}
+	}
+	return append(p, ct.Biases...)
+}
+
+// UpsampleMode selects how Upsample fills in the new pixels.
+type UpsampleMode int
+
+const (
+	// UpsampleNearest repeats every input pixel Scale x Scale times.
+	UpsampleNearest UpsampleMode = iota
+	// UpsampleBilinear interpolates linearly between the four nearest input pixels.
+	UpsampleBilinear
+)
+
+// Upsample enlarges flattened channels x height x width feature maps by an
+// integer Scale factor. It has no trainable parameters.
+type Upsample struct {
+	Channels, Height, Width int
+	Scale                   int
+	Mode                    UpsampleMode
+}
+
+// NewUpsample creates an Upsample module for channels x height x width inputs.
+func NewUpsample(channels, height, width, scale int, mode UpsampleMode) *Upsample {
+	return &Upsample{
+		Channels: channels,
+		Height:   height,
+		Width:    width,
+		Scale:    scale,
+		Mode:     mode,
+	}
+}
+
+// Clone returns a copy of the Upsample configuration.
+func (u *Upsample) Clone() *Upsample {
+	c := *u
+	return &c
+}
+
+// String provides a formatted string representation of an Upsample module.
+func (u *Upsample) String() string {
+	mode := "nearest"
+	if u.Mode == UpsampleBilinear {
+		mode = "bilinear"
+	}
+	return fmt.Sprintf("Upsample(%dx%dx%d, scale=%d, mode=%s)", u.Channels, u.Height, u.Width, u.Scale, mode)
+}
+
+// OutShape returns the spatial size (height, width) of the output feature maps.
+func (u *Upsample) OutShape() (int, int) {
+	return u.Height * u.Scale, u.Width * u.Scale
+}
+
+// sourceCoord maps an output coordinate back onto the input grid (half-pixel
+// centers, as in most frameworks) and returns the two neighbouring input
+// indices together with the interpolation weight of the second one.
+func (u *Upsample) sourceCoord(out, size int) (int, int, float64) {
+	src := (float64(out)+0.5)/float64(u.Scale) - 0.5
+	if src < 0 {
+		src = 0
+	}
+	lo := int(math.Floor(src))
+	hi := lo + 1
+	if hi >= size {
+		hi = size - 1
+	}
+	return lo, hi, src - float64(lo)
+}
+
+// Output upsamples every channel of the flattened input.
+func (u *Upsample) Output(ins []*Value) []*Value {
+	outH, outW := u.OutShape()
+	out := make([]*Value, 0, u.Channels*outH*outW)
+	at := func(c, y, x int) *Value {
+		return ins[c*u.Height*u.Width+y*u.Width+x]
+	}
+
+	for c := 0; c < u.Channels; c++ {
+		for oy := 0; oy < outH; oy++ {
+			for ox := 0; ox < outW; ox++ {
+				if u.Mode == UpsampleNearest {
+					out = append(out, at(c, oy/u.Scale, ox/u.Scale))
+					continue
+				}
+
+				y0, y1, wy := u.sourceCoord(oy, u.Height)
+				x0, x1, wx := u.sourceCoord(ox, u.Width)
+				v := at(c, y0, x0).Mul(NewValue((1-wy)*(1-wx), "")).
+					Add(at(c, y0, x1).Mul(NewValue((1-wy)*wx, ""))).
+					Add(at(c, y1, x0).Mul(NewValue(wy*(1-wx), ""))).
+					Add(at(c, y1, x1).Mul(NewValue(wy*wx, "")))
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// Parameters returns nil; Upsample has nothing to train.
+func (u *Upsample) Parameters() []*Value {
+	return nil
+}
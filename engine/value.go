@@ -33,6 +33,21 @@ func NewValue(val float64, label string) *Value {
 	}
 }
 
+// leafCopy returns a new leaf Value with the same data and label, detached from
+// any computation graph. It is used to deep-copy trainable parameters.
+func (val *Value) leafCopy() *Value {
+	return NewValue(val.Data, val.Label)
+}
+
+// leafCopies applies leafCopy to every Value in vs.
+func leafCopies(vs []*Value) []*Value {
+	out := make([]*Value, len(vs))
+	for i, v := range vs {
+		out[i] = v.leafCopy()
+	}
+	return out
+}
+
 // Add performs element-wise addition between two Values.
 // It returns a new Value representing the sum and sets up its backward function.
 func (a *Value) Add(b *Value) *Value {
@@ -130,6 +145,162 @@ func (a *Value) Tanh() *Value {
 	return out
 }
 
+// Sum adds up a slice of Values in a single graph node, which keeps the graph
+// shallow compared to chaining Add calls. The sum of an empty slice is 0.
+func Sum(vs []*Value) *Value {
+	out := &Value{
+		Grad:  0,
+		Prev:  append([]*Value(nil), vs...),
+		Op:    "sum",
+		Label: "",
+	}
+	for _, v := range vs {
+		out.Data += v.Data
+	}
+
+	out.Backward = func() {
+		for _, v := range out.Prev {
+			v.Grad += out.Grad
+		}
+	}
+
+	return out
+}
+
+// Exp computes e raised to the power of a Value.
+// It returns a new Value representing the result and sets up its backward function.
+func (a *Value) Exp() *Value {
+	out := &Value{
+		Data:  math.Exp(a.Data),
+		Grad:  0,
+		Prev:  []*Value{a},
+		Op:    "exp",
+		Label: "",
+	}
+
+	out.Backward = func() {
+		a.Grad += out.Grad * out.Data // d/dx e^x = e^x
+	}
+
+	return out
+}
+
+// ReLU applies the rectified linear unit activation function, max(0, a), to a Value.
+// It returns a new Value representing the result and sets up its backward function.
+func (a *Value) ReLU() *Value {
+	out := &Value{
+		Data:  math.Max(0, a.Data),
+		Grad:  0,
+		Prev:  []*Value{a},
+		Op:    "relu",
+		Label: "",
+	}
+
+	out.Backward = func() {
+		if a.Data > 0 {
+			a.Grad += out.Grad
+		}
+	}
+
+	return out
+}
+
+// Sigmoid applies the logistic sigmoid activation function, 1 / (1 + e^-a), to a Value.
+// It is computed in a numerically stable way for large negative and positive inputs.
+// It returns a new Value representing the result and sets up its backward function.
+func (a *Value) Sigmoid() *Value {
+	var s float64
+	if a.Data >= 0 {
+		s = 1 / (1 + math.Exp(-a.Data))
+	} else {
+		e := math.Exp(a.Data) // Avoids overflow of e^-a for very negative a
+		s = e / (1 + e)
+	}
+	out := &Value{
+		Data:  s,
+		Grad:  0,
+		Prev:  []*Value{a},
+		Op:    "sigmoid",
+		Label: "",
+	}
+
+	out.Backward = func() {
+		a.Grad += out.Grad * out.Data * (1 - out.Data)
+	}
+
+	return out
+}
+
+// Softmax converts a slice of Values (logits) into a probability distribution.
+// The maximum logit is subtracted first for numerical stability; since it is a
+// constant shift, the result and its gradients are unchanged.
+func Softmax(vs []*Value) []*Value {
+	if len(vs) == 0 {
+		return nil
+	}
+	maxData := vs[0].Data
+	for _, v := range vs[1:] {
+		maxData = math.Max(maxData, v.Data)
+	}
+
+	exps := make([]*Value, len(vs))
+	sum := NewValue(0.0, "")
+	for i, v := range vs {
+		exps[i] = v.Sub(NewValue(maxData, "")).Exp()
+		sum = sum.Add(exps[i])
+	}
+
+	out := make([]*Value, len(vs))
+	for i := range exps {
+		out[i] = exps[i].Div(sum)
+		out[i].Label = fmt.Sprintf("softmax_%d", i+1)
+	}
+	return out
+}
+
+// LogSumExp computes log(sum(exp(vs))) as a single graph node, shifting by the
+// maximum value so that large inputs do not overflow. The gradient with respect
+// to each input is its softmax probability.
+func LogSumExp(vs []*Value) *Value {
+	maxData := math.Inf(-1)
+	for _, v := range vs {
+		maxData = math.Max(maxData, v.Data)
+	}
+	sum := 0.0
+	for _, v := range vs {
+		sum += math.Exp(v.Data - maxData)
+	}
+
+	out := &Value{
+		Data:  maxData + math.Log(sum),
+		Grad:  0,
+		Prev:  append([]*Value(nil), vs...),
+		Op:    "logsumexp",
+		Label: "",
+	}
+
+	out.Backward = func() {
+		for _, v := range vs {
+			v.Grad += out.Grad * math.Exp(v.Data-out.Data) // softmax_i
+		}
+	}
+
+	return out
+}
+
+// LogSoftmax converts a slice of Values (logits) into log-probabilities,
+// v_i - LogSumExp(vs). It is numerically stable even when Softmax would
+// underflow to zero, and pairs with losses.NLL.
+func LogSoftmax(vs []*Value) []*Value {
+	lse := LogSumExp(vs)
+	out := make([]*Value, len(vs))
+	for i, v := range vs {
+		out[i] = v.Sub(lse)
+		out[i].Label = fmt.Sprintf("log_softmax_%d", i+1)
+	}
+	return out
+}
+
 // reversedCopy creates a new slice with elements copied in reverse order.
 func reversedCopy[T any](s []T) []T {
 	n := len(s)
@@ -166,6 +337,7 @@ func createTopoNet(L *Value) []*Value {
 // The gradient of the current Value is initialized to 1.0 before backpropagation.
 func (v *Value) FullBackward() {
 	topo := createTopoNet(v)
+	logger.Debug("backward pass", "output", v.Label, "nodes", len(topo))
 
 	// Reset all gradients in the graph to zero before starting new backprop
 	for _, node := range topo {
@@ -226,4 +398,4 @@ func TestValue() {
 	}
 	fmt.Println("--- End TestValue ---")
 	fmt.Println()
-}
\ No newline at end of file
+}
@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+)
+
+// WeightNorm wraps a Layer and reparameterizes each neuron's weight vector as
+// w = g * v / ||v||, decoupling the length of the weights (g) from their
+// direction (v). G and V are the trainable parameters in place of the wrapped
+// layer's weights; biases are still taken from the wrapped layer.
+type WeightNorm struct {
+	Layer *Layer
+	G     []*Value   // One scale per neuron
+	V     [][]*Value // One direction vector per neuron
+}
+
+// NewWeightNorm wraps the given layer with weight normalization.
+// V is initialized from the layer's current weights and G from their norms,
+// so the wrapped layer initially computes exactly the same outputs.
+func NewWeightNorm(l *Layer) *WeightNorm {
+	wn := WeightNorm{
+		Layer: l,
+		G:     make([]*Value, len(l.Neurons)),
+		V:     make([][]*Value, len(l.Neurons)),
+	}
+
+	for i, neuron := range l.Neurons {
+		norm := 0.0
+		wn.V[i] = make([]*Value, len(neuron.Weights))
+		for j, w := range neuron.Weights {
+			wn.V[i][j] = NewValue(w.Data, fmt.Sprintf("v%d", j+1))
+			norm += w.Data * w.Data
+		}
+		wn.G[i] = NewValue(math.Sqrt(norm), "g")
+	}
+	return &wn
+}
+
+// Clone returns a deep copy of the wrapper and its wrapped layer.
+func (wn *WeightNorm) Clone() *WeightNorm {
+	c := WeightNorm{
+		Layer: wn.Layer.Clone(),
+		G:     leafCopies(wn.G),
+		V:     make([][]*Value, len(wn.V)),
+	}
+	for i := range wn.V {
+		c.V[i] = leafCopies(wn.V[i])
+	}
+	return &c
+}
+
+// String provides a formatted string representation of a WeightNorm layer.
+func (wn *WeightNorm) String() string {
+	return fmt.Sprintf("WeightNorm(\n%s)", wn.Layer.String())
+}
+
+// weights builds the effective weight vector g * v / ||v|| of neuron i as graph
+// nodes, so gradients flow back into G and V.
+func (wn *WeightNorm) weights(i int) []*Value {
+	sumSq := NewValue(0.0, "")
+	for _, v := range wn.V[i] {
+		sumSq = sumSq.Add(v.Mul(v))
+	}
+	scale := wn.G[i].Div(sumSq.Pow(0.5)) // g / ||v||
+	scale.Label = "g/||v||"
+
+	w := make([]*Value, len(wn.V[i]))
+	for j, v := range wn.V[i] {
+		w[j] = v.Mul(scale)
+	}
+	return w
+}
+
+// Output computes the outputs of all neurons using the normalized weights.
+func (wn *WeightNorm) Output(inputs []*Value) []*Value {
+	out := make([]*Value, len(wn.Layer.Neurons))
+	for i, neuron := range wn.Layer.Neurons {
+		out[i] = neuron.outputWith(wn.weights(i), inputs)
+		out[i].Label = fmt.Sprintf("layer_neuron_%d_output", i+1)
+	}
+	return out
+}
+
+// Parameters returns the trainable parameters of the wrapper: for each neuron,
+// its direction vector V, its scale G and the wrapped layer's bias.
+func (wn *WeightNorm) Parameters() []*Value {
+	var p []*Value
+	for i, neuron := range wn.Layer.Neurons {
+		p = append(p, wn.V[i]...)
+		p = append(p, wn.G[i], neuron.Bias)
+	}
+	return p
+}
+
+// Fold writes the current effective weights back into the wrapped Layer, so the
+// layer can be used (or saved) on its own without the wrapper.
+func (wn *WeightNorm) Fold() {
+	for i, neuron := range wn.Layer.Neurons {
+		for j, w := range wn.weights(i) {
+			neuron.Weights[j].Data = w.Data
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Package gguf writes models in GGUF, the single-file format of the ggml
+// ecosystem (llama.cpp and related tools), so networks trained here can be
+// loaded there. A file holds typed key/value metadata followed by named
+// tensors; see ExportMLP for the keys describing an MLP.
+package gguf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/npy"
+)
+
+// Version is the GGUF version written.
+const Version = 3
+
+// Alignment is the byte alignment of the tensor data, stored as general.alignment.
+const Alignment = 32
+
+// Metadata value types of the GGUF specification.
+const (
+	typeUint8   = 0
+	typeInt8    = 1
+	typeUint16  = 2
+	typeInt16   = 3
+	typeUint32  = 4
+	typeInt32   = 5
+	typeFloat32 = 6
+	typeBool    = 7
+	typeString  = 8
+	typeArray   = 9
+	typeUint64  = 10
+	typeInt64   = 11
+	typeFloat64 = 12
+)
+
+// ggmlTypeF32 is the ggml tensor type of 32-bit floats.
+const ggmlTypeF32 = 0
+
+// Write writes a GGUF file with the given metadata and tensors, stored as
+// 32-bit floats in order of name. Metadata values may be strings, bools,
+// int, int32, int64, uint32, uint64, float32, float64 or slices of those
+// (except bool); general.alignment is added.
+func Write(w io.Writer, metadata map[string]any, tensors map[string]*npy.Array) error {
+	meta := map[string]any{"general.alignment": uint32(Alignment)}
+	for k, v := range metadata {
+		meta[k] = v
+	}
+
+	var b []byte
+	b = append(b, "GGUF"...)
+	b = binary.LittleEndian.AppendUint32(b, Version)
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(tensors)))
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(meta)))
+	for _, k := range sortedKeys(meta) {
+		b = appendString(b, k)
+		var err error
+		if b, err = appendValue(b, meta[k]); err != nil {
+			return fmt.Errorf("gguf: metadata %q: %w", k, err)
+		}
+	}
+
+	// Tensor infos; the data of each tensor starts at an aligned offset
+	names := sortedKeys(tensors)
+	offset := uint64(0)
+	for _, name := range names {
+		a := tensors[name]
+		if len(a.Data) != a.Size() {
+			return fmt.Errorf("gguf: tensor %q has %d values for shape %v", name, len(a.Data), a.Shape)
+		}
+		b = appendString(b, name)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(a.Shape)))
+		for d := len(a.Shape) - 1; d >= 0; d-- {
+			b = binary.LittleEndian.AppendUint64(b, uint64(a.Shape[d])) // ggml lists the innermost dimension first
+		}
+		b = binary.LittleEndian.AppendUint32(b, ggmlTypeF32)
+		b = binary.LittleEndian.AppendUint64(b, offset)
+		offset = align(offset + 4*uint64(len(a.Data)))
+	}
+
+	b = pad(b)
+	for _, name := range names {
+		for _, v := range tensors[name].Data {
+			b = binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v)))
+		}
+		b = pad(b)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// ExportMLP writes the weights of mlp to w as a GGUF file named name. The
+// tensors are named as by engine.NamedParameters ("layers.0.weight" with
+// ggml dimensions [inputs, outputs], "layers.0.bias"), and the architecture
+// is described by the metadata general.architecture = "mlp",
+// mlp.input_size, mlp.layer_sizes and mlp.activations (one name per layer,
+// see engine.Activation).
+func ExportMLP(w io.Writer, mlp *engine.MLP, name string) error {
+	if len(mlp.Layers) == 0 || len(mlp.Layers[0].Neurons) == 0 {
+		return fmt.Errorf("gguf: MLP has no layers")
+	}
+	sizes := make([]uint32, len(mlp.Layers))
+	acts := make([]string, len(mlp.Layers))
+	for i, layer := range mlp.Layers {
+		if len(layer.Neurons) == 0 {
+			return fmt.Errorf("gguf: layer %d has no neurons", i+1)
+		}
+		sizes[i] = uint32(len(layer.Neurons))
+		acts[i] = layer.Neurons[0].Activation.String()
+		for _, neuron := range layer.Neurons {
+			if neuron.Activation != layer.Neurons[0].Activation {
+				return fmt.Errorf("gguf: layer %d mixes activations", i+1)
+			}
+		}
+	}
+	meta := map[string]any{
+		"general.architecture": "mlp",
+		"general.name":         name,
+		"mlp.input_size":       uint32(len(mlp.Layers[0].Neurons[0].Weights)),
+		"mlp.layer_sizes":      sizes,
+		"mlp.activations":      acts,
+	}
+	return Write(w, meta, npy.Params(mlp))
+}
+
+// appendString appends a GGUF string: its length as uint64, then its bytes.
+func appendString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendValue appends the type and value of a metadata entry.
+func appendValue(b []byte, v any) ([]byte, error) {
+	le := binary.LittleEndian
+	switch v := v.(type) {
+	case string:
+		return appendString(le.AppendUint32(b, typeString), v), nil
+	case bool:
+		x := byte(0)
+		if v {
+			x = 1
+		}
+		return append(le.AppendUint32(b, typeBool), x), nil
+	case int:
+		return le.AppendUint64(le.AppendUint32(b, typeInt64), uint64(v)), nil
+	case int32:
+		return le.AppendUint32(le.AppendUint32(b, typeInt32), uint32(v)), nil
+	case int64:
+		return le.AppendUint64(le.AppendUint32(b, typeInt64), uint64(v)), nil
+	case uint32:
+		return le.AppendUint32(le.AppendUint32(b, typeUint32), v), nil
+	case uint64:
+		return le.AppendUint64(le.AppendUint32(b, typeUint64), v), nil
+	case float32:
+		return le.AppendUint32(le.AppendUint32(b, typeFloat32), math.Float32bits(v)), nil
+	case float64:
+		return le.AppendUint64(le.AppendUint32(b, typeFloat64), math.Float64bits(v)), nil
+	case []string:
+		return appendArray(b, typeString, v, func(b []byte, x string) []byte { return appendString(b, x) }), nil
+	case []int:
+		return appendArray(b, typeInt64, v, func(b []byte, x int) []byte { return le.AppendUint64(b, uint64(x)) }), nil
+	case []int32:
+		return appendArray(b, typeInt32, v, func(b []byte, x int32) []byte { return le.AppendUint32(b, uint32(x)) }), nil
+	case []int64:
+		return appendArray(b, typeInt64, v, func(b []byte, x int64) []byte { return le.AppendUint64(b, uint64(x)) }), nil
+	case []uint32:
+		return appendArray(b, typeUint32, v, le.AppendUint32), nil
+	case []uint64:
+		return appendArray(b, typeUint64, v, le.AppendUint64), nil
+	case []float32:
+		return appendArray(b, typeFloat32, v, func(b []byte, x float32) []byte { return le.AppendUint32(b, math.Float32bits(x)) }), nil
+	case []float64:
+		return appendArray(b, typeFloat64, v, func(b []byte, x float64) []byte { return le.AppendUint64(b, math.Float64bits(x)) }), nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+// appendArray appends an array value: the array type, the element type, the
+// length and the elements.
+func appendArray[T any](b []byte, elemType uint32, vs []T, appendElem func([]byte, T) []byte) []byte {
+	b = binary.LittleEndian.AppendUint32(b, typeArray)
+	b = binary.LittleEndian.AppendUint32(b, elemType)
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(vs)))
+	for _, v := range vs {
+		b = appendElem(b, v)
+	}
+	return b
+}
+
+// align rounds n up to a multiple of Alignment.
+func align(n uint64) uint64 {
+	return (n + Alignment - 1) / Alignment * Alignment
+}
+
+// pad appends zero bytes until len(b) is a multiple of Alignment.
+func pad(b []byte) []byte {
+	for len(b)%Alignment != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// sortedKeys returns the keys of m in order, so files are deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+// Package golearn adapts datasets of the golearn machine-learning library
+// (github.com/sjwhitworth/golearn) for training with this module, so its
+// CSV/ARFF loaders, filters and splits can feed a train.Trainer directly.
+package golearn
+
+import (
+	"fmt"
+
+	"github.com/sjwhitworth/golearn/base"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+)
+
+// Dataset is a data.Dataset view of a golearn FixedDataGrid. The non-class
+// attributes form the input vector and the class attributes the target
+// vector, in the grid's attribute order. Float attributes are used as they
+// are, binary attributes become 0 or 1 and categorical attributes are
+// one-hot encoded over their values, so a categorical class attribute gives
+// targets for softmax cross-entropy. Examples are converted on every Get;
+// the grid must not gain categorical values while the Dataset is in use.
+type Dataset struct {
+	Grid base.FixedDataGrid
+
+	inputs, targets []column
+	rows            int
+}
+
+// column is one attribute of the grid and how it is encoded.
+type column struct {
+	spec   base.AttributeSpec
+	values []string // Values of a categorical attribute, one-hot encoded; nil otherwise
+	binary bool
+}
+
+// NewDataset wraps grid. It fails if the grid has no class attribute or an
+// attribute that is not float, binary or categorical.
+func NewDataset(grid base.FixedDataGrid) (*Dataset, error) {
+	classes := grid.AllClassAttributes()
+	if len(classes) == 0 {
+		return nil, fmt.Errorf("golearn: grid has no class attribute")
+	}
+	var nonClass []base.Attribute // In grid order, unlike base.NonClassAttributes
+	for _, attr := range grid.AllAttributes() {
+		if !isClass(attr, classes) {
+			nonClass = append(nonClass, attr)
+		}
+	}
+	inputs, err := columns(grid, nonClass)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := columns(grid, classes)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := grid.Size()
+	return &Dataset{Grid: grid, inputs: inputs, targets: targets, rows: rows}, nil
+}
+
+// isClass reports whether attr is one of the class attributes.
+func isClass(attr base.Attribute, classes []base.Attribute) bool {
+	for _, c := range classes {
+		if attr.Equals(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// columns resolves the attributes of the grid and their encoding.
+func columns(grid base.FixedDataGrid, attrs []base.Attribute) ([]column, error) {
+	cols := make([]column, len(attrs))
+	for i, attr := range attrs {
+		spec, err := grid.GetAttribute(attr)
+		if err != nil {
+			return nil, fmt.Errorf("golearn: attribute %s: %w", attr.GetName(), err)
+		}
+		cols[i].spec = spec
+		switch a := attr.(type) {
+		case *base.FloatAttribute:
+		case *base.BinaryAttribute:
+			cols[i].binary = true
+		case *base.CategoricalAttribute:
+			cols[i].values = a.GetValues()
+		default:
+			return nil, fmt.Errorf("golearn: attribute %s of type %T is not supported", attr.GetName(), attr)
+		}
+	}
+	return cols, nil
+}
+
+// Len returns the number of rows of the grid.
+func (ds *Dataset) Len() int {
+	return ds.rows
+}
+
+// Get converts row i of the grid to an example.
+func (ds *Dataset) Get(i int) data.Example {
+	return data.Example{
+		Input:  ds.encode(ds.inputs, i),
+		Target: ds.encode(ds.targets, i),
+	}
+}
+
+// encode converts the values of the columns in row i to floats.
+func (ds *Dataset) encode(cols []column, i int) []float64 {
+	var out []float64
+	for _, c := range cols {
+		b := ds.Grid.Get(c.spec, i)
+		switch {
+		case c.values != nil:
+			oneHot := make([]float64, len(c.values))
+			oneHot[base.UnpackBytesToU64(b)] = 1
+			out = append(out, oneHot...)
+		case c.binary:
+			v := 0.0
+			if b[0] > 0 {
+				v = 1
+			}
+			out = append(out, v)
+		default:
+			out = append(out, base.UnpackBytesToFloat(b))
+		}
+	}
+	return out
+}
+
+// Classes returns the values of the first class attribute if it is
+// categorical, in the order of the one-hot target positions, so predictions
+// can be mapped back to labels; nil otherwise.
+func (ds *Dataset) Classes() []string {
+	return ds.targets[0].values
+}
@@ -0,0 +1,164 @@
+// Package gonum converts between this module's layers and tensors and the
+// matrices of gonum (gonum.org/v1/gonum/mat), so data and weights can be
+// pre- or post-processed with gonum's linear algebra.
+//
+// The engine stores every scalar as its own *engine.Value with a gradient, so
+// there is no float64 slice a mat.Dense could share. Two kinds of
+// conversion are therefore offered:
+//
+//   - Copies (WeightsToDense, BiasesToVec, TensorToDense, DenseToTensor,
+//     SetWeights, SetBiases) are independent of their source: changing one
+//     never affects the other. Use them to hand data to gonum routines that
+//     need a *mat.Dense.
+//   - Views (WeightsView, TensorView) implement mat.Matrix and mat.Mutable
+//     directly on the Values: At reads their current data and Set writes
+//     through to them, with no copying. They are slower per element than a
+//     mat.Dense but always up to date, e.g. while training.
+package gonum
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Both views are gonum matrices that can be modified in place.
+var (
+	_ mat.Mutable = WeightsView{}
+	_ mat.Mutable = TensorView{}
+)
+
+// WeightsToDense returns a copy of the weights of l as an [outputs x inputs]
+// matrix: row i holds the weights of neuron i.
+func WeightsToDense(l *engine.Layer) *mat.Dense {
+	return mat.DenseCopyOf(WeightsView{Layer: l})
+}
+
+// BiasesToVec returns a copy of the biases of l, one per neuron.
+func BiasesToVec(l *engine.Layer) *mat.VecDense {
+	b := make([]float64, len(l.Neurons))
+	for i, neuron := range l.Neurons {
+		b[i] = neuron.Bias.Data
+	}
+	return mat.NewVecDense(len(b), b)
+}
+
+// SetWeights copies the [outputs x inputs] matrix w into the weights of l.
+func SetWeights(l *engine.Layer, w mat.Matrix) error {
+	v := WeightsView{Layer: l}
+	if r, c := v.Dims(); !sameDims(w, r, c) {
+		wr, wc := w.Dims()
+		return fmt.Errorf("gonum: %dx%d matrix for a layer of %d neurons with %d inputs", wr, wc, r, c)
+	}
+	v.copyFrom(w)
+	return nil
+}
+
+// SetBiases copies b into the biases of l, one per neuron.
+func SetBiases(l *engine.Layer, b mat.Vector) error {
+	if b.Len() != len(l.Neurons) {
+		return fmt.Errorf("gonum: %d biases for a layer of %d neurons", b.Len(), len(l.Neurons))
+	}
+	for i, neuron := range l.Neurons {
+		neuron.Bias.Data = b.AtVec(i)
+	}
+	return nil
+}
+
+// TensorToDense returns a copy of a batch tensor as a matrix with one row per
+// sample and one column per value of a sample.
+func TensorToDense(t *engine.Tensor) *mat.Dense {
+	return mat.DenseCopyOf(TensorView{Tensor: t})
+}
+
+// DenseToTensor returns a batch tensor of new leaf Values holding a copy of m,
+// one sample per row.
+func DenseToTensor(m mat.Matrix) *engine.Tensor {
+	r, c := m.Dims()
+	data := make([]*engine.Value, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data = append(data, engine.NewValue(m.At(i, j), ""))
+		}
+	}
+	return engine.NewTensor(data, r, c)
+}
+
+// WeightsView is an [outputs x inputs] mat.Matrix view of the weights of a
+// layer. It reads and writes the weight Values directly; see the package
+// documentation.
+type WeightsView struct {
+	Layer *engine.Layer
+}
+
+// Dims returns the number of neurons and the number of inputs.
+func (v WeightsView) Dims() (r, c int) {
+	if len(v.Layer.Neurons) == 0 {
+		return 0, 0
+	}
+	return len(v.Layer.Neurons), len(v.Layer.Neurons[0].Weights)
+}
+
+// At returns the weight from input j to neuron i.
+func (v WeightsView) At(i, j int) float64 {
+	return v.Layer.Neurons[i].Weights[j].Data
+}
+
+// Set sets the weight from input j to neuron i.
+func (v WeightsView) Set(i, j int, x float64) {
+	v.Layer.Neurons[i].Weights[j].Data = x
+}
+
+// T returns the transpose view, [inputs x outputs].
+func (v WeightsView) T() mat.Matrix {
+	return mat.Transpose{Matrix: v}
+}
+
+// copyFrom sets every weight from the matrix of the same dimensions.
+func (v WeightsView) copyFrom(m mat.Matrix) {
+	r, c := v.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v.Set(i, j, m.At(i, j))
+		}
+	}
+}
+
+// TensorView is a mat.Matrix view of a batch tensor with one row per sample.
+// It reads and writes the tensor's Values directly; see the package
+// documentation.
+type TensorView struct {
+	Tensor *engine.Tensor
+}
+
+// Dims returns the batch size and the number of values per sample.
+func (v TensorView) Dims() (r, c int) {
+	r = v.Tensor.BatchSize()
+	if r == 0 {
+		return 0, 0
+	}
+	return r, len(v.Tensor.Data) / r
+}
+
+// At returns value j of sample i.
+func (v TensorView) At(i, j int) float64 {
+	return v.Tensor.Row(i)[j].Data
+}
+
+// Set sets value j of sample i.
+func (v TensorView) Set(i, j int, x float64) {
+	v.Tensor.Row(i)[j].Data = x
+}
+
+// T returns the transpose view, with one column per sample.
+func (v TensorView) T() mat.Matrix {
+	return mat.Transpose{Matrix: v}
+}
+
+// sameDims reports whether m has r rows and c columns.
+func sameDims(m mat.Matrix, r, c int) bool {
+	mr, mc := m.Dims()
+	return mr == r && mc == c
+}
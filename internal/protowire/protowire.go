@@ -0,0 +1,180 @@
+// Package protowire decodes the Protocol Buffers wire format, which is enough
+// to read messages of formats such as ONNX field by field without generated
+// code or a dependency on the protobuf runtime.
+package protowire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Type is the wire type of a field, telling how its value is encoded.
+type Type int
+
+const (
+	Varint  Type = 0 // Variable-length integer (int32, int64, uint64, bool, enum)
+	Fixed64 Type = 1 // Little-endian 8 bytes (fixed64, double)
+	Bytes   Type = 2 // Length-delimited (string, bytes, messages, packed repeated fields)
+	Fixed32 Type = 5 // Little-endian 4 bytes (fixed32, float)
+)
+
+// Field is one decoded field of a message. Depending on Type, the value is
+// in Num (Varint, Fixed64, Fixed32) or Bytes.
+type Field struct {
+	Number int
+	Type   Type
+	Num    uint64
+	Bytes  []byte
+}
+
+// Int returns the value of a varint field as a signed integer.
+func (f Field) Int() int64 {
+	return int64(f.Num)
+}
+
+// Float32 returns the value of a fixed32 field as a float.
+func (f Field) Float32() float32 {
+	return math.Float32frombits(uint32(f.Num))
+}
+
+// Float64 returns the value of a fixed64 field as a double.
+func (f Field) Float64() float64 {
+	return math.Float64frombits(f.Num)
+}
+
+// String returns the value of a length-delimited field as a string.
+func (f Field) String() string {
+	return string(f.Bytes)
+}
+
+// errTruncated reports a message that ends in the middle of a field.
+var errTruncated = errors.New("protowire: truncated message")
+
+// Fields decodes the top-level fields of a message in the order they appear.
+// Nested messages are left encoded in Field.Bytes, to be decoded the same way.
+func Fields(b []byte) ([]Field, error) {
+	var fields []Field
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		b = b[n:]
+		f := Field{Number: int(key >> 3), Type: Type(key & 7)}
+		switch f.Type {
+		case Varint:
+			f.Num, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errTruncated
+			}
+			b = b[n:]
+		case Fixed64:
+			if len(b) < 8 {
+				return nil, errTruncated
+			}
+			f.Num, b = binary.LittleEndian.Uint64(b), b[8:]
+		case Fixed32:
+			if len(b) < 4 {
+				return nil, errTruncated
+			}
+			f.Num, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case Bytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return nil, errTruncated
+			}
+			f.Bytes, b = b[n:n+int(size)], b[n+int(size):]
+		default:
+			return nil, fmt.Errorf("protowire: unsupported wire type %d of field %d", f.Type, f.Number)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// Varints appends the integers of a repeated varint field to dst. The field
+// may be packed (one length-delimited field) or not (one field per value).
+func Varints(dst []int64, f Field) ([]int64, error) {
+	if f.Type == Varint {
+		return append(dst, f.Int()), nil
+	}
+	if f.Type != Bytes {
+		return nil, fmt.Errorf("protowire: field %d has wire type %d, expected varints", f.Number, f.Type)
+	}
+	for b := f.Bytes; len(b) > 0; {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		dst, b = append(dst, int64(v)), b[n:]
+	}
+	return dst, nil
+}
+
+// Floats appends the values of a repeated float field to dst, packed or not.
+func Floats(dst []float64, f Field) ([]float64, error) {
+	if f.Type == Fixed32 {
+		return append(dst, float64(f.Float32())), nil
+	}
+	if f.Type != Bytes || len(f.Bytes)%4 != 0 {
+		return nil, fmt.Errorf("protowire: field %d is not a list of floats", f.Number)
+	}
+	for b := f.Bytes; len(b) > 0; b = b[4:] {
+		dst = append(dst, float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+	}
+	return dst, nil
+}
+
+// Doubles appends the values of a repeated double field to dst, packed or not.
+func Doubles(dst []float64, f Field) ([]float64, error) {
+	if f.Type == Fixed64 {
+		return append(dst, f.Float64()), nil
+	}
+	if f.Type != Bytes || len(f.Bytes)%8 != 0 {
+		return nil, fmt.Errorf("protowire: field %d is not a list of doubles", f.Number)
+	}
+	for b := f.Bytes; len(b) > 0; b = b[8:] {
+		dst = append(dst, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	}
+	return dst, nil
+}
+
+// AppendVarint appends field number num holding the varint v to b.
+func AppendVarint(b []byte, num int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|uint64(Varint))
+	return binary.AppendUvarint(b, v)
+}
+
+// AppendDouble appends field number num holding the double v to b.
+func AppendDouble(b []byte, num int, v float64) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|uint64(Fixed64))
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+}
+
+// AppendBytes appends the length-delimited field number num (a string, bytes
+// or an encoded message) to b.
+func AppendBytes(b []byte, num int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|uint64(Bytes))
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendString appends the string field number num to b.
+func AppendString(b []byte, num int, s string) []byte {
+	return AppendBytes(b, num, []byte(s))
+}
+
+// AppendPackedDoubles appends the repeated double field number num to b in
+// packed encoding, omitting it if vs is empty.
+func AppendPackedDoubles(b []byte, num int, vs []float64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	packed := make([]byte, 0, 8*len(vs))
+	for _, v := range vs {
+		packed = binary.LittleEndian.AppendUint64(packed, math.Float64bits(v))
+	}
+	return AppendBytes(b, num, packed)
+}
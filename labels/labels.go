@@ -0,0 +1,145 @@
+// Package labels converts between the representations of class labels:
+// integer class indices, one-hot vectors, string class names and the outputs
+// of a classifier. It is shared by the losses, metrics and data packages so
+// they agree on how classes are encoded and predicted.
+package labels
+
+import (
+	"fmt"
+	"sort"
+)
+
+// OneHot returns a vector of length n that is 1 at class and 0 elsewhere.
+// It panics if class is not in [0, n).
+func OneHot(class, n int) []float64 {
+	if class < 0 || class >= n {
+		panic(fmt.Sprintf("labels: class %d outside [0, %d)", class, n))
+	}
+	v := make([]float64, n)
+	v[class] = 1
+	return v
+}
+
+// OneHotAll one-hot encodes every class with n classes, e.g. to build the
+// targets of a dataset.
+func OneHotAll(classes []int, n int) [][]float64 {
+	out := make([][]float64, len(classes))
+	for i, c := range classes {
+		out[i] = OneHot(c, n)
+	}
+	return out
+}
+
+// Argmax returns the index of the largest value, the first one on ties. It
+// recovers the class of a one-hot vector.
+func Argmax(vs []float64) int {
+	best := 0
+	for i, v := range vs {
+		if v > vs[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// Predict returns the class predicted by the outputs of a classifier: for a
+// single output (binary classification) 1 if it is above threshold and 0
+// otherwise, and the argmax of the outputs (logits or probabilities) otherwise.
+func Predict(outputs []float64, threshold float64) int {
+	if len(outputs) == 1 {
+		if outputs[0] > threshold {
+			return 1
+		}
+		return 0
+	}
+	return Argmax(outputs)
+}
+
+// PredictAll returns the predicted class of every row of outputs.
+func PredictAll(outputs [][]float64, threshold float64) []int {
+	classes := make([]int, len(outputs))
+	for i, out := range outputs {
+		classes[i] = Predict(out, threshold)
+	}
+	return classes
+}
+
+// Encoder maps string class names to indices 0, 1, ... and back.
+type Encoder struct {
+	names []string
+	index map[string]int
+}
+
+// NewEncoder creates an Encoder with the given classes, in order.
+// It panics if a class appears twice.
+func NewEncoder(classes ...string) *Encoder {
+	e := &Encoder{names: append([]string(nil), classes...), index: make(map[string]int, len(classes))}
+	for i, name := range classes {
+		if _, dup := e.index[name]; dup {
+			panic(fmt.Sprintf("labels: duplicate class %q", name))
+		}
+		e.index[name] = i
+	}
+	return e
+}
+
+// FitEncoder creates an Encoder with the distinct values, sorted, so the
+// encoding does not depend on the order of the examples.
+func FitEncoder(values []string) *Encoder {
+	seen := map[string]bool{}
+	var classes []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			classes = append(classes, v)
+		}
+	}
+	sort.Strings(classes)
+	return NewEncoder(classes...)
+}
+
+// Len returns the number of classes.
+func (e *Encoder) Len() int {
+	return len(e.names)
+}
+
+// Classes returns the class names in the order of their indices.
+func (e *Encoder) Classes() []string {
+	return append([]string(nil), e.names...)
+}
+
+// Index returns the index of class name and whether it is known.
+func (e *Encoder) Index(name string) (int, bool) {
+	i, ok := e.index[name]
+	return i, ok
+}
+
+// Name returns the name of class i. It panics if i is out of range.
+func (e *Encoder) Name(i int) string {
+	if i < 0 || i >= len(e.names) {
+		panic(fmt.Sprintf("labels: class %d outside [0, %d)", i, len(e.names)))
+	}
+	return e.names[i]
+}
+
+// Encode returns the index of every value, or an error for an unknown class.
+func (e *Encoder) Encode(values []string) ([]int, error) {
+	out := make([]int, len(values))
+	for i, v := range values {
+		c, ok := e.index[v]
+		if !ok {
+			return nil, fmt.Errorf("labels: unknown class %q", v)
+		}
+		out[i] = c
+	}
+	return out, nil
+}
+
+// Decode returns the name of every class index.
+func (e *Encoder) Decode(classes []int) []string {
+	out := make([]string, len(classes))
+	for i, c := range classes {
+		out[i] = e.Name(c)
+	}
+	return out
+}
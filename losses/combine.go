@@ -0,0 +1,76 @@
+package losses
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Combine returns the weighted sum of several loss terms, e.g. the losses of the
+// heads of an engine.MultiHead model. A nil weights slice weights every term 1.
+func Combine(terms []*engine.Value, weights []float64) *engine.Value {
+	if weights != nil && len(weights) != len(terms) {
+		panic(fmt.Sprintf("losses: Combine got %d terms and %d weights", len(terms), len(weights)))
+	}
+	weighted := make([]*engine.Value, len(terms))
+	for i, t := range terms {
+		weighted[i] = t
+		if weights != nil {
+			weighted[i] = t.Mul(engine.NewValue(weights[i], ""))
+		}
+	}
+	total := engine.Sum(weighted)
+	total.Label = "combined_loss"
+	return total
+}
+
+// UncertaintyWeighting learns the weights of a multi-task loss automatically
+// (Kendall et al., 2018). Every task i gets a trainable log-variance s_i and the
+// combined loss is sum(exp(-s_i) * L_i + s_i): noisy or hard tasks learn a large
+// s_i and are down-weighted, while the +s_i term stops all weights from
+// collapsing to zero. Pass Parameters() to the optimizer along with the model's.
+type UncertaintyWeighting struct {
+	LogVars []*engine.Value
+}
+
+// NewUncertaintyWeighting creates automatic weighting for n loss terms,
+// starting with every weight at 1 (log-variance 0).
+func NewUncertaintyWeighting(n int) *UncertaintyWeighting {
+	uw := UncertaintyWeighting{
+		LogVars: make([]*engine.Value, n),
+	}
+	for i := range uw.LogVars {
+		uw.LogVars[i] = engine.NewValue(0.0, fmt.Sprintf("log_var%d", i+1))
+	}
+	return &uw
+}
+
+// Weights returns the current effective weight exp(-s_i) of every term.
+func (uw *UncertaintyWeighting) Weights() []float64 {
+	w := make([]float64, len(uw.LogVars))
+	for i, s := range uw.LogVars {
+		w[i] = math.Exp(-s.Data)
+	}
+	return w
+}
+
+// Combine returns sum(exp(-s_i) * terms[i] + s_i) as a graph node.
+func (uw *UncertaintyWeighting) Combine(terms []*engine.Value) *engine.Value {
+	if len(terms) != len(uw.LogVars) {
+		panic(fmt.Sprintf("losses: UncertaintyWeighting has %d weights but got %d terms", len(uw.LogVars), len(terms)))
+	}
+	weighted := make([]*engine.Value, len(terms))
+	for i, t := range terms {
+		precision := uw.LogVars[i].Mul(engine.NewValue(-1.0, "")).Exp()
+		weighted[i] = precision.Mul(t).Add(uw.LogVars[i])
+	}
+	total := engine.Sum(weighted)
+	total.Label = "combined_loss"
+	return total
+}
+
+// Parameters returns the trainable log-variances.
+func (uw *UncertaintyWeighting) Parameters() []*engine.Value {
+	return uw.LogVars
+}
@@ -0,0 +1,110 @@
+package losses
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/labels"
+)
+
+// softmaxFloats returns the softmax of the logits' data and log(sum(exp(logits))),
+// both computed stably by shifting by the maximum logit.
+func softmaxFloats(logits []*engine.Value) ([]float64, float64) {
+	maxData := math.Inf(-1)
+	for _, l := range logits {
+		maxData = math.Max(maxData, l.Data)
+	}
+	probs := make([]float64, len(logits))
+	sum := 0.0
+	for i, l := range logits {
+		probs[i] = math.Exp(l.Data - maxData)
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs, maxData + math.Log(sum)
+}
+
+// fusedCrossEntropy builds a single graph node for -sum(targets * log(softmax(logits)))
+// with constant targets. The log-softmax is fused in, which is numerically stable
+// for large logits and gives each logit the gradient softmax * sum(targets) - target.
+func fusedCrossEntropy(logits []*engine.Value, targets []float64) *engine.Value {
+	probs, logSumExp := softmaxFloats(logits)
+
+	out := &engine.Value{
+		Prev:  append([]*engine.Value(nil), logits...),
+		Op:    "cross_entropy",
+		Label: "cross_entropy_loss",
+	}
+	targetSum := 0.0
+	for i, t := range targets {
+		out.Data += t * (logSumExp - logits[i].Data)
+		targetSum += t
+	}
+	out.Backward = func() {
+		for i, l := range logits {
+			l.Grad += out.Grad * (probs[i]*targetSum - targets[i])
+		}
+	}
+	return out
+}
+
+// oneHot returns a one-hot vector of length n for class, mixed with the uniform
+// distribution by epsilon (label smoothing): (1 - epsilon) * onehot + epsilon / n.
+func oneHot(class, n int, epsilon float64) []float64 {
+	if class < 0 || class >= n {
+		panic(fmt.Sprintf("losses: CrossEntropy class %d out of range for %d logits", class, n))
+	}
+	t := labels.OneHot(class, n)
+	for i := range t {
+		t[i] = (1-epsilon)*t[i] + epsilon/float64(n)
+	}
+	return t
+}
+
+// CrossEntropy returns the cross-entropy loss -log(softmax(logits)[class]) for
+// one sample with raw (unnormalized) logits. The log-softmax is fused into a
+// single graph node, which is numerically stable for large logits and gives
+// each logit the gradient softmax(logits) - onehot(class).
+func CrossEntropy(logits []*engine.Value, class int) *engine.Value {
+	return fusedCrossEntropy(logits, oneHot(class, len(logits), 0))
+}
+
+// CrossEntropySmoothed is CrossEntropy with label smoothing: the one-hot target
+// is mixed with the uniform distribution, (1 - epsilon) * onehot + epsilon / K
+// for K classes, which discourages over-confident logits and improves calibration.
+func CrossEntropySmoothed(logits []*engine.Value, class int, epsilon float64) *engine.Value {
+	return fusedCrossEntropy(logits, oneHot(class, len(logits), epsilon))
+}
+
+// CrossEntropyBatch returns the mean cross-entropy over a batch of logits and their classes.
+func CrossEntropyBatch(logits [][]*engine.Value, classes []int) *engine.Value {
+	return CrossEntropyBatchReduction(logits, classes, Mean)[0]
+}
+
+// CrossEntropyBatchReduction returns the per-sample cross-entropies of a batch combined according to r.
+func CrossEntropyBatchReduction(logits [][]*engine.Value, classes []int, r Reduction) []*engine.Value {
+	if len(logits) != len(classes) {
+		panic(fmt.Sprintf("losses: CrossEntropy got %d samples and %d classes", len(logits), len(classes)))
+	}
+	elems := make([]*engine.Value, len(logits))
+	for i := range logits {
+		elems[i] = CrossEntropy(logits[i], classes[i])
+	}
+	return Reduce(elems, r)
+}
+
+// NLL returns the negative log-likelihood -logProbs[class] for one sample whose
+// predictions are already log-probabilities (e.g. from engine.LogSoftmax).
+// NLL(engine.LogSoftmax(logits), class) equals CrossEntropy(logits, class); the
+// decomposed form lets custom pipelines reuse or modify the log-probabilities.
+func NLL(logProbs []*engine.Value, class int) *engine.Value {
+	if class < 0 || class >= len(logProbs) {
+		panic(fmt.Sprintf("losses: NLL class %d out of range for %d log-probabilities", class, len(logProbs)))
+	}
+	loss := logProbs[class].Mul(engine.NewValue(-1.0, ""))
+	loss.Label = "nll_loss"
+	return loss
+}
@@ -0,0 +1,146 @@
+package losses
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// logAdd returns log(exp(a) + exp(b)) without overflow, treating -Inf as log(0).
+func logAdd(a, b float64) float64 {
+	if math.IsInf(a, -1) {
+		return b
+	}
+	if math.IsInf(b, -1) {
+		return a
+	}
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log1p(math.Exp(b-a))
+}
+
+// CTC returns the connectionist temporal classification loss -log P(target | x)
+// for one sequence. logProbs[t][k] is the log-probability of symbol k at time
+// step t (e.g. engine.LogSoftmax of a per-step output), target is the label
+// sequence without blanks, and blank is the index of the blank symbol.
+//
+// P sums over every alignment that collapses to target once repeated symbols
+// are merged and blanks removed. It is computed with the forward-backward
+// dynamic program in log space, and the whole loss is a single graph node whose
+// gradient for logProbs[t][k] is -sum(alpha_t(s) * beta_t(s) over positions s
+// labelled k) / (P * p_t(k)). If no alignment exists (target too long for the
+// number of time steps) the loss is +Inf and no gradient is propagated.
+func CTC(logProbs [][]*engine.Value, target []int, blank int) *engine.Value {
+	T := len(logProbs)
+	for _, k := range target {
+		if k == blank {
+			panic(fmt.Sprintf("losses: CTC target contains the blank symbol %d", blank))
+		}
+	}
+
+	// Extended label sequence: blank, l1, blank, l2, ..., blank
+	ext := make([]int, 2*len(target)+1)
+	for s := range ext {
+		ext[s] = blank
+		if s%2 == 1 {
+			ext[s] = target[s/2]
+		}
+	}
+	S := len(ext)
+
+	var prev []*engine.Value
+	for _, step := range logProbs {
+		prev = append(prev, step...)
+	}
+	out := &engine.Value{
+		Prev:  prev,
+		Op:    "ctc",
+		Label: "ctc_loss",
+	}
+	out.Backward = func() {}
+	if T == 0 {
+		out.Data = math.Inf(1)
+		return out
+	}
+	u := func(t, s int) float64 { return logProbs[t][ext[s]].Data }
+	// skip reports whether a path may jump over position s-2 to reach s
+	skip := func(s int) bool { return s >= 2 && ext[s] != blank && ext[s] != ext[s-2] }
+
+	newTable := func() [][]float64 {
+		table := make([][]float64, T)
+		for t := range table {
+			table[t] = make([]float64, S)
+			for s := range table[t] {
+				table[t][s] = math.Inf(-1)
+			}
+		}
+		return table
+	}
+
+	// Forward pass: alpha[t][s] = log-probability of all prefixes ending at ext[s] at time t
+	alpha := newTable()
+	alpha[0][0] = u(0, 0)
+	if S > 1 {
+		alpha[0][1] = u(0, 1)
+	}
+	for t := 1; t < T; t++ {
+		for s := 0; s < S; s++ {
+			a := alpha[t-1][s]
+			if s >= 1 {
+				a = logAdd(a, alpha[t-1][s-1])
+			}
+			if skip(s) {
+				a = logAdd(a, alpha[t-1][s-2])
+			}
+			alpha[t][s] = a + u(t, s)
+		}
+	}
+
+	logP := alpha[T-1][S-1]
+	if S > 1 {
+		logP = logAdd(logP, alpha[T-1][S-2])
+	}
+	out.Data = -logP
+	if math.IsInf(logP, -1) {
+		return out
+	}
+
+	// Backward pass: beta[t][s] = log-probability of all suffixes starting at ext[s] at time t
+	beta := newTable()
+	beta[T-1][S-1] = u(T-1, S-1)
+	if S > 1 {
+		beta[T-1][S-2] = u(T-1, S-2)
+	}
+	for t := T - 2; t >= 0; t-- {
+		for s := S - 1; s >= 0; s-- {
+			b := beta[t+1][s]
+			if s+1 < S {
+				b = logAdd(b, beta[t+1][s+1])
+			}
+			if s+2 < S && skip(s+2) {
+				b = logAdd(b, beta[t+1][s+2])
+			}
+			beta[t][s] = b + u(t, s)
+		}
+	}
+
+	out.Backward = func() {
+		for t := 0; t < T; t++ {
+			// Total log-probability mass passing through each symbol at time t
+			mass := map[int]float64{}
+			for s := 0; s < S; s++ {
+				m, ok := mass[ext[s]]
+				if !ok {
+					m = math.Inf(-1)
+				}
+				mass[ext[s]] = logAdd(m, alpha[t][s]+beta[t][s])
+			}
+			for k, m := range mass {
+				logProbs[t][k].Grad -= out.Grad * math.Exp(m-logP-logProbs[t][k].Data)
+			}
+		}
+	}
+	return out
+}
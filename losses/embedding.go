@@ -0,0 +1,93 @@
+package losses
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Losses in this file compare pairs (or triplets) of embeddings, typically the
+// outputs of an engine.Siamese tower.
+
+// normEpsilon keeps norms and similarities differentiable for all-zero vectors.
+const normEpsilon = 1e-12
+
+// dot returns the dot product of two equally long Value vectors.
+func dot(a, b []*engine.Value) *engine.Value {
+	terms := make([]*engine.Value, len(a))
+	for i := range a {
+		terms[i] = a[i].Mul(b[i])
+	}
+	return engine.Sum(terms)
+}
+
+// CosineSimilarity returns a·b / (||a|| ||b||) as a differentiable graph node.
+func CosineSimilarity(a, b []*engine.Value) *engine.Value {
+	checkLengths("CosineSimilarity", a, b)
+	norms := dot(a, a).Mul(dot(b, b)).Add(engine.NewValue(normEpsilon, "")).Pow(0.5)
+	cos := dot(a, b).Div(norms)
+	cos.Label = "cosine_similarity"
+	return cos
+}
+
+// CosineEmbedding returns the cosine embedding loss for a pair of embeddings:
+// 1 - cos(a, b) for a similar pair (label 1), and max(0, cos(a, b) - margin)
+// for a dissimilar pair (label -1).
+func CosineEmbedding(a, b []*engine.Value, label int, margin float64) *engine.Value {
+	cos := CosineSimilarity(a, b)
+	var loss *engine.Value
+	switch label {
+	case 1:
+		loss = engine.NewValue(1.0, "").Sub(cos)
+	case -1:
+		loss = cos.Sub(engine.NewValue(margin, "")).ReLU()
+	default:
+		panic(fmt.Sprintf("losses: CosineEmbedding label must be 1 or -1, got %d", label))
+	}
+	loss.Label = "cosine_embedding_loss"
+	return loss
+}
+
+// EuclideanDistance returns ||a - b|| as a differentiable graph node.
+// A tiny epsilon under the square root keeps the gradient finite when a == b.
+func EuclideanDistance(a, b []*engine.Value) *engine.Value {
+	checkLengths("EuclideanDistance", a, b)
+	sq := make([]*engine.Value, len(a))
+	for i := range a {
+		diff := a[i].Sub(b[i])
+		sq[i] = diff.Mul(diff)
+	}
+	d := engine.Sum(sq).Add(engine.NewValue(normEpsilon, "")).Pow(0.5)
+	d.Label = "euclidean_distance"
+	return d
+}
+
+// Triplet returns the triplet margin loss max(0, d(a, p) - d(a, n) + margin)
+// with Euclidean distances: it pulls the anchor towards the positive and pushes
+// it away from the negative until the negative is at least margin further away.
+func Triplet(anchor, positive, negative []*engine.Value, margin float64) *engine.Value {
+	loss := EuclideanDistance(anchor, positive).
+		Sub(EuclideanDistance(anchor, negative)).
+		Add(engine.NewValue(margin, "")).
+		ReLU()
+	loss.Label = "triplet_loss"
+	return loss
+}
+
+// Contrastive returns the classic pairwise contrastive loss on the Euclidean
+// distance d between two embeddings: 0.5 * d^2 for a similar pair, pulling it
+// together, and 0.5 * max(0, margin - d)^2 for a dissimilar pair, pushing it
+// apart until it is at least margin away.
+func Contrastive(a, b []*engine.Value, similar bool, margin float64) *engine.Value {
+	d := EuclideanDistance(a, b)
+	var loss *engine.Value
+	if similar {
+		loss = d.Mul(d)
+	} else {
+		gap := engine.NewValue(margin, "").Sub(d).ReLU()
+		loss = gap.Mul(gap)
+	}
+	loss = loss.Mul(engine.NewValue(0.5, ""))
+	loss.Label = "contrastive_loss"
+	return loss
+}
@@ -0,0 +1,59 @@
+package losses
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// focalEpsilon is the smallest base 1 - p_t of the modulating factor, which
+// keeps its gradient finite at p_t = 1 for gamma < 1.
+const focalEpsilon = 1e-12
+
+// Focal returns the focal loss -alpha_t * (1 - p_t)^gamma * log(p_t) for one
+// sample, which down-weights easy, well-classified examples so training
+// concentrates on the hard ones of a severely imbalanced problem.
+//
+// With a single logit the problem is binary: p = sigmoid(logit), target is 0
+// or 1, and alpha_t is alpha for the positive class and 1 - alpha for the
+// negative one. With several logits p = softmax(logits), target is the class
+// index and alpha_t = alpha. log(p_t) is taken from the fused, numerically
+// stable cross-entropy, and gamma = 0 reduces the loss to (weighted) cross-entropy.
+func Focal(logits []*engine.Value, target int, gamma, alpha float64) *engine.Value {
+	alphaT := alpha
+	if len(logits) == 1 {
+		if target != 0 && target != 1 {
+			panic(fmt.Sprintf("losses: binary Focal target must be 0 or 1, got %d", target))
+		}
+		if target == 0 {
+			alphaT = 1 - alpha
+		}
+		// sigmoid(x) = softmax([0, x])[1], which reuses the stable softmax path
+		logits = []*engine.Value{engine.NewValue(0.0, ""), logits[0]}
+	}
+
+	ce := CrossEntropy(logits, target) // -log(p_t)
+	loss := ce
+	if gamma != 0 {
+		pt := ce.Mul(engine.NewValue(-1.0, "")).Exp()
+		eps := engine.NewValue(focalEpsilon, "")
+		base := engine.NewValue(1.0, "").Sub(pt).Sub(eps).ReLU().Add(eps) // max(1 - p_t, eps)
+		modulating := base.Pow(gamma)                                     // (1 - p_t)^gamma
+		loss = modulating.Mul(ce)
+	}
+	loss = loss.Mul(engine.NewValue(alphaT, ""))
+	loss.Label = "focal_loss"
+	return loss
+}
+
+// FocalBatch returns the mean focal loss over a batch of logits and their targets.
+func FocalBatch(logits [][]*engine.Value, targets []int, gamma, alpha float64) *engine.Value {
+	if len(logits) != len(targets) {
+		panic(fmt.Sprintf("losses: Focal got %d samples and %d targets", len(logits), len(targets)))
+	}
+	elems := make([]*engine.Value, len(logits))
+	for i := range logits {
+		elems[i] = Focal(logits[i], targets[i], gamma, alpha)
+	}
+	return Reduce(elems, Mean)[0]
+}
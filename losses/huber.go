@@ -0,0 +1,92 @@
+package losses
+
+import (
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// huberElement builds a single graph node for the Huber loss of one prediction:
+// 0.5*d^2 if |d| <= delta and delta*(|d| - 0.5*delta) otherwise, where d = pred - target.
+// Its gradient with respect to pred is d clipped to [-delta, delta].
+func huberElement(pred, target *engine.Value, delta float64) *engine.Value {
+	d := pred.Data - target.Data
+	var data, grad float64
+	if math.Abs(d) <= delta {
+		data = 0.5 * d * d
+		grad = d
+	} else {
+		data = delta * (math.Abs(d) - 0.5*delta)
+		grad = math.Copysign(delta, d)
+	}
+
+	out := &engine.Value{
+		Data: data,
+		Prev: []*engine.Value{pred, target},
+		Op:   "huber",
+	}
+	out.Backward = func() {
+		pred.Grad += out.Grad * grad
+		target.Grad -= out.Grad * grad
+	}
+	return out
+}
+
+// Huber returns the mean Huber loss between preds and targets: quadratic for
+// errors up to delta and linear beyond, so outliers pull on the model with a
+// bounded gradient.
+func Huber(preds, targets []*engine.Value, delta float64) *engine.Value {
+	loss := HuberReduction(preds, targets, delta, Mean)[0]
+	loss.Label = "huber_loss"
+	return loss
+}
+
+// HuberReduction returns the per-element Huber losses combined according to r.
+func HuberReduction(preds, targets []*engine.Value, delta float64, r Reduction) []*engine.Value {
+	checkLengths("Huber", preds, targets)
+	elems := make([]*engine.Value, len(preds))
+	for i := range preds {
+		elems[i] = huberElement(preds[i], targets[i], delta)
+	}
+	return Reduce(elems, r)
+}
+
+// l1Element builds a single graph node for the absolute error |pred - target|.
+// Its gradient with respect to pred is the sign of the error, 0 where it is 0.
+func l1Element(pred, target *engine.Value) *engine.Value {
+	d := pred.Data - target.Data
+	var grad float64
+	if d != 0 {
+		grad = math.Copysign(1, d)
+	}
+
+	out := &engine.Value{
+		Data: math.Abs(d),
+		Prev: []*engine.Value{pred, target},
+		Op:   "l1",
+	}
+	out.Backward = func() {
+		pred.Grad += out.Grad * grad
+		target.Grad -= out.Grad * grad
+	}
+	return out
+}
+
+// SmoothL1 returns the mean smooth L1 loss, which is the Huber loss with
+// delta = beta divided by beta: 0.5*d^2/beta for |d| < beta and |d| - 0.5*beta otherwise.
+// With beta = 0 it is the plain L1 loss, the mean of |d|, as in PyTorch.
+func SmoothL1(preds, targets []*engine.Value, beta float64) *engine.Value {
+	var loss *engine.Value
+	if beta == 0 {
+		checkLengths("SmoothL1", preds, targets)
+		elems := make([]*engine.Value, len(preds))
+		for i := range preds {
+			elems[i] = l1Element(preds[i], targets[i])
+		}
+		loss = Reduce(elems, Mean)[0]
+	} else {
+		loss = Huber(preds, targets, beta).Mul(engine.NewValue(1/beta, ""))
+	}
+	loss.Label = "smooth_l1_loss"
+	return loss
+}
@@ -0,0 +1,50 @@
+package losses
+
+import (
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// klElement builds a single graph node for q * (log q - logP). Terms with q = 0
+// contribute nothing (the 0 * log 0 = 0 convention), which a composition of
+// Mul and a logarithm could not express without producing NaNs.
+func klElement(logP, q *engine.Value) *engine.Value {
+	out := &engine.Value{
+		Prev: []*engine.Value{logP, q},
+		Op:   "kl_div",
+	}
+	if q.Data > 0 {
+		out.Data = q.Data * (math.Log(q.Data) - logP.Data)
+	}
+	out.Backward = func() {
+		if q.Data <= 0 {
+			return
+		}
+		logP.Grad -= out.Grad * q.Data
+		q.Grad += out.Grad * (math.Log(q.Data) - logP.Data + 1)
+	}
+	return out
+}
+
+// KLDiv returns the Kullback-Leibler divergence KL(q || p) = sum(q * (log q - log p))
+// of a target distribution q from a predicted distribution given as
+// log-probabilities logP (e.g. the output of a log-softmax). Gradients flow into
+// both arguments, so q may itself be a model output (as in distillation or VAEs).
+func KLDiv(logP, q []*engine.Value) *engine.Value {
+	loss := KLDivReduction(logP, q, Sum)[0]
+	loss.Label = "kl_div_loss"
+	return loss
+}
+
+// KLDivReduction returns the per-element terms q * (log q - logP) combined according
+// to r. Note that only Sum yields the actual divergence; Mean divides it by the
+// number of elements.
+func KLDivReduction(logP, q []*engine.Value, r Reduction) []*engine.Value {
+	checkLengths("KLDiv", logP, q)
+	elems := make([]*engine.Value, len(logP))
+	for i := range logP {
+		elems[i] = klElement(logP[i], q[i])
+	}
+	return Reduce(elems, r)
+}
@@ -0,0 +1,137 @@
+package losses
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Loss is implemented by every loss the training utilities can use.
+// Compute returns a scalar loss for one sample (or one set of aligned
+// predictions and targets) as a graph node ready for FullBackward.
+type Loss interface {
+	Compute(preds, targets []*engine.Value) *engine.Value
+}
+
+// Func adapts an ordinary function to the Loss interface, for user-defined losses.
+type Func func(preds, targets []*engine.Value) *engine.Value
+
+// Compute calls f(preds, targets).
+func (f Func) Compute(preds, targets []*engine.Value) *engine.Value {
+	return f(preds, targets)
+}
+
+// scalar reduces per-element losses to the single Value returned by Compute.
+// None has no scalar form, so it falls back to the sum of the elements (which
+// yields the same gradients for every element as backpropagating each one).
+func scalar(elems []*engine.Value, r Reduction) *engine.Value {
+	if r == None {
+		r = Sum
+	}
+	return Reduce(elems, r)[0]
+}
+
+// MSELoss is the Loss form of MSEReduction.
+type MSELoss struct {
+	Reduction Reduction
+}
+
+// Compute returns the squared errors reduced according to l.Reduction.
+func (l MSELoss) Compute(preds, targets []*engine.Value) *engine.Value {
+	return scalar(MSEReduction(preds, targets, None), l.Reduction)
+}
+
+// HuberLoss is the Loss form of HuberReduction.
+type HuberLoss struct {
+	Delta     float64
+	Reduction Reduction
+}
+
+// Compute returns the Huber losses reduced according to l.Reduction.
+func (l HuberLoss) Compute(preds, targets []*engine.Value) *engine.Value {
+	return scalar(HuberReduction(preds, targets, l.Delta, None), l.Reduction)
+}
+
+// PoissonNLLLoss is the Loss form of PoissonNLLReduction, with preds given as log-rates.
+type PoissonNLLLoss struct {
+	Reduction Reduction
+}
+
+// Compute returns the Poisson negative log-likelihoods reduced according to l.Reduction.
+func (l PoissonNLLLoss) Compute(preds, targets []*engine.Value) *engine.Value {
+	return scalar(PoissonNLLReduction(preds, targets, None), l.Reduction)
+}
+
+// QuantileLoss is the Loss form of QuantileReduction.
+type QuantileLoss struct {
+	Q         float64
+	Reduction Reduction
+}
+
+// Compute returns the pinball losses reduced according to l.Reduction.
+func (l QuantileLoss) Compute(preds, targets []*engine.Value) *engine.Value {
+	return scalar(QuantileReduction(preds, targets, l.Q, None), l.Reduction)
+}
+
+// KLDivLoss is the Loss form of KLDivReduction, with preds given as log-probabilities.
+// Like every Loss it averages the per-element terms by default (as PyTorch's
+// KLDivLoss does); set Reduction to Sum to compute the actual divergence.
+type KLDivLoss struct {
+	Reduction Reduction
+}
+
+// Compute returns the KL terms reduced according to l.Reduction.
+func (l KLDivLoss) Compute(preds, targets []*engine.Value) *engine.Value {
+	return scalar(KLDivReduction(preds, targets, None), l.Reduction)
+}
+
+// NLLLoss is the Loss form of NLL for log-probability predictions and a target
+// distribution (one-hot or soft): -sum(target * logProbs).
+type NLLLoss struct{}
+
+// Compute returns the negative log-likelihood of the target distribution.
+func (l NLLLoss) Compute(preds, targets []*engine.Value) *engine.Value {
+	checkLengths("NLL", preds, targets)
+	terms := make([]*engine.Value, len(preds))
+	for i := range preds {
+		terms[i] = preds[i].Mul(engine.NewValue(-targets[i].Data, ""))
+	}
+	loss := engine.Sum(terms)
+	loss.Label = "nll_loss"
+	return loss
+}
+
+// CrossEntropyLoss computes the cross-entropy between raw logits and a target
+// probability distribution (a one-hot vector or soft labels, e.g. from mixup),
+// with the same fused log-softmax as CrossEntropy. A single target for several
+// logits is taken as a class index, so hard and soft targets can be mixed
+// (say, class indices for validation and mixed-up distributions for training).
+// Reduction is not applied within a sample:
+// the result is always -sum(target * log(softmax(logits))).
+// A non-zero LabelSmoothing mixes the targets with the uniform distribution
+// by that amount before computing the loss.
+type CrossEntropyLoss struct {
+	LabelSmoothing float64
+}
+
+// Compute returns the cross-entropy between the logits in preds and the target distribution.
+func (l CrossEntropyLoss) Compute(preds, targets []*engine.Value) *engine.Value {
+	if len(targets) == 1 && len(preds) > 1 {
+		return fusedCrossEntropy(preds, oneHot(int(targets[0].Data), len(preds), l.LabelSmoothing))
+	}
+	if len(preds) != len(targets) {
+		panic(fmt.Sprintf("losses: CrossEntropy got %d logits and %d targets", len(preds), len(targets)))
+	}
+	t := make([]float64, len(targets))
+	for i, target := range targets {
+		t[i] = (1-l.LabelSmoothing)*target.Data + l.LabelSmoothing/float64(len(targets))
+	}
+	return fusedCrossEntropy(preds, t)
+}
+
+// SoftCrossEntropy returns -sum(targets * log(softmax(logits))) for a target
+// probability distribution, as one fused graph node. With a one-hot target it
+// equals CrossEntropy. Targets are treated as constants.
+func SoftCrossEntropy(logits, targets []*engine.Value) *engine.Value {
+	return CrossEntropyLoss{}.Compute(logits, targets)
+}
@@ -0,0 +1,73 @@
+// Package losses provides loss functions built on the engine's autograd Values.
+// Every loss returns graph nodes, so calling FullBackward on the result
+// computes gradients for everything that produced the predictions.
+package losses
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Reduction selects how per-element losses are combined.
+type Reduction int
+
+const (
+	Mean Reduction = iota // Average of the per-element losses (default)
+	Sum                   // Sum of the per-element losses
+	None                  // Per-element losses, not combined
+)
+
+// String returns the lower-case name of the reduction.
+func (r Reduction) String() string {
+	switch r {
+	case Mean:
+		return "mean"
+	case Sum:
+		return "sum"
+	case None:
+		return "none"
+	}
+	return fmt.Sprintf("Reduction(%d)", int(r))
+}
+
+// Reduce combines per-element losses according to r. For Mean and Sum it
+// returns a single Value; for None it returns the elements unchanged.
+func Reduce(elems []*engine.Value, r Reduction) []*engine.Value {
+	switch r {
+	case None:
+		return elems
+	case Sum:
+		return []*engine.Value{engine.Sum(elems)}
+	}
+	if len(elems) == 0 {
+		return []*engine.Value{engine.NewValue(0.0, "")}
+	}
+	mean := engine.Sum(elems).Mul(engine.NewValue(1/float64(len(elems)), ""))
+	return []*engine.Value{mean}
+}
+
+// checkLengths panics if preds and targets differ in length.
+func checkLengths(name string, preds, targets []*engine.Value) {
+	if len(preds) != len(targets) {
+		panic(fmt.Sprintf("losses: %s got %d predictions and %d targets", name, len(preds), len(targets)))
+	}
+}
+
+// MSE returns the mean squared error between preds and targets.
+func MSE(preds, targets []*engine.Value) *engine.Value {
+	loss := MSEReduction(preds, targets, Mean)[0]
+	loss.Label = "mse_loss"
+	return loss
+}
+
+// MSEReduction returns the squared errors (pred - target)^2 combined according to r.
+func MSEReduction(preds, targets []*engine.Value, r Reduction) []*engine.Value {
+	checkLengths("MSE", preds, targets)
+	elems := make([]*engine.Value, len(preds))
+	for i := range preds {
+		diff := preds[i].Sub(targets[i])
+		elems[i] = diff.Mul(diff)
+	}
+	return Reduce(elems, r)
+}
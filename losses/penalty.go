@@ -0,0 +1,66 @@
+package losses
+
+import (
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Regularization penalties are graph nodes added to the data loss, e.g.
+//
+//	loss := losses.MSE(preds, targets).Add(losses.L2Penalty(mlp.Parameters(), 1e-4))
+//
+// so their gradients are mixed into the parameter gradients before the
+// optimizer sees them. This is "coupled" regularization: with adaptive
+// optimizers such as Adam the penalty gradient is rescaled per parameter like
+// any other gradient, so it no longer acts as a uniform shrinkage. Decoupled
+// weight decay (as in AdamW) instead shrinks the parameters directly in the
+// optimizer step, independently of the gradients, and should be preferred for
+// adaptive optimizers. With plain SGD the two are equivalent up to a factor
+// (L2Penalty with lambda matches weight decay 2*lambda).
+//
+// Usually only weights are penalized; filter biases out of params if needed.
+
+// L2Penalty returns lambda * sum(p^2) over params as a single graph node.
+// Its gradient with respect to each parameter is 2 * lambda * p.
+func L2Penalty(params []*engine.Value, lambda float64) *engine.Value {
+	out := &engine.Value{
+		Prev:  append([]*engine.Value(nil), params...),
+		Op:    "l2_penalty",
+		Label: "l2_penalty",
+	}
+	for _, p := range params {
+		out.Data += lambda * p.Data * p.Data
+	}
+	out.Backward = func() {
+		for _, p := range params {
+			p.Grad += out.Grad * 2 * lambda * p.Data
+		}
+	}
+	return out
+}
+
+// L1Penalty returns lambda * sum(|p|) over params as a single graph node,
+// which drives small parameters to exactly zero (sparsity).
+// Its gradient with respect to each parameter is lambda * sign(p), taken as 0 at p = 0.
+func L1Penalty(params []*engine.Value, lambda float64) *engine.Value {
+	out := &engine.Value{
+		Prev:  append([]*engine.Value(nil), params...),
+		Op:    "l1_penalty",
+		Label: "l1_penalty",
+	}
+	for _, p := range params {
+		out.Data += lambda * math.Abs(p.Data)
+	}
+	out.Backward = func() {
+		for _, p := range params {
+			switch {
+			case p.Data > 0:
+				p.Grad += out.Grad * lambda
+			case p.Data < 0:
+				p.Grad -= out.Grad * lambda
+			}
+		}
+	}
+	return out
+}
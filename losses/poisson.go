@@ -0,0 +1,23 @@
+package losses
+
+import "github.com/Rmehta-sudo/neural-net/engine"
+
+// PoissonNLL returns the mean Poisson negative log-likelihood for count data,
+// with the model predicting the log of the rate: exp(logRate) - target * logRate.
+// Predicting the log-rate keeps the rate positive without clamping, and the
+// constant log(target!) term is omitted since it has no gradient.
+func PoissonNLL(logRate, targets []*engine.Value) *engine.Value {
+	loss := PoissonNLLReduction(logRate, targets, Mean)[0]
+	loss.Label = "poisson_nll_loss"
+	return loss
+}
+
+// PoissonNLLReduction returns the per-element Poisson negative log-likelihoods combined according to r.
+func PoissonNLLReduction(logRate, targets []*engine.Value, r Reduction) []*engine.Value {
+	checkLengths("PoissonNLL", logRate, targets)
+	elems := make([]*engine.Value, len(logRate))
+	for i := range logRate {
+		elems[i] = logRate[i].Exp().Sub(targets[i].Mul(logRate[i]))
+	}
+	return Reduce(elems, r)
+}
@@ -0,0 +1,45 @@
+package losses
+
+import "github.com/Rmehta-sudo/neural-net/engine"
+
+// pinballElement builds a single graph node for the pinball loss of one
+// prediction: q * (target - pred) if the target lies above the prediction and
+// (1 - q) * (pred - target) otherwise.
+func pinballElement(pred, target *engine.Value, q float64) *engine.Value {
+	diff := target.Data - pred.Data
+	data, grad := q*diff, -q // Prediction too low
+	if diff < 0 {
+		data, grad = (q-1)*diff, 1-q // Prediction too high
+	}
+
+	out := &engine.Value{
+		Data: data,
+		Prev: []*engine.Value{pred, target},
+		Op:   "pinball",
+	}
+	out.Backward = func() {
+		pred.Grad += out.Grad * grad
+		target.Grad -= out.Grad * grad
+	}
+	return out
+}
+
+// Quantile returns the mean quantile (pinball) loss for quantile q in (0, 1).
+// Minimizing it makes preds estimate the conditional q-quantile of the targets
+// rather than their mean; e.g. training heads with q = 0.05 and q = 0.95 yields
+// a 90% prediction interval.
+func Quantile(preds, targets []*engine.Value, q float64) *engine.Value {
+	loss := QuantileReduction(preds, targets, q, Mean)[0]
+	loss.Label = "quantile_loss"
+	return loss
+}
+
+// QuantileReduction returns the per-element pinball losses combined according to r.
+func QuantileReduction(preds, targets []*engine.Value, q float64, r Reduction) []*engine.Value {
+	checkLengths("Quantile", preds, targets)
+	elems := make([]*engine.Value, len(preds))
+	for i := range preds {
+		elems[i] = pinballElement(preds[i], targets[i], q)
+	}
+	return Reduce(elems, r)
+}
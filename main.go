@@ -11,7 +11,8 @@ func main() {
 	fmt.Println("Welcome to Rmehta-sudo's Micrograd in Go!")
 	fmt.Println("This program demonstrates a minimal autograd engine and a simple neural network built with it.")
 	fmt.Println("You'll see examples of individual value operations, neuron, layer, and multi-layer perceptron (MLP) usage.")
-	fmt.Println("----------------------------------------------------------------------------------------------------\n")
+	fmt.Println("----------------------------------------------------------------------------------------------------")
+	fmt.Println()
 
 	// Initialize random seed for reproducible results in examples
 	// For real-world applications, consider cryptographically secure randomness or `time.Now().UnixNano()`
@@ -36,9 +37,9 @@ func main() {
 	// The `engine.MLP` combines multiple `engine.Layer`s to form a deep neural network.
 	// This example shows how to build, perform forward passes,
 	// calculate loss, and update parameters using gradient descent.
-	engine.TestMLP()
+	testMLP()
 
 	fmt.Println("----------------------------------------------------------------------------------------------------")
 	fmt.Println("All demonstrations complete! You can now explore the `engine` package files to understand the implementation.")
 	fmt.Println("Refer to the README.md for more details on building and training your own networks.")
-}
\ No newline at end of file
+}
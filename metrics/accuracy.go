@@ -0,0 +1,68 @@
+package metrics
+
+import "github.com/Rmehta-sudo/neural-net/labels"
+
+// Accuracy is the fraction of correctly classified examples.
+//
+// With a single model output the task is binary: an output (or target)
+// greater than Threshold is the positive class, so use 0.5 for sigmoid
+// outputs and 0/1 targets, or 0 for tanh outputs and -1/1 targets.
+// With several outputs the predicted class is the argmax of the outputs and
+// the true class is the argmax of a one-hot or soft target vector, or the
+// target itself if it is a single class index.
+type Accuracy struct {
+	Threshold float64
+
+	correct, total int
+}
+
+// NewAccuracy creates an Accuracy metric with the binary threshold 0.5.
+func NewAccuracy() *Accuracy {
+	return &Accuracy{Threshold: 0.5}
+}
+
+// Name returns "accuracy".
+func (a *Accuracy) Name() string {
+	return "accuracy"
+}
+
+// Reset clears the accumulated counts.
+func (a *Accuracy) Reset() {
+	a.correct, a.total = 0, 0
+}
+
+// Update records whether the prediction for one example is correct.
+func (a *Accuracy) Update(preds, targets []float64) {
+	a.total++
+	if predictedClass(preds, a.Threshold) == trueClass(preds, targets, a.Threshold) {
+		a.correct++
+	}
+}
+
+// Value returns the fraction of correct predictions, or 0 before any update.
+func (a *Accuracy) Value() float64 {
+	if a.total == 0 {
+		return 0
+	}
+	return float64(a.correct) / float64(a.total)
+}
+
+// predictedClass maps model outputs to a class: thresholded for a single
+// output, argmax otherwise (see labels.Predict).
+func predictedClass(preds []float64, threshold float64) int {
+	return labels.Predict(preds, threshold)
+}
+
+// trueClass maps a target vector to a class, consistently with predictedClass.
+func trueClass(preds, targets []float64, threshold float64) int {
+	switch {
+	case len(preds) == 1:
+		if targets[0] > threshold {
+			return 1
+		}
+		return 0
+	case len(targets) == 1:
+		return int(targets[0]) // Class index target
+	}
+	return labels.Argmax(targets)
+}
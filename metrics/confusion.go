@@ -0,0 +1,253 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfusionMatrix counts, for every true class, how often each class was
+// predicted. Classes are derived from model outputs and targets as for
+// Accuracy: thresholded single outputs give the binary classes 0 and 1,
+// several outputs are compared by argmax.
+type ConfusionMatrix struct {
+	Classes   int
+	Threshold float64 // Binary decision threshold for single-output models
+	Counts    [][]int // Counts[true][predicted]
+}
+
+// NewConfusionMatrix creates an empty confusion matrix for the given number of
+// classes (2 for binary problems) with the binary threshold 0.5.
+func NewConfusionMatrix(classes int) *ConfusionMatrix {
+	cm := ConfusionMatrix{Classes: classes, Threshold: 0.5}
+	cm.Reset()
+	return &cm
+}
+
+// Reset clears all counts.
+func (cm *ConfusionMatrix) Reset() {
+	cm.Counts = make([][]int, cm.Classes)
+	for i := range cm.Counts {
+		cm.Counts[i] = make([]int, cm.Classes)
+	}
+}
+
+// Update records the predicted and true class of one example.
+func (cm *ConfusionMatrix) Update(preds, targets []float64) {
+	pred := predictedClass(preds, cm.Threshold)
+	truth := trueClass(preds, targets, cm.Threshold)
+	if pred >= cm.Classes || truth < 0 || truth >= cm.Classes {
+		panic(fmt.Sprintf("metrics: class out of range for %d classes (predicted %d, true %d)", cm.Classes, pred, truth))
+	}
+	cm.Counts[truth][pred]++
+}
+
+// Total returns the number of recorded examples.
+func (cm *ConfusionMatrix) Total() int {
+	n := 0
+	for _, row := range cm.Counts {
+		for _, c := range row {
+			n += c
+		}
+	}
+	return n
+}
+
+// Support returns the number of examples whose true class is class.
+func (cm *ConfusionMatrix) Support(class int) int {
+	n := 0
+	for _, c := range cm.Counts[class] {
+		n += c
+	}
+	return n
+}
+
+// counts returns the true positives, false positives and false negatives of class.
+func (cm *ConfusionMatrix) counts(class int) (tp, fp, fn int) {
+	tp = cm.Counts[class][class]
+	for other := 0; other < cm.Classes; other++ {
+		if other != class {
+			fp += cm.Counts[other][class]
+			fn += cm.Counts[class][other]
+		}
+	}
+	return tp, fp, fn
+}
+
+// ratio returns a / b, or 0 if b is 0.
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Precision returns the fraction of examples predicted as class that truly are.
+func (cm *ConfusionMatrix) Precision(class int) float64 {
+	tp, fp, _ := cm.counts(class)
+	return ratio(tp, tp+fp)
+}
+
+// Recall returns the fraction of examples of class that were predicted as such.
+func (cm *ConfusionMatrix) Recall(class int) float64 {
+	tp, _, fn := cm.counts(class)
+	return ratio(tp, tp+fn)
+}
+
+// F1 returns the harmonic mean of the precision and recall of class.
+func (cm *ConfusionMatrix) F1(class int) float64 {
+	tp, fp, fn := cm.counts(class)
+	return ratio(2*tp, 2*tp+fp+fn)
+}
+
+// String renders the matrix as a table with true classes as rows and
+// predicted classes as columns.
+func (cm *ConfusionMatrix) String() string {
+	width := len(fmt.Sprint(cm.Total()))
+	width = max(width, len(fmt.Sprint(cm.Classes-1))+1)
+
+	var sb strings.Builder
+	sb.WriteString("true \\ pred")
+	for p := 0; p < cm.Classes; p++ {
+		fmt.Fprintf(&sb, " %*d", width, p)
+	}
+	sb.WriteString("\n")
+	for t, row := range cm.Counts {
+		fmt.Fprintf(&sb, "%11d", t)
+		for _, c := range row {
+			fmt.Fprintf(&sb, " %*d", width, c)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Average selects how per-class scores are combined into one number.
+type Average int
+
+const (
+	// Binary reports the score of the positive class only.
+	Binary Average = iota
+	// Macro is the unweighted mean of the per-class scores.
+	Macro
+	// Micro computes the score from the counts pooled over all classes.
+	Micro
+	// Weighted is the mean of the per-class scores weighted by class support.
+	Weighted
+)
+
+// String returns the lower-case name of the averaging mode.
+func (a Average) String() string {
+	switch a {
+	case Binary:
+		return "binary"
+	case Macro:
+		return "macro"
+	case Micro:
+		return "micro"
+	case Weighted:
+		return "weighted"
+	}
+	return fmt.Sprintf("Average(%d)", int(a))
+}
+
+// average combines the per-class score (given by perClass, and by pooled
+// counts for Micro) according to avg.
+func (cm *ConfusionMatrix) average(avg Average, positive int, perClass func(int) float64, pooled func(tp, fp, fn int) float64) float64 {
+	switch avg {
+	case Binary:
+		return perClass(positive)
+	case Micro:
+		var tp, fp, fn int
+		for c := 0; c < cm.Classes; c++ {
+			ctp, cfp, cfn := cm.counts(c)
+			tp, fp, fn = tp+ctp, fp+cfp, fn+cfn
+		}
+		return pooled(tp, fp, fn)
+	case Weighted:
+		sum := 0.0
+		for c := 0; c < cm.Classes; c++ {
+			sum += perClass(c) * float64(cm.Support(c))
+		}
+		return sum / max(1, float64(cm.Total()))
+	}
+	sum := 0.0
+	for c := 0; c < cm.Classes; c++ {
+		sum += perClass(c)
+	}
+	return sum / float64(cm.Classes)
+}
+
+// Precision is a Metric reporting precision averaged over classes.
+type Precision struct {
+	*ConfusionMatrix
+	Average  Average
+	Positive int // Positive class for Binary averaging
+}
+
+// NewPrecision creates a precision metric over the given number of classes.
+// Binary averaging uses class 1 as the positive class.
+func NewPrecision(classes int, avg Average) *Precision {
+	return &Precision{ConfusionMatrix: NewConfusionMatrix(classes), Average: avg, Positive: 1}
+}
+
+// Name returns "precision".
+func (p *Precision) Name() string {
+	return "precision"
+}
+
+// Value returns the averaged precision.
+func (p *Precision) Value() float64 {
+	return p.average(p.Average, p.Positive, p.ConfusionMatrix.Precision, func(tp, fp, fn int) float64 {
+		return ratio(tp, tp+fp)
+	})
+}
+
+// Recall is a Metric reporting recall averaged over classes.
+type Recall struct {
+	*ConfusionMatrix
+	Average  Average
+	Positive int // Positive class for Binary averaging
+}
+
+// NewRecall creates a recall metric over the given number of classes.
+// Binary averaging uses class 1 as the positive class.
+func NewRecall(classes int, avg Average) *Recall {
+	return &Recall{ConfusionMatrix: NewConfusionMatrix(classes), Average: avg, Positive: 1}
+}
+
+// Name returns "recall".
+func (r *Recall) Name() string {
+	return "recall"
+}
+
+// Value returns the averaged recall.
+func (r *Recall) Value() float64 {
+	return r.average(r.Average, r.Positive, r.ConfusionMatrix.Recall, func(tp, fp, fn int) float64 {
+		return ratio(tp, tp+fn)
+	})
+}
+
+// F1 is a Metric reporting the F1 score averaged over classes.
+type F1 struct {
+	*ConfusionMatrix
+	Average  Average
+	Positive int // Positive class for Binary averaging
+}
+
+// NewF1 creates an F1 metric over the given number of classes.
+// Binary averaging uses class 1 as the positive class.
+func NewF1(classes int, avg Average) *F1 {
+	return &F1{ConfusionMatrix: NewConfusionMatrix(classes), Average: avg, Positive: 1}
+}
+
+// Name returns "f1".
+func (f *F1) Name() string {
+	return "f1"
+}
+
+// Value returns the averaged F1 score.
+func (f *F1) Value() float64 {
+	return f.average(f.Average, f.Positive, f.ConfusionMatrix.F1, func(tp, fp, fn int) float64 {
+		return ratio(2*tp, 2*tp+fp+fn)
+	})
+}
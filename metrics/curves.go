@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// Scores accumulates the positive-class scores and true labels of a binary
+// classifier, for metrics that sweep over all decision thresholds.
+// With a single model output the score is that output and an example is
+// positive if its target exceeds LabelThreshold; with several outputs the
+// score is output Positive and an example is positive if Positive is the
+// argmax of its target (or equals a class index target).
+type Scores struct {
+	Positive       int     // Output index of the positive class (multi-output models)
+	LabelThreshold float64 // Targets above it are positive (single-output models)
+	Scores         []float64
+	Labels         []bool
+}
+
+// Reset discards the accumulated scores.
+func (s *Scores) Reset() {
+	s.Scores, s.Labels = nil, nil
+}
+
+// Update records the score and label of one example.
+func (s *Scores) Update(preds, targets []float64) {
+	if len(preds) == 1 {
+		s.Scores = append(s.Scores, preds[0])
+		s.Labels = append(s.Labels, targets[0] > s.LabelThreshold)
+		return
+	}
+	s.Scores = append(s.Scores, preds[s.Positive])
+	s.Labels = append(s.Labels, trueClass(preds, targets, 0) == s.Positive)
+}
+
+// ROCPoint is a point of a receiver operating characteristic curve.
+type ROCPoint struct {
+	Threshold float64 `json:"threshold"` // Scores >= Threshold are predicted positive
+	FPR       float64 `json:"fpr"`       // False positive rate
+	TPR       float64 `json:"tpr"`       // True positive rate (recall)
+}
+
+// PRPoint is a point of a precision-recall curve.
+type PRPoint struct {
+	Threshold float64 `json:"threshold"` // Scores >= Threshold are predicted positive
+	Recall    float64 `json:"recall"`
+	Precision float64 `json:"precision"`
+}
+
+// sweep sorts the examples by decreasing score and calls visit with the
+// cumulative true and false positive counts at every distinct threshold.
+func sweep(scores []float64, labels []bool, visit func(threshold float64, tp, fp int)) (pos, neg int) {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+	tp, fp := 0, 0
+	for i, idx := range order {
+		if labels[idx] {
+			tp++
+		} else {
+			fp++
+		}
+		// Tied scores share a threshold, so only emit after the last of them
+		if i+1 == len(order) || scores[order[i+1]] != scores[idx] {
+			visit(scores[idx], tp, fp)
+		}
+	}
+	return tp, fp
+}
+
+// ROCCurve returns the ROC curve of the given scores and labels, starting at
+// (0, 0) and ending at (1, 1), ordered by decreasing threshold.
+func ROCCurve(scores []float64, labels []bool) []ROCPoint {
+	var counts [][3]float64
+	pos, neg := sweep(scores, labels, func(threshold float64, tp, fp int) {
+		counts = append(counts, [3]float64{threshold, float64(tp), float64(fp)})
+	})
+
+	points := make([]ROCPoint, 0, len(counts)+1)
+	points = append(points, ROCPoint{Threshold: math.MaxFloat64, FPR: 0, TPR: 0}) // Nothing predicted positive
+	for _, c := range counts {
+		points = append(points, ROCPoint{Threshold: c[0], FPR: c[2] / max(1, float64(neg)), TPR: c[1] / max(1, float64(pos))})
+	}
+	return points
+}
+
+// PRCurve returns the precision-recall curve of the given scores and labels,
+// ordered by decreasing threshold (increasing recall).
+func PRCurve(scores []float64, labels []bool) []PRPoint {
+	var points []PRPoint
+	var counts [][3]float64
+	pos, _ := sweep(scores, labels, func(threshold float64, tp, fp int) {
+		counts = append(counts, [3]float64{threshold, float64(tp), float64(fp)})
+	})
+	for _, c := range counts {
+		points = append(points, PRPoint{Threshold: c[0], Recall: c[1] / max(1, float64(pos)), Precision: c[1] / (c[1] + c[2])})
+	}
+	return points
+}
+
+// ROCAUCOf returns the area under the ROC curve (trapezoidal rule): the
+// probability that a random positive is scored above a random negative.
+// It is 0.5 if only one class is present.
+func ROCAUCOf(scores []float64, labels []bool) float64 {
+	points := ROCCurve(scores, labels)
+	if points[len(points)-1].FPR == 0 || points[len(points)-1].TPR == 0 {
+		return 0.5 // Undefined without both classes
+	}
+	area := 0.0
+	for i := 1; i < len(points); i++ {
+		area += (points[i].FPR - points[i-1].FPR) * (points[i].TPR + points[i-1].TPR) / 2
+	}
+	return area
+}
+
+// AveragePrecision returns the area under the precision-recall curve as the
+// recall-weighted mean of the precisions, sum((R_i - R_i-1) * P_i), which
+// avoids the optimism of linear interpolation. It is 0 without positives.
+func AveragePrecision(scores []float64, labels []bool) float64 {
+	area, prevRecall := 0.0, 0.0
+	for _, p := range PRCurve(scores, labels) {
+		area += (p.Recall - prevRecall) * p.Precision
+		prevRecall = p.Recall
+	}
+	return area
+}
+
+// ROCAUC is a Metric reporting the area under the ROC curve. Create it with
+// NewROCAUC; the zero value takes output 0 of multi-output models as the
+// positive class.
+type ROCAUC struct {
+	Scores
+}
+
+// NewROCAUC creates a ROC AUC metric. Like NewPrecision and the other
+// constructors, it uses class 1 as the positive class.
+func NewROCAUC() *ROCAUC {
+	return &ROCAUC{Scores: Scores{Positive: 1}}
+}
+
+// Name returns "roc_auc".
+func (m *ROCAUC) Name() string {
+	return "roc_auc"
+}
+
+// Value returns the ROC AUC of the accumulated scores.
+func (m *ROCAUC) Value() float64 {
+	if len(m.Scores.Scores) == 0 {
+		return 0
+	}
+	return ROCAUCOf(m.Scores.Scores, m.Labels)
+}
+
+// Curve returns the ROC curve of the accumulated scores.
+func (m *ROCAUC) Curve() []ROCPoint {
+	return ROCCurve(m.Scores.Scores, m.Labels)
+}
+
+// PRAUC is a Metric reporting the area under the precision-recall curve
+// (average precision). Create it with NewPRAUC; the zero value takes output 0
+// of multi-output models as the positive class.
+type PRAUC struct {
+	Scores
+}
+
+// NewPRAUC creates a PR AUC metric with class 1 as the positive class.
+func NewPRAUC() *PRAUC {
+	return &PRAUC{Scores: Scores{Positive: 1}}
+}
+
+// Name returns "pr_auc".
+func (m *PRAUC) Name() string {
+	return "pr_auc"
+}
+
+// Value returns the average precision of the accumulated scores.
+func (m *PRAUC) Value() float64 {
+	return AveragePrecision(m.Scores.Scores, m.Labels)
+}
+
+// Curve returns the precision-recall curve of the accumulated scores.
+func (m *PRAUC) Curve() []PRPoint {
+	return PRCurve(m.Scores.Scores, m.Labels)
+}
@@ -0,0 +1,13 @@
+// Package metrics provides evaluation metrics that are accumulated example by
+// example (or batch by batch) and read out at the end of an epoch.
+package metrics
+
+// Metric accumulates predictions and targets and summarizes them as a single
+// number. Update is called once per example with the model outputs and the
+// target vector; Value reports the metric over everything seen since Reset.
+type Metric interface {
+	Name() string
+	Reset()
+	Update(preds, targets []float64)
+	Value() float64
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import "math"
+
+// Regression metrics treat every output of every example as one prediction
+// of the matching target value.
+
+// errorSums accumulates the sums needed by the regression metrics.
+type errorSums struct {
+	n        int
+	absErr   float64 // Σ|pred - target|
+	sqErr    float64 // Σ(pred - target)²
+	relErr   float64 // Σ|pred - target| / |target|
+	sum, sq  float64 // Σtarget and Σtarget², for the total variance
+	relCount int     // Number of targets included in relErr
+}
+
+// Reset clears the accumulated sums.
+func (s *errorSums) Reset() {
+	*s = errorSums{}
+}
+
+// Update adds the errors of one example.
+func (s *errorSums) Update(preds, targets []float64) {
+	for i, p := range preds {
+		y := targets[i]
+		d := p - y
+		s.n++
+		s.absErr += math.Abs(d)
+		s.sqErr += d * d
+		s.sum += y
+		s.sq += y * y
+		if y != 0 {
+			s.relErr += math.Abs(d / y)
+			s.relCount++
+		}
+	}
+}
+
+// MAE is the mean absolute error.
+type MAE struct {
+	errorSums
+}
+
+// Name returns "mae".
+func (m *MAE) Name() string {
+	return "mae"
+}
+
+// Value returns the mean absolute error, or 0 before any update.
+func (m *MAE) Value() float64 {
+	return m.absErr / max(1, float64(m.n))
+}
+
+// RMSE is the root mean squared error, in the units of the targets.
+type RMSE struct {
+	errorSums
+}
+
+// Name returns "rmse".
+func (m *RMSE) Name() string {
+	return "rmse"
+}
+
+// Value returns the root mean squared error, or 0 before any update.
+func (m *RMSE) Value() float64 {
+	return math.Sqrt(m.sqErr / max(1, float64(m.n)))
+}
+
+// MAPE is the mean absolute percentage error, reported as a fraction
+// (0.05 means 5%). Targets equal to zero are skipped, as their relative error
+// is undefined.
+type MAPE struct {
+	errorSums
+}
+
+// Name returns "mape".
+func (m *MAPE) Name() string {
+	return "mape"
+}
+
+// Value returns the mean absolute relative error over the non-zero targets.
+func (m *MAPE) Value() float64 {
+	return m.relErr / max(1, float64(m.relCount))
+}
+
+// R2 is the coefficient of determination, 1 - SSE / SST: 1 for perfect
+// predictions, 0 for always predicting the mean target, negative for worse.
+type R2 struct {
+	errorSums
+}
+
+// Name returns "r2".
+func (m *R2) Name() string {
+	return "r2"
+}
+
+// Value returns the coefficient of determination. If all targets are equal
+// it is 1 for perfect predictions and 0 otherwise.
+func (m *R2) Value() float64 {
+	if m.n == 0 {
+		return 0
+	}
+	sst := m.sq - m.sum*m.sum/float64(m.n)
+	if sst <= 0 {
+		if m.sqErr == 0 {
+			return 1
+		}
+		return 0
+	}
+	return 1 - m.sqErr/sst
+}
+
+// RegressionMetrics returns the full regression suite: R², RMSE, MAE and MAPE.
+func RegressionMetrics() []Metric {
+	return []Metric{&R2{}, &RMSE{}, &MAE{}, &MAPE{}}
+}
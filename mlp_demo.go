@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/losses"
+	"github.com/Rmehta-sudo/neural-net/metrics"
+	"github.com/Rmehta-sudo/neural-net/optim"
+	"github.com/Rmehta-sudo/neural-net/train"
+)
+
+/*
+testMLP demonstrates the usage and training of an MLP network.
+It sets up a binary classification problem, trains the MLP using mini-batch gradient
+descent (through train.Trainer),
+and prints the loss and predictions over iterations.
+Input features (xs) and target labels (ys)
+Create an MLP with:
+  - 3 input features (from xs)
+  - First hidden layer with 4 neurons
+  - Second hidden layer with 4 neurons
+  - Output layer with 1 neuron (for binary classification, typically one output before thresholding)
+*/
+func testMLP() {
+	fmt.Println("--- Testing MLP (Multi-Layer Perceptron) Training ---")
+	fmt.Println("This example demonstrates a simple binary classification task.")
+	fmt.Println("The goal is to train an MLP to map 3-dimensional inputs to a single output (-1.0 or 1.0).")
+
+	xs := [][]float64{
+		{2.0, 3.0, -1.0},
+		{3.0, -1.0, 0.5},
+		{0.5, 1.0, 1.0},
+		{1.0, 1.0, -1.0},
+	}
+
+	ys := []float64{1.0, -1.0, -1.0, 1.0} // Target values
+
+	fmt.Printf("\nDataset:\n  Inputs (xs): %v\n  Targets (ys): %v\n", xs, ys)
+
+	mlp := engine.NewMLPRand([]int{4, 4, 1}, 3, rand.New(rand.NewSource(42))) // Own generator: reproducible weights
+
+	fmt.Printf("\nMLP Architecture:\n%s\n", mlp.String())
+
+	learningRate := 0.1  // Learning rate for gradient descent
+	numIterations := 100 // Number of training epochs
+	batchSize := 2       // Examples per gradient step, so every epoch takes two steps
+
+	// Mini-batch gradient descent on the mean squared error
+	trainer := train.NewTrainer(mlp, losses.MSELoss{}, optim.NewSGD(mlp.Parameters(), learningRate))
+	trainer.Loader = data.NewLoader(data.NewScalarDataset(xs, ys), batchSize)
+	trainer.Epochs = numIterations
+	trainer.Metrics = []metrics.Metric{&metrics.Accuracy{Threshold: 0}} // Tanh outputs: the sign is the class
+	trainer.Progress = &train.EpochLog{W: os.Stdout, Every: 10}         // One line every 10 epochs instead of a progress bar
+
+	fmt.Printf("\nStarting Training for %d Iterations...\n", numIterations)
+	fmt.Printf("Learning Rate: %.4f, Batch Size: %d\n\n", learningRate, batchSize)
+
+	// Ctrl+C stops training at the next batch instead of killing the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := trainer.Fit(ctx); err != nil {
+		fmt.Println("Training failed:", err)
+		return
+	}
+
+	current := make([]float64, len(xs))
+	for i, x := range xs {
+		current[i] = mlp.Output(engine.ToValue1D(x))[0].Data
+	}
+	fmt.Printf("\nTarget Ys:  %s\n", formatFloats(ys))
+	fmt.Printf("Current Ys: %s\n\n", formatFloats(current))
+	fmt.Println("Use trainer.History.ToCSV or ToJSON to export the full training history.")
+
+	fmt.Println("--- Training Complete ---")
+	fmt.Println("To verify learned parameters, you can inspect 'mlp.Parameters()'")
+	fmt.Println("--- End TestMLP ---")
+	fmt.Println()
+}
+
+// formatFloats formats values as a bracketed, comma-separated list with 4 decimals.
+func formatFloats(vs []float64) string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = fmt.Sprintf("%.4f", v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
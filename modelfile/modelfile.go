@@ -0,0 +1,433 @@
+// Package modelfile stores MLPs in a versioned binary format based on
+// Protocol Buffers, together with metadata about their training. The schema
+// and its compatibility rules are in model.proto: fields are only ever added,
+// readers skip fields they do not know, and FormatVersion changes only when
+// old readers could not safely ignore a change, so files saved today keep
+// loading in future versions of the package.
+package modelfile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/internal/protowire"
+)
+
+// FormatVersion is the version written by Write; Read accepts it and any older one.
+const FormatVersion = 1
+
+// Model is the content of a model file.
+type Model struct {
+	MLP *engine.MLP
+
+	// Training describes how the model was trained, e.g. as returned by
+	// train.Trainer.Metadata. It is the same metadata engine.MLP.SaveWithMetadata
+	// stores, so models move between the formats without losing it. Optional.
+	Training *engine.ModelMetadata
+
+	Metadata map[string]string // Optional free-form annotations, e.g. a description
+}
+
+// Field numbers of model.proto.
+const (
+	modelVersion  = 1
+	modelInputs   = 2
+	modelLayers   = 3
+	modelTraining = 4
+	modelMetadata = 5
+
+	layerOutputs    = 1
+	layerActivation = 2
+	layerWeights    = 3
+	layerBiases     = 4
+
+	trainingEpochs       = 1
+	trainingLoss         = 2
+	trainingValLoss      = 3
+	trainingOptimizer    = 4
+	trainingLearningRate = 5
+	trainingMetrics      = 6
+	trainingSaved        = 7
+	trainingConfig       = 8
+	trainingPackage      = 9
+	trainingDataset      = 10
+	trainingStarted      = 11
+	trainingFinished     = 12
+
+	mapKey   = 1
+	mapValue = 2
+)
+
+// Write encodes m to w. All neurons of a layer must share one activation.
+// Empty PackageVersion and Saved fields of m.Training are filled in, as by
+// engine.MLP.SaveWithMetadata.
+func Write(w io.Writer, m *Model) error {
+	if m.MLP == nil || len(m.MLP.Layers) == 0 || len(m.MLP.Layers[0].Neurons) == 0 {
+		return fmt.Errorf("modelfile: model has no layers")
+	}
+	b := protowire.AppendVarint(nil, modelVersion, FormatVersion)
+	b = protowire.AppendVarint(b, modelInputs, uint64(len(m.MLP.Layers[0].Neurons[0].Weights)))
+	for i, layer := range m.MLP.Layers {
+		lb, err := encodeLayer(layer)
+		if err != nil {
+			return fmt.Errorf("modelfile: layer %d: %w", i+1, err)
+		}
+		b = protowire.AppendBytes(b, modelLayers, lb)
+	}
+	if m.Training != nil {
+		meta := *m.Training
+		if meta.PackageVersion == "" {
+			meta.PackageVersion = engine.PackageVersion()
+		}
+		if meta.Saved.IsZero() {
+			meta.Saved = time.Now().UTC()
+		}
+		b = protowire.AppendBytes(b, modelTraining, encodeTraining(&meta))
+	}
+	for _, k := range sortedKeys(m.Metadata) {
+		entry := protowire.AppendString(nil, mapKey, k)
+		entry = protowire.AppendString(entry, mapValue, m.Metadata[k])
+		b = protowire.AppendBytes(b, modelMetadata, entry)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// encodeLayer encodes a Layer message.
+func encodeLayer(l *engine.Layer) ([]byte, error) {
+	if len(l.Neurons) == 0 {
+		return nil, fmt.Errorf("no neurons")
+	}
+	act := l.Neurons[0].Activation
+	var weights, biases []float64
+	for _, neuron := range l.Neurons {
+		if neuron.Activation != act {
+			return nil, fmt.Errorf("mixes %v and %v activations", act, neuron.Activation)
+		}
+		for _, w := range neuron.Weights {
+			weights = append(weights, w.Data)
+		}
+		biases = append(biases, neuron.Bias.Data)
+	}
+	b := protowire.AppendVarint(nil, layerOutputs, uint64(len(l.Neurons)))
+	b = protowire.AppendString(b, layerActivation, act.String())
+	b = protowire.AppendPackedDoubles(b, layerWeights, weights)
+	return protowire.AppendPackedDoubles(b, layerBiases, biases), nil
+}
+
+// Keys of ModelMetadata stored in the dedicated fields of the Training
+// message, which predate the generic ones, rather than in its maps.
+const (
+	keyEpochs       = "epochs"
+	keyOptimizer    = "optimizer"
+	keyLearningRate = "learning_rate"
+	keyLoss         = "loss"
+	keyValLoss      = "val_loss"
+)
+
+// encodeTraining encodes a Training message. The final loss, validation loss,
+// epochs, optimizer and learning rate go in their own fields, so files stay
+// readable by versions that know only those.
+func encodeTraining(meta *engine.ModelMetadata) []byte {
+	var b []byte
+	config := map[string]string{}
+	for k, v := range meta.Training {
+		config[k] = v
+	}
+	if epochs, err := strconv.ParseUint(config[keyEpochs], 10, 64); err == nil {
+		b = protowire.AppendVarint(b, trainingEpochs, epochs)
+		delete(config, keyEpochs)
+	}
+	metrics := map[string]float64{}
+	for k, v := range meta.Metrics {
+		metrics[k] = v
+	}
+	if loss, ok := metrics[keyLoss]; ok {
+		b = protowire.AppendDouble(b, trainingLoss, loss)
+		delete(metrics, keyLoss)
+	}
+	if loss, ok := metrics[keyValLoss]; ok {
+		b = protowire.AppendDouble(b, trainingValLoss, loss)
+		delete(metrics, keyValLoss)
+	}
+	if opt, ok := config[keyOptimizer]; ok {
+		b = protowire.AppendString(b, trainingOptimizer, opt)
+		delete(config, keyOptimizer)
+	}
+	if lr, err := strconv.ParseFloat(config[keyLearningRate], 64); err == nil {
+		b = protowire.AppendDouble(b, trainingLearningRate, lr)
+		delete(config, keyLearningRate)
+	}
+	for _, k := range sortedKeys(metrics) {
+		entry := protowire.AppendString(nil, mapKey, k)
+		entry = protowire.AppendDouble(entry, mapValue, metrics[k])
+		b = protowire.AppendBytes(b, trainingMetrics, entry)
+	}
+	b = appendTime(b, trainingSaved, meta.Saved)
+	for _, k := range sortedKeys(config) {
+		entry := protowire.AppendString(nil, mapKey, k)
+		entry = protowire.AppendString(entry, mapValue, config[k])
+		b = protowire.AppendBytes(b, trainingConfig, entry)
+	}
+	if meta.PackageVersion != "" {
+		b = protowire.AppendString(b, trainingPackage, meta.PackageVersion)
+	}
+	if meta.DatasetFingerprint != "" {
+		b = protowire.AppendString(b, trainingDataset, meta.DatasetFingerprint)
+	}
+	b = appendTime(b, trainingStarted, meta.TrainingStarted)
+	return appendTime(b, trainingFinished, meta.TrainingFinished)
+}
+
+// appendTime appends t in seconds since the epoch, unless it is zero.
+func appendTime(b []byte, num int, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	return protowire.AppendVarint(b, num, uint64(t.Unix()))
+}
+
+// sortedKeys returns the keys of m in order, so encoding is deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Read decodes a model file from r. Fields unknown to this version, written
+// by newer versions of the package, are skipped.
+func Read(r io.Reader) (*Model, error) {
+	m, _, err := read(r)
+	return m, err
+}
+
+// read decodes a model file from r and returns it with its format version.
+func read(r io.Reader) (*Model, int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	fields, err := protowire.Fields(data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("modelfile: %w", err)
+	}
+
+	m := &Model{}
+	var version, inputs int
+	var layers []layerSpec
+	for _, f := range fields {
+		switch f.Number {
+		case modelVersion:
+			version = int(f.Num)
+		case modelInputs:
+			inputs = int(f.Num)
+		case modelLayers:
+			l, err := decodeLayer(f.Bytes)
+			if err != nil {
+				return nil, 0, fmt.Errorf("modelfile: layer %d: %w", len(layers)+1, err)
+			}
+			layers = append(layers, l)
+		case modelTraining:
+			if m.Training, err = decodeTraining(f.Bytes); err != nil {
+				return nil, 0, fmt.Errorf("modelfile: training: %w", err)
+			}
+		case modelMetadata:
+			k, v, err := decodeEntry(f.Bytes)
+			if err != nil {
+				return nil, 0, fmt.Errorf("modelfile: metadata: %w", err)
+			}
+			if m.Metadata == nil {
+				m.Metadata = map[string]string{}
+			}
+			m.Metadata[k] = v.String()
+		}
+	}
+	if version < 1 || version > FormatVersion {
+		return nil, 0, fmt.Errorf("modelfile: format version %d is not supported (newest known is %d)", version, FormatVersion)
+	}
+	if m.MLP, err = buildMLP(inputs, layers); err != nil {
+		return nil, 0, fmt.Errorf("modelfile: %w", err)
+	}
+	return m, version, nil
+}
+
+// Inspect reads the architecture and training metadata of the model file at
+// path without building the network, like engine.InspectModel does for the
+// JSON and gob formats, which it does not recognise. Format is "modelfile".
+func Inspect(path string) (*engine.ModelInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, version, err := read(f)
+	if err != nil {
+		return nil, err
+	}
+	info := &engine.ModelInfo{
+		Format:   "modelfile",
+		Version:  version,
+		Inputs:   len(m.MLP.Layers[0].Neurons[0].Weights),
+		Metadata: m.Training,
+	}
+	for _, layer := range m.MLP.Layers {
+		info.LayerSizes = append(info.LayerSizes, len(layer.Neurons))
+		info.Activations = append(info.Activations, layer.Neurons[0].Activation)
+		for _, n := range layer.Neurons {
+			info.Parameters += len(n.Weights) + 1
+		}
+	}
+	return info, nil
+}
+
+// layerSpec is a decoded Layer message.
+type layerSpec struct {
+	outputs         int
+	activation      engine.Activation
+	weights, biases []float64
+}
+
+// decodeLayer decodes a Layer message.
+func decodeLayer(b []byte) (layerSpec, error) {
+	var l layerSpec
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return l, err
+	}
+	for _, f := range fields {
+		switch f.Number {
+		case layerOutputs:
+			l.outputs = int(f.Num)
+		case layerActivation:
+			if l.activation, err = engine.ParseActivation(f.String()); err != nil {
+				return l, err
+			}
+		case layerWeights:
+			if l.weights, err = protowire.Doubles(l.weights, f); err != nil {
+				return l, err
+			}
+		case layerBiases:
+			if l.biases, err = protowire.Doubles(l.biases, f); err != nil {
+				return l, err
+			}
+		}
+	}
+	return l, nil
+}
+
+// decodeTraining decodes a Training message.
+func decodeTraining(b []byte) (*engine.ModelMetadata, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return nil, err
+	}
+	meta := &engine.ModelMetadata{}
+	setConfig := func(k, v string) {
+		if meta.Training == nil {
+			meta.Training = map[string]string{}
+		}
+		meta.Training[k] = v
+	}
+	setMetric := func(k string, v float64) {
+		if meta.Metrics == nil {
+			meta.Metrics = map[string]float64{}
+		}
+		meta.Metrics[k] = v
+	}
+	for _, f := range fields {
+		switch f.Number {
+		case trainingEpochs:
+			setConfig(keyEpochs, strconv.FormatUint(f.Num, 10))
+		case trainingLoss:
+			setMetric(keyLoss, f.Float64())
+		case trainingValLoss:
+			if v := f.Float64(); v != 0 { // Version 1 files always wrote it, 0 without validation
+				setMetric(keyValLoss, v)
+			}
+		case trainingOptimizer:
+			setConfig(keyOptimizer, f.String())
+		case trainingLearningRate:
+			setConfig(keyLearningRate, strconv.FormatFloat(f.Float64(), 'g', -1, 64))
+		case trainingMetrics:
+			k, v, err := decodeEntry(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			setMetric(k, v.Float64())
+		case trainingSaved:
+			meta.Saved = time.Unix(f.Int(), 0)
+		case trainingConfig:
+			k, v, err := decodeEntry(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			setConfig(k, v.String())
+		case trainingPackage:
+			meta.PackageVersion = f.String()
+		case trainingDataset:
+			meta.DatasetFingerprint = f.String()
+		case trainingStarted:
+			meta.TrainingStarted = time.Unix(f.Int(), 0)
+		case trainingFinished:
+			meta.TrainingFinished = time.Unix(f.Int(), 0)
+		}
+	}
+	return meta, nil
+}
+
+// decodeEntry decodes a map entry into its key and value field.
+func decodeEntry(b []byte) (string, protowire.Field, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return "", protowire.Field{}, err
+	}
+	var key string
+	var value protowire.Field
+	for _, f := range fields {
+		switch f.Number {
+		case mapKey:
+			key = f.String()
+		case mapValue:
+			value = f
+		}
+	}
+	return key, value, nil
+}
+
+// buildMLP rebuilds the MLP from its decoded layers after checking their sizes.
+func buildMLP(inputs int, layers []layerSpec) (*engine.MLP, error) {
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("model has no layers")
+	}
+	mlp := &engine.MLP{Layers: make([]*engine.Layer, len(layers))}
+	numIn := inputs
+	for i, l := range layers {
+		if len(l.weights) != l.outputs*numIn || len(l.biases) != l.outputs {
+			return nil, fmt.Errorf("layer %d has %d weights and %d biases for %d inputs and %d outputs",
+				i+1, len(l.weights), len(l.biases), numIn, l.outputs)
+		}
+		layer := engine.Layer{Neurons: make([]*engine.Neuron, l.outputs)}
+		for o := range layer.Neurons {
+			neuron := engine.Neuron{
+				Weights:    make([]*engine.Value, numIn),
+				Bias:       engine.NewValue(l.biases[o], "b"),
+				Activation: l.activation,
+			}
+			for j := range neuron.Weights {
+				neuron.Weights[j] = engine.NewValue(l.weights[o*numIn+j], fmt.Sprintf("w%d", j+1))
+			}
+			layer.Neurons[o] = &neuron
+		}
+		mlp.Layers[i] = &layer
+		numIn = l.outputs
+	}
+	return mlp, nil
+}
@@ -0,0 +1,265 @@
+// Package npy reads and writes arrays in NumPy's .npy format and archives of
+// them in the .npz format, so weights can be exchanged with Python scripts:
+// a PyTorch state_dict saved with numpy.savez loads into a model here by
+// parameter name (see LoadParams), and Params exports a model the same way.
+package npy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Array is an n-dimensional array with its values in row-major (C) order.
+type Array struct {
+	Shape []int
+	Data  []float64
+}
+
+// Size returns the number of values the shape holds.
+func (a *Array) Size() int {
+	n := 1
+	for _, d := range a.Shape {
+		n *= d
+	}
+	return n
+}
+
+// magic starts every .npy file.
+const magic = "\x93NUMPY"
+
+// maxHeader bounds the header size to reject corrupt files before allocating;
+// NumPy writes headers of well under a kilobyte.
+const maxHeader = 1 << 20
+
+// readChunk is the number of values decoded per read, so memory grows with the
+// data actually present rather than with the shape claimed by the header.
+const readChunk = 1 << 16
+
+// Header fields of a .npy file; header values are written by NumPy as a
+// Python dict literal such as {'descr': '<f4', 'fortran_order': False, 'shape': (3, 4), }.
+var (
+	descrRe   = regexp.MustCompile(`'descr':\s*'([^']*)'`)
+	fortranRe = regexp.MustCompile(`'fortran_order':\s*(True|False)`)
+	shapeRe   = regexp.MustCompile(`'shape':\s*\(([^)]*)\)`)
+)
+
+// Read decodes a .npy array from r. Little- and big-endian floats (f4, f8)
+// and signed or unsigned integers (i1 to i8, u1 to u8) are converted to
+// float64; arrays in Fortran order are reordered to row-major order.
+func Read(r io.Reader) (*Array, error) {
+	br := bufio.NewReader(r)
+	pre := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(br, pre); err != nil {
+		return nil, fmt.Errorf("npy: reading header: %w", err)
+	}
+	if string(pre[:len(magic)]) != magic {
+		return nil, fmt.Errorf("npy: not a .npy file")
+	}
+	var headerLen int
+	switch major := pre[len(magic)]; major {
+	case 1:
+		var n uint16
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return nil, fmt.Errorf("npy: reading header: %w", err)
+		}
+		headerLen = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(br, binary.LittleEndian, &n); err != nil {
+			return nil, fmt.Errorf("npy: reading header: %w", err)
+		}
+		headerLen = int(n)
+	default:
+		return nil, fmt.Errorf("npy: unsupported format version %d", major)
+	}
+	if headerLen > maxHeader {
+		return nil, fmt.Errorf("npy: header of %d bytes is too large", headerLen)
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("npy: reading header: %w", err)
+	}
+
+	descr, fortran, shape, err := parseHeader(string(header))
+	if err != nil {
+		return nil, err
+	}
+	size, ok := checkedSize(shape)
+	if !ok {
+		return nil, fmt.Errorf("npy: shape %v is too large", shape)
+	}
+	a := &Array{Shape: shape}
+	if a.Data, err = readValues(br, descr, size); err != nil {
+		return nil, err
+	}
+	if fortran && len(shape) > 1 {
+		a.Data = fromFortran(a.Data, shape)
+	}
+	return a, nil
+}
+
+// checkedSize returns the number of values of shape, or false if it overflows.
+func checkedSize(shape []int) (int, bool) {
+	n := 1
+	for _, d := range shape {
+		if d != 0 && n > math.MaxInt/d {
+			return 0, false
+		}
+		n *= d
+	}
+	return n, true
+}
+
+// parseHeader extracts the dtype, memory order and shape from a header.
+func parseHeader(h string) (descr string, fortran bool, shape []int, err error) {
+	m := descrRe.FindStringSubmatch(h)
+	if m == nil {
+		return "", false, nil, fmt.Errorf("npy: header has no descr: %q", h)
+	}
+	descr = m[1]
+	if m := fortranRe.FindStringSubmatch(h); m != nil {
+		fortran = m[1] == "True"
+	}
+	m = shapeRe.FindStringSubmatch(h)
+	if m == nil {
+		return "", false, nil, fmt.Errorf("npy: header has no shape: %q", h)
+	}
+	shape = []int{} // A scalar has the empty shape ()
+	for _, d := range strings.Split(m[1], ",") {
+		if d = strings.TrimSpace(d); d == "" {
+			continue
+		}
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 0 {
+			return "", false, nil, fmt.Errorf("npy: invalid shape (%s)", m[1])
+		}
+		shape = append(shape, n)
+	}
+	return descr, fortran, shape, nil
+}
+
+// readValues decodes n values of dtype descr from r.
+func readValues(r io.Reader, descr string, n int) ([]float64, error) {
+	if len(descr) < 3 {
+		return nil, fmt.Errorf("npy: unsupported dtype %q", descr)
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	switch descr[0] {
+	case '>':
+		order = binary.BigEndian
+	case '<', '|', '=':
+	default:
+		return nil, fmt.Errorf("npy: unsupported dtype %q", descr)
+	}
+	kind := descr[1]
+	size, err := strconv.Atoi(descr[2:])
+	if err != nil {
+		return nil, fmt.Errorf("npy: unsupported dtype %q", descr)
+	}
+
+	var decode func(b []byte) float64
+	switch {
+	case kind == 'f' && size == 4:
+		decode = func(b []byte) float64 { return float64(math.Float32frombits(order.Uint32(b))) }
+	case kind == 'f' && size == 8:
+		decode = func(b []byte) float64 { return math.Float64frombits(order.Uint64(b)) }
+	case (kind == 'i' || kind == 'u' || kind == 'b') && (size == 1 || size == 2 || size == 4 || size == 8):
+		decode = func(b []byte) float64 { return integer(b, order, kind != 'i') }
+	default:
+		return nil, fmt.Errorf("npy: unsupported dtype %q", descr)
+	}
+
+	if n > math.MaxInt/size {
+		return nil, fmt.Errorf("npy: %d values are too many", n)
+	}
+	dst := make([]float64, 0, min(n, readChunk))
+	b := make([]byte, size*min(n, readChunk))
+	for len(dst) < n {
+		k := min(n-len(dst), readChunk)
+		if _, err := io.ReadFull(r, b[:k*size]); err != nil {
+			return nil, fmt.Errorf("npy: reading %d values: %w", n, err)
+		}
+		for i := 0; i < k; i++ {
+			dst = append(dst, decode(b[i*size:(i+1)*size]))
+		}
+	}
+	return dst, nil
+}
+
+// integer decodes an integer of len(b) bytes.
+func integer(b []byte, order binary.ByteOrder, unsigned bool) float64 {
+	var u uint64
+	switch len(b) {
+	case 1:
+		u = uint64(b[0])
+	case 2:
+		u = uint64(order.Uint16(b))
+	case 4:
+		u = uint64(order.Uint32(b))
+	default:
+		u = order.Uint64(b)
+	}
+	if unsigned {
+		return float64(u)
+	}
+	shift := 64 - 8*len(b) // Sign-extend
+	return float64(int64(u<<shift) >> shift)
+}
+
+// fromFortran reorders values stored in column-major order into row-major order.
+func fromFortran(data []float64, shape []int) []float64 {
+	out := make([]float64, len(data))
+	idx := make([]int, len(shape))
+	for i := range out {
+		// i is the row-major position of idx; find its column-major position
+		pos, stride := 0, 1
+		for d := range shape {
+			pos += idx[d] * stride
+			stride *= shape[d]
+		}
+		out[i] = data[pos]
+		for d := len(shape) - 1; d >= 0; d-- {
+			if idx[d]++; idx[d] < shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+	}
+	return out
+}
+
+// Write encodes a as a version 1.0 .npy array of little-endian float64 ('<f8').
+func Write(w io.Writer, a *Array) error {
+	if len(a.Data) != a.Size() {
+		return fmt.Errorf("npy: %d values for shape %v", len(a.Data), a.Shape)
+	}
+	dims := make([]string, len(a.Shape))
+	for i, d := range a.Shape {
+		dims[i] = strconv.Itoa(d)
+	}
+	shape := strings.Join(dims, ", ")
+	if len(a.Shape) == 1 {
+		shape += "," // A 1-tuple in Python
+	}
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%s), }", shape)
+	// Pad with spaces and end with a newline so the data is 64-byte aligned
+	total := len(magic) + 4 + len(header) + 1
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+
+	b := make([]byte, 0, len(magic)+4+len(header)+8*len(a.Data))
+	b = append(b, magic...)
+	b = append(b, 1, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(header)))
+	b = append(b, header...)
+	for _, v := range a.Data {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	_, err := w.Write(b)
+	return err
+}
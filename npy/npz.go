@@ -0,0 +1,73 @@
+package npy
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ReadNPZ reads every array of an .npz archive (as written by numpy.savez or
+// numpy.savez_compressed) of the given size, keyed by name without the ".npy"
+// extension.
+func ReadNPZ(r io.ReaderAt, size int64) (map[string]*Array, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("npy: reading archive: %w", err)
+	}
+	arrays := map[string]*Array{}
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".npy") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("npy: %s: %w", f.Name, err)
+		}
+		a, err := Read(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		arrays[strings.TrimSuffix(f.Name, ".npy")] = a
+	}
+	return arrays, nil
+}
+
+// OpenNPZ reads the .npz archive at path, see ReadNPZ.
+func OpenNPZ(path string) (map[string]*Array, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ReadNPZ(f, info.Size())
+}
+
+// WriteNPZ writes arrays to w as a compressed .npz archive, one "<name>.npy"
+// entry per array in order of name, readable with numpy.load.
+func WriteNPZ(w io.Writer, arrays map[string]*Array) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		fw, err := zw.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := Write(fw, arrays[name]); err != nil {
+			return fmt.Errorf("npy: %s: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,59 @@
+package npy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Params returns the parameters of m as arrays keyed by the names of
+// engine.NamedParameters (e.g. "layers.0.weight"), ready for WriteNPZ.
+func Params(m engine.Module) map[string]*Array {
+	arrays := map[string]*Array{}
+	for _, np := range engine.NamedParameters(m) {
+		a := &Array{Shape: append([]int(nil), np.Shape...), Data: make([]float64, len(np.Values))}
+		for i, v := range np.Values {
+			a.Data[i] = v.Data
+		}
+		arrays[np.Name] = a
+	}
+	return arrays
+}
+
+// LoadParams copies arrays into the parameters of m with the same names, as
+// returned by engine.NamedParameters. Like PyTorch's load_state_dict, every
+// parameter must be present with the same shape and no array may be left
+// over; nothing is copied if any does not match.
+func LoadParams(m engine.Module, arrays map[string]*Array) error {
+	named := engine.NamedParameters(m)
+	used := map[string]bool{}
+	for _, np := range named {
+		a, ok := arrays[np.Name]
+		if !ok {
+			return fmt.Errorf("npy: no array for parameter %q", np.Name)
+		}
+		if !slices.Equal(a.Shape, np.Shape) || len(a.Data) != len(np.Values) {
+			return fmt.Errorf("npy: array %q has shape %v, parameter has %v", np.Name, a.Shape, np.Shape)
+		}
+		used[np.Name] = true
+	}
+	var extra []string
+	for name := range arrays {
+		if !used[name] {
+			extra = append(extra, name)
+		}
+	}
+	if len(extra) > 0 {
+		sort.Strings(extra)
+		return fmt.Errorf("npy: arrays %v match no parameter", extra)
+	}
+
+	for _, np := range named {
+		for i, v := range arrays[np.Name].Data {
+			np.Values[i].Data = v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Package onnx imports feed-forward networks saved in the ONNX format, so
+// models trained with other frameworks (e.g. exported with torch.onnx.export)
+// can be evaluated or fine-tuned here.
+//
+// Only graphs that form a chain of dense layers are supported: Gemm, or MatMul
+// optionally followed by an Add of a bias, each optionally followed by Relu,
+// Tanh or Sigmoid. Identity, Dropout and Flatten nodes are skipped, as they
+// do nothing to a single input vector at inference time.
+package onnx
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Import reads an ONNX model from r and reconstructs it as an equivalent
+// MLP, with one layer per Gemm or MatMul node. Layers not followed by an
+// activation node are Linear.
+func Import(r io.Reader) (*engine.MLP, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	g, err := decodeModel(b)
+	if err != nil {
+		return nil, fmt.Errorf("onnx: decoding model: %w", err)
+	}
+	return g.mlp()
+}
+
+// ImportFile reads the ONNX model at path, see Import.
+func ImportFile(path string) (*engine.MLP, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Import(f)
+}
+
+// builder accumulates the layers of the MLP while the graph is walked.
+type builder struct {
+	layers    []*engine.Layer
+	numIn     int  // Inputs of the next layer; 0 until the first layer is known
+	biasable  bool // The last layer came from a MatMul and can still take an Add
+	activated bool // The last layer already has its activation
+}
+
+// mlp walks the nodes of the graph, which ONNX stores in topological order,
+// following the chain from the data input to the output.
+func (g *graph) mlp() (*engine.MLP, error) {
+	cur := ""
+	for _, name := range g.inputs {
+		if _, ok := g.initializers[name]; !ok {
+			cur = name // The first input without a stored value is the data
+			break
+		}
+	}
+	if cur == "" {
+		return nil, fmt.Errorf("onnx: graph has no data input")
+	}
+
+	var b builder
+	for _, n := range g.nodes {
+		if n.domain != "" && n.domain != "ai.onnx" {
+			return nil, fmt.Errorf("onnx: node %q: operator domain %q is not supported", n.name, n.domain)
+		}
+		if len(n.inputs) == 0 || len(n.outputs) != 1 {
+			return nil, fmt.Errorf("onnx: node %q (%s) does not have exactly one output", n.name, n.op)
+		}
+		var err error
+		switch n.op {
+		case "Gemm":
+			err = b.gemm(g, n, cur)
+		case "MatMul":
+			err = b.matMul(g, n, cur)
+		case "Add":
+			err = b.add(g, n, cur)
+		case "Relu", "Tanh", "Sigmoid":
+			err = b.activation(n, cur)
+		case "Identity", "Dropout", "Flatten":
+			if n.inputs[0] != cur {
+				err = fmt.Errorf("input %q is not the output of the previous node", n.inputs[0])
+			}
+		default:
+			err = fmt.Errorf("operator %s is not supported", n.op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("onnx: node %q (%s): %w", n.name, n.op, err)
+		}
+		cur = n.outputs[0]
+	}
+
+	if len(b.layers) == 0 {
+		return nil, fmt.Errorf("onnx: graph has no dense layers")
+	}
+	if len(g.outputs) != 1 || g.outputs[0] != cur {
+		return nil, fmt.Errorf("onnx: graph output is not the end of the layer chain")
+	}
+	return &engine.MLP{Layers: b.layers}, nil
+}
+
+// gemm adds the layer of Y = alpha * A * B' + beta * C, where A is the
+// current input row and B' is B, transposed if transB is set.
+func (b *builder) gemm(g *graph, n node, cur string) error {
+	if n.inputs[0] != cur || len(n.inputs) < 2 {
+		return fmt.Errorf("expected the previous output and a weight initializer as inputs")
+	}
+	if n.attrs["transA"].i != 0 {
+		return fmt.Errorf("transA is not supported")
+	}
+	alpha, beta := 1.0, 1.0
+	if a, ok := n.attrs["alpha"]; ok {
+		alpha = a.f
+	}
+	if a, ok := n.attrs["beta"]; ok {
+		beta = a.f
+	}
+
+	w, ok := g.initializers[n.inputs[1]]
+	if !ok || len(w.dims) != 2 {
+		return fmt.Errorf("weight %q is not a stored matrix", n.inputs[1])
+	}
+	if err := b.addLayer(w, n.attrs["transB"].i != 0, alpha); err != nil {
+		return err
+	}
+	if len(n.inputs) > 2 && n.inputs[2] != "" {
+		c, ok := g.initializers[n.inputs[2]]
+		if !ok {
+			return fmt.Errorf("bias %q is not stored", n.inputs[2])
+		}
+		return b.addBias(c, beta)
+	}
+	return nil
+}
+
+// matMul adds the layer of Y = A * B, which an Add node may give a bias.
+func (b *builder) matMul(g *graph, n node, cur string) error {
+	if n.inputs[0] != cur || len(n.inputs) != 2 {
+		return fmt.Errorf("expected the previous output and a weight initializer as inputs")
+	}
+	w, ok := g.initializers[n.inputs[1]]
+	if !ok || len(w.dims) != 2 {
+		return fmt.Errorf("weight %q is not a stored matrix", n.inputs[1])
+	}
+	if err := b.addLayer(w, false, 1); err != nil {
+		return err
+	}
+	b.biasable = true
+	return nil
+}
+
+// add sets the biases of the layer of the preceding MatMul.
+func (b *builder) add(g *graph, n node, cur string) error {
+	if len(n.inputs) != 2 {
+		return fmt.Errorf("expected two inputs")
+	}
+	other := n.inputs[1]
+	if n.inputs[1] == cur {
+		other = n.inputs[0]
+	} else if n.inputs[0] != cur {
+		return fmt.Errorf("neither input is the output of the previous node")
+	}
+	c, ok := g.initializers[other]
+	if !ok {
+		return fmt.Errorf("bias %q is not stored", other)
+	}
+	if !b.biasable {
+		return fmt.Errorf("only a bias directly after MatMul is supported")
+	}
+	b.biasable = false
+	return b.addBias(c, 1)
+}
+
+// activation sets the activation of the last layer.
+func (b *builder) activation(n node, cur string) error {
+	if n.inputs[0] != cur {
+		return fmt.Errorf("input %q is not the output of the previous node", n.inputs[0])
+	}
+	if len(b.layers) == 0 || b.activated {
+		return fmt.Errorf("an activation must directly follow a dense layer")
+	}
+	act := map[string]engine.Activation{"Relu": engine.ReLU, "Tanh": engine.Tanh, "Sigmoid": engine.Sigmoid}[n.op]
+	b.layers[len(b.layers)-1].WithActivation(act)
+	b.activated, b.biasable = true, false
+	return nil
+}
+
+// addLayer appends a Linear layer with zero biases and the weights of w
+// scaled by scale. w is stored [in, out], or [out, in] if transposed.
+func (b *builder) addLayer(w tensor, transposed bool, scale float64) error {
+	in, out := w.dims[0], w.dims[1]
+	if transposed {
+		in, out = out, in
+	}
+	if b.numIn != 0 && in != b.numIn {
+		return fmt.Errorf("weight has %d inputs, previous layer has %d outputs", in, b.numIn)
+	}
+
+	layer := engine.Layer{Neurons: make([]*engine.Neuron, out)}
+	for o := range layer.Neurons {
+		neuron := engine.Neuron{
+			Weights:    make([]*engine.Value, in),
+			Bias:       engine.NewValue(0, "b"),
+			Activation: engine.Linear,
+		}
+		for i := range neuron.Weights {
+			idx := i*out + o
+			if transposed {
+				idx = o*in + i
+			}
+			neuron.Weights[i] = engine.NewValue(scale*w.data[idx], fmt.Sprintf("w%d", i+1))
+		}
+		layer.Neurons[o] = &neuron
+	}
+	b.layers = append(b.layers, &layer)
+	b.numIn = out
+	b.biasable, b.activated = false, false
+	return nil
+}
+
+// addBias adds scale times c, one value per output or a single broadcast
+// value, to the biases of the last layer.
+func (b *builder) addBias(c tensor, scale float64) error {
+	neurons := b.layers[len(b.layers)-1].Neurons
+	if len(c.data) != len(neurons) && len(c.data) != 1 {
+		return fmt.Errorf("bias has %d values for %d outputs", len(c.data), len(neurons))
+	}
+	for o, neuron := range neurons {
+		v := c.data[0]
+		if len(c.data) > 1 {
+			v = c.data[o]
+		}
+		neuron.Bias.Data += scale * v
+	}
+	return nil
+}
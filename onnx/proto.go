@@ -0,0 +1,231 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/internal/protowire"
+)
+
+// The subset of the ONNX protobuf schema (onnx.proto) the importer reads,
+// with the field numbers of the official definition.
+
+// graph is a GraphProto.
+type graph struct {
+	nodes        []node
+	initializers map[string]tensor
+	inputs       []string // Names of the graph inputs, which may include initializers
+	outputs      []string
+}
+
+// node is a NodeProto: one operator applied to named tensors.
+type node struct {
+	name    string
+	op      string
+	domain  string
+	inputs  []string
+	outputs []string
+	attrs   map[string]attribute
+}
+
+// attribute is the scalar part of an AttributeProto.
+type attribute struct {
+	f float64
+	i int64
+}
+
+// tensor is a TensorProto holding float or double values.
+type tensor struct {
+	dims []int
+	data []float64
+}
+
+// TensorProto data types the importer understands.
+const (
+	typeFloat  = 1
+	typeDouble = 11
+)
+
+// decodeModel decodes a ModelProto and returns its graph.
+func decodeModel(b []byte) (*graph, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.Number == 7 && f.Type == protowire.Bytes {
+			return decodeGraph(f.Bytes)
+		}
+	}
+	return nil, fmt.Errorf("onnx: model has no graph")
+}
+
+// decodeGraph decodes a GraphProto.
+func decodeGraph(b []byte) (*graph, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return nil, err
+	}
+	g := graph{initializers: map[string]tensor{}}
+	for _, f := range fields {
+		switch f.Number {
+		case 1:
+			n, err := decodeNode(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			g.nodes = append(g.nodes, n)
+		case 5:
+			name, t, err := decodeTensor(f.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("onnx: initializer %q: %w", name, err)
+			}
+			g.initializers[name] = t
+		case 11, 12:
+			name, err := valueInfoName(f.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if f.Number == 11 {
+				g.inputs = append(g.inputs, name)
+			} else {
+				g.outputs = append(g.outputs, name)
+			}
+		}
+	}
+	return &g, nil
+}
+
+// valueInfoName returns the name of a ValueInfoProto.
+func valueInfoName(b []byte) (string, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range fields {
+		if f.Number == 1 {
+			return f.String(), nil
+		}
+	}
+	return "", nil
+}
+
+// decodeNode decodes a NodeProto.
+func decodeNode(b []byte) (node, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return node{}, err
+	}
+	n := node{attrs: map[string]attribute{}}
+	for _, f := range fields {
+		switch f.Number {
+		case 1:
+			n.inputs = append(n.inputs, f.String())
+		case 2:
+			n.outputs = append(n.outputs, f.String())
+		case 3:
+			n.name = f.String()
+		case 4:
+			n.op = f.String()
+		case 5:
+			name, a, err := decodeAttribute(f.Bytes)
+			if err != nil {
+				return node{}, err
+			}
+			n.attrs[name] = a
+		case 7:
+			n.domain = f.String()
+		}
+	}
+	return n, nil
+}
+
+// decodeAttribute decodes the name and scalar value of an AttributeProto.
+func decodeAttribute(b []byte) (string, attribute, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return "", attribute{}, err
+	}
+	var name string
+	var a attribute
+	for _, f := range fields {
+		switch f.Number {
+		case 1:
+			name = f.String()
+		case 2:
+			a.f = float64(f.Float32())
+		case 3:
+			a.i = f.Int()
+		}
+	}
+	return name, a, nil
+}
+
+// decodeTensor decodes the name and values of a float or double TensorProto.
+func decodeTensor(b []byte) (string, tensor, error) {
+	fields, err := protowire.Fields(b)
+	if err != nil {
+		return "", tensor{}, err
+	}
+	var (
+		name     string
+		t        tensor
+		dataType int64
+		raw      []byte
+		dims     []int64
+		external bool
+	)
+	for _, f := range fields {
+		switch f.Number {
+		case 1:
+			if dims, err = protowire.Varints(dims, f); err != nil {
+				return name, t, err
+			}
+		case 2:
+			dataType = f.Int()
+		case 4:
+			if t.data, err = protowire.Floats(t.data, f); err != nil {
+				return name, t, err
+			}
+		case 8:
+			name = f.String()
+		case 9:
+			raw = f.Bytes
+		case 10:
+			if t.data, err = protowire.Doubles(t.data, f); err != nil {
+				return name, t, err
+			}
+		case 14:
+			external = f.Int() == 1
+		}
+	}
+	if external {
+		return name, t, fmt.Errorf("externally stored data is not supported")
+	}
+
+	size := 1
+	for _, d := range dims {
+		t.dims = append(t.dims, int(d))
+		size *= int(d)
+	}
+	if raw != nil {
+		switch dataType {
+		case typeFloat:
+			for ; len(raw) >= 4; raw = raw[4:] {
+				t.data = append(t.data, float64(math.Float32frombits(binary.LittleEndian.Uint32(raw))))
+			}
+		case typeDouble:
+			for ; len(raw) >= 8; raw = raw[8:] {
+				t.data = append(t.data, math.Float64frombits(binary.LittleEndian.Uint64(raw)))
+			}
+		}
+	}
+	if dataType != typeFloat && dataType != typeDouble {
+		return name, t, fmt.Errorf("data type %d is not float or double", dataType)
+	}
+	if len(t.data) != size {
+		return name, t, fmt.Errorf("%d values for shape %v", len(t.data), t.dims)
+	}
+	return name, t, nil
+}
@@ -0,0 +1,210 @@
+package optim
+
+import (
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Adam keeps exponential moving averages of each parameter's gradient (first
+// moment) and squared gradient (second moment) and scales every step by their
+// bias-corrected ratio, giving each parameter its own effective learning rate.
+//
+// A non-zero WeightDecay turns it into AdamW: parameters are shrunk directly,
+// p -= LR * WeightDecay * p, separately from the adaptive gradient step, so the
+// decay is not distorted by the second-moment scaling as an L2 loss term would be.
+// Parameters passed to ExcludeFromDecay (e.g. engine.NoDecayParameters(model))
+// are not decayed.
+//
+// Variant selects a modified update rule sharing the same state (see AdamVariant).
+// AMSGrad divides by the largest second moment seen so far instead of the
+// current one, so the effective step size never grows; this fixes the cases in
+// which plain Adam provably fails to converge. It has no effect on Adamax,
+// whose infinity norm is already non-decreasing in the same sense.
+type Adam struct {
+	Params      []*engine.Value
+	Variant     AdamVariant
+	LR          float64
+	Beta1       float64 // Decay rate of the first moment estimate
+	Beta2       float64 // Decay rate of the second moment estimate
+	Eps         float64 // Added to the denominator for numerical stability
+	WeightDecay float64 // Decoupled weight decay coefficient (AdamW); 0 disables it
+	AMSGrad     bool    // Normalize by the running maximum of the second moment
+
+	noDecay map[*engine.Value]bool
+	t       int       // Number of steps taken
+	m, v    []float64 // First and second moment estimates (v is the infinity norm for Adamax)
+	vMax    []float64 // Running maximum of v (AMSGrad only)
+}
+
+// AdamVariant selects the update rule of an Adam optimizer.
+type AdamVariant int
+
+const (
+	// AdamStandard is the original Adam update.
+	AdamStandard AdamVariant = iota
+	// Adamax replaces the second moment by an exponentially weighted infinity
+	// norm, u = max(Beta2 * u, |g|), which is less sensitive to rare large gradients.
+	Adamax
+	// Nadam applies Nesterov momentum to the first moment, looking one step ahead
+	// along the momentum direction.
+	Nadam
+	// RAdam (rectified Adam) rescales the adaptive step by a rectification term
+	// that accounts for the high variance of the second moment estimate early in
+	// training, falling back to plain momentum SGD until the estimate is reliable.
+	// This removes the need for a manual learning-rate warmup.
+	RAdam
+)
+
+// NewAdam creates an Adam optimizer for params with learning rate lr and the
+// usual defaults beta1 = 0.9, beta2 = 0.999, eps = 1e-8.
+func NewAdam(params []*engine.Value, lr float64) *Adam {
+	return &Adam{
+		Params: params,
+		LR:     lr,
+		Beta1:  0.9,
+		Beta2:  0.999,
+		Eps:    1e-8,
+		m:      make([]float64, len(params)),
+		v:      make([]float64, len(params)),
+	}
+}
+
+// NewAdamW creates an Adam optimizer with decoupled weight decay of every
+// parameter; NewAdamWFor exempts biases and normalization scales.
+func NewAdamW(params []*engine.Value, lr, weightDecay float64) *Adam {
+	opt := NewAdam(params, lr)
+	opt.WeightDecay = weightDecay
+	return opt
+}
+
+// NewAdamWFor creates an AdamW optimizer for the parameters of m that does not
+// decay those returned by engine.NoDecayParameters, such as biases.
+func NewAdamWFor(m engine.Module, lr, weightDecay float64) *Adam {
+	opt := NewAdamW(m.Parameters(), lr, weightDecay)
+	opt.ExcludeFromDecay(engine.NoDecayParameters(m)...)
+	return opt
+}
+
+// NewAdamax creates an Adam optimizer using the Adamax update (a learning rate
+// around 0.002 is typical).
+func NewAdamax(params []*engine.Value, lr float64) *Adam {
+	opt := NewAdam(params, lr)
+	opt.Variant = Adamax
+	return opt
+}
+
+// NewNadam creates an Adam optimizer using the Nadam (Nesterov-accelerated) update.
+func NewNadam(params []*engine.Value, lr float64) *Adam {
+	opt := NewAdam(params, lr)
+	opt.Variant = Nadam
+	return opt
+}
+
+// NewRAdam creates an Adam optimizer using the rectified (RAdam) update.
+func NewRAdam(params []*engine.Value, lr float64) *Adam {
+	opt := NewAdam(params, lr)
+	opt.Variant = RAdam
+	return opt
+}
+
+// rectification returns the RAdam variance rectification factor for the
+// current step, or 0 while the approximated length of the second moment's
+// moving average (rho) is too short for the adaptive step to be trusted.
+func (opt *Adam) rectification() float64 {
+	rhoInf := 2/(1-opt.Beta2) - 1
+	b2t := math.Pow(opt.Beta2, float64(opt.t))
+	rho := rhoInf - 2*float64(opt.t)*b2t/(1-b2t)
+	if rho <= 5 {
+		return 0
+	}
+	return math.Sqrt((rho - 4) * (rho - 2) * rhoInf / ((rhoInf - 4) * (rhoInf - 2) * rho))
+}
+
+// ExcludeFromDecay exempts the given parameters from weight decay.
+func (opt *Adam) ExcludeFromDecay(params ...*engine.Value) {
+	if opt.noDecay == nil {
+		opt.noDecay = map[*engine.Value]bool{}
+	}
+	for _, p := range params {
+		opt.noDecay[p] = true
+	}
+}
+
+// Step updates the moment estimates and moves every parameter.
+func (opt *Adam) Step() {
+	opt.t++
+	bc1 := 1 - math.Pow(opt.Beta1, float64(opt.t)) // Bias corrections for the
+	bc2 := 1 - math.Pow(opt.Beta2, float64(opt.t)) // zero-initialized moments
+	var rect float64
+	if opt.Variant == RAdam {
+		rect = opt.rectification()
+	}
+
+	for i, p := range opt.Params {
+		if opt.WeightDecay != 0 && !opt.noDecay[p] {
+			p.Data -= opt.LR * opt.WeightDecay * p.Data
+		}
+
+		opt.m[i] = opt.Beta1*opt.m[i] + (1-opt.Beta1)*p.Grad
+		mHat := opt.m[i] / bc1
+
+		switch opt.Variant {
+		case Adamax:
+			opt.v[i] = math.Max(opt.Beta2*opt.v[i], math.Abs(p.Grad))
+			p.Data -= opt.LR * mHat / (opt.v[i] + opt.Eps)
+		case RAdam:
+			v := opt.secondMoment(i, p.Grad)
+			if rect == 0 {
+				p.Data -= opt.LR * mHat // Un-adapted momentum step during the early steps
+			} else {
+				p.Data -= opt.LR * rect * mHat / (math.Sqrt(v/bc2) + opt.Eps)
+			}
+		case Nadam:
+			lookahead := opt.Beta1*mHat + (1-opt.Beta1)*p.Grad/bc1 // Nesterov look-ahead
+			p.Data -= opt.LR * lookahead / (math.Sqrt(opt.secondMoment(i, p.Grad)/bc2) + opt.Eps)
+		default:
+			p.Data -= opt.LR * mHat / (math.Sqrt(opt.secondMoment(i, p.Grad)/bc2) + opt.Eps)
+		}
+	}
+}
+
+// secondMoment updates the second moment estimate of parameter i with its
+// gradient and returns the (uncorrected) value to normalize by: the estimate
+// itself, or its running maximum with AMSGrad.
+func (opt *Adam) secondMoment(i int, grad float64) float64 {
+	opt.v[i] = opt.Beta2*opt.v[i] + (1-opt.Beta2)*grad*grad
+	if !opt.AMSGrad {
+		return opt.v[i]
+	}
+	if opt.vMax == nil {
+		opt.vMax = make([]float64, len(opt.Params))
+	}
+	opt.vMax[i] = math.Max(opt.vMax[i], opt.v[i])
+	return opt.vMax[i]
+}
+
+// ZeroGrad resets the gradients of all parameters.
+func (opt *Adam) ZeroGrad() {
+	zeroGrad(opt.Params)
+}
+
+// Parameters returns the parameters the optimizer updates.
+func (opt *Adam) Parameters() []*engine.Value {
+	return opt.Params
+}
+
+// LearningRate returns the current learning rate.
+func (opt *Adam) LearningRate() float64 {
+	return opt.LR
+}
+
+// SetLearningRate changes the learning rate.
+func (opt *Adam) SetLearningRate(lr float64) {
+	opt.LR = lr
+}
+
+// SetMomentum changes the momentum (Beta1).
+func (opt *Adam) SetMomentum(momentum float64) {
+	opt.Beta1 = momentum
+}
@@ -0,0 +1,101 @@
+package optim
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// GradCentralization wraps an inner optimizer and centralizes gradients before
+// every step: within each weight vector (the incoming weights of one neuron or
+// output channel) the mean gradient is subtracted, so the update has zero mean.
+// This constrains the weights to a hyperplane and often speeds up convergence
+// of dense and convolutional layers. Biases and other vectors are left alone.
+type GradCentralization struct {
+	Inner   Optimizer
+	Vectors [][]*engine.Value // Groups of parameters centralized together
+}
+
+// NewGradCentralization wraps inner, which must optimize the parameters of m,
+// centralizing the gradient of every weight vector of m.
+func NewGradCentralization(inner Optimizer, m engine.Module) *GradCentralization {
+	return &GradCentralization{
+		Inner:   inner,
+		Vectors: WeightVectors(m),
+	}
+}
+
+// WeightVectors splits every multi-dimensional parameter tensor of m (see
+// engine.NamedParameters) into its rows along the first (output) dimension.
+func WeightVectors(m engine.Module) [][]*engine.Value {
+	var vectors [][]*engine.Value
+	for _, np := range engine.NamedParameters(m) {
+		if len(np.Shape) < 2 || np.Shape[0] == 0 {
+			continue // Biases and scales have no fan-in to centralize over
+		}
+		size := len(np.Values) / np.Shape[0]
+		if size < 2 {
+			continue
+		}
+		for start := 0; start < len(np.Values); start += size {
+			vectors = append(vectors, np.Values[start:start+size])
+		}
+	}
+	return vectors
+}
+
+// CentralizeGradients subtracts the mean gradient of every vector from its entries.
+func CentralizeGradients(vectors [][]*engine.Value) {
+	for _, vec := range vectors {
+		mean := 0.0
+		for _, p := range vec {
+			mean += p.Grad
+		}
+		mean /= float64(len(vec))
+		for _, p := range vec {
+			p.Grad -= mean
+		}
+	}
+}
+
+// Step centralizes the gradients and takes an inner step.
+func (gc *GradCentralization) Step() {
+	CentralizeGradients(gc.Vectors)
+	gc.Inner.Step()
+}
+
+// ZeroGrad resets the gradients through the inner optimizer.
+func (gc *GradCentralization) ZeroGrad() {
+	gc.Inner.ZeroGrad()
+}
+
+// LearningRate returns the learning rate of the inner optimizer.
+func (gc *GradCentralization) LearningRate() float64 {
+	return gc.Inner.LearningRate()
+}
+
+// SetLearningRate changes the learning rate of the inner optimizer.
+func (gc *GradCentralization) SetLearningRate(lr float64) {
+	gc.Inner.SetLearningRate(lr)
+}
+
+// SetMomentum changes the momentum of the inner optimizer, if it has one.
+func (gc *GradCentralization) SetMomentum(momentum float64) {
+	if ms, ok := gc.Inner.(MomentumSetter); ok {
+		ms.SetMomentum(momentum)
+	}
+}
+
+// State returns the state of the inner optimizer; centralization itself is stateless.
+func (gc *GradCentralization) State() (State, error) {
+	return innerState(gc.Inner)
+}
+
+// LoadState restores the state of the inner optimizer.
+func (gc *GradCentralization) LoadState(s State) error {
+	st, ok := gc.Inner.(Stateful)
+	if !ok {
+		return fmt.Errorf("optim: inner optimizer %T does not support loading its state", gc.Inner)
+	}
+	return st.LoadState(s)
+}
@@ -0,0 +1,105 @@
+package optim
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// EMA keeps an exponential moving average ("shadow" copy) of a set of
+// parameters, shadow = Decay * shadow + (1 - Decay) * p, updated after every
+// optimizer step. The averaged weights usually generalize better than the raw
+// ones; swap them in with Apply for evaluation or export and go back to the
+// training weights with Restore. Training itself is unaffected.
+type EMA struct {
+	Params []*engine.Value
+	Decay  float64 // Typically 0.99 - 0.9999
+
+	shadow  []float64
+	backup  []float64 // Training weights while the shadow weights are applied
+	updates int
+}
+
+// NewEMA starts tracking params, initializing the average at their current values.
+func NewEMA(params []*engine.Value, decay float64) *EMA {
+	ema := EMA{
+		Params: params,
+		Decay:  decay,
+		shadow: make([]float64, len(params)),
+	}
+	for i, p := range params {
+		ema.shadow[i] = p.Data
+	}
+	return &ema
+}
+
+// Update folds the current parameter values into the average; call it after every Step.
+func (ema *EMA) Update() {
+	if ema.backup != nil {
+		panic("optim: EMA.Update called while the averaged weights are applied")
+	}
+	ema.updates++
+	for i, p := range ema.Params {
+		ema.shadow[i] = ema.Decay*ema.shadow[i] + (1-ema.Decay)*p.Data
+	}
+}
+
+// Apply replaces the parameters by their averages, remembering the training
+// weights so Restore can bring them back.
+func (ema *EMA) Apply() {
+	if ema.backup != nil {
+		return // Already applied
+	}
+	ema.backup = make([]float64, len(ema.Params))
+	for i, p := range ema.Params {
+		ema.backup[i] = p.Data
+		p.Data = ema.shadow[i]
+	}
+}
+
+// Restore puts the training weights back after Apply.
+func (ema *EMA) Restore() {
+	if ema.backup == nil {
+		return
+	}
+	for i, p := range ema.Params {
+		p.Data = ema.backup[i]
+	}
+	ema.backup = nil
+}
+
+// CopyTo writes the averaged weights into params, e.g. the parameters of a
+// clone of the model made for export. params must match Params position by position.
+func (ema *EMA) CopyTo(params []*engine.Value) {
+	if len(params) != len(ema.shadow) {
+		panic(fmt.Sprintf("optim: EMA.CopyTo got %d parameters, tracking %d", len(params), len(ema.shadow)))
+	}
+	for i, p := range params {
+		p.Data = ema.shadow[i]
+	}
+}
+
+// State returns the averaged weights and the number of updates.
+func (ema *EMA) State() (State, error) {
+	return State{
+		Kind:    "ema",
+		Step:    ema.updates,
+		Buffers: buffers(map[string][]float64{"shadow": ema.shadow}),
+	}, nil
+}
+
+// LoadState restores a state returned by State.
+func (ema *EMA) LoadState(s State) error {
+	if err := checkKind(s, "ema"); err != nil {
+		return err
+	}
+	shadow, err := buffer(s, "shadow", len(ema.Params))
+	if err != nil {
+		return err
+	}
+	if shadow == nil {
+		return fmt.Errorf("optim: ema state is missing the averaged weights")
+	}
+	ema.shadow, ema.updates = shadow, s.Step
+	return nil
+}
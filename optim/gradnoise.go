@@ -0,0 +1,119 @@
+package optim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// GradNoise wraps an inner optimizer and adds annealed Gaussian noise to every
+// gradient before each step. The noise variance decays as
+// Eta / (1 + t)^Gamma, so early training explores more and late training
+// converges normally; this helps very small or deep networks escape poor
+// regions of the loss surface.
+type GradNoise struct {
+	Inner  Optimizer
+	Params []*engine.Value // The parameters optimized by Inner
+	Eta    float64         // Initial noise variance (0.01 - 1 are typical)
+	Gamma  float64         // Annealing exponent
+	Rand   *rand.Rand      // Source of the noise; nil uses the global math/rand source
+
+	t int // Number of steps taken
+}
+
+// NewGradNoise wraps inner, which must optimize params, with noise variance
+// eta and the recommended annealing exponent gamma = 0.55.
+func NewGradNoise(inner Optimizer, params []*engine.Value, eta float64) *GradNoise {
+	return &GradNoise{
+		Inner:  inner,
+		Params: params,
+		Eta:    eta,
+		Gamma:  0.55,
+	}
+}
+
+// Stddev returns the standard deviation of the noise added at the next step.
+func (gn *GradNoise) Stddev() float64 {
+	return math.Sqrt(gn.Eta / math.Pow(1+float64(gn.t), gn.Gamma))
+}
+
+// Step perturbs the gradients and takes an inner step.
+func (gn *GradNoise) Step() {
+	stddev := gn.Stddev()
+	for _, p := range gn.Params {
+		p.Grad += gn.normal() * stddev
+	}
+	gn.t++
+	gn.Inner.Step()
+}
+
+// normal draws a standard normal value from Rand or the global source.
+func (gn *GradNoise) normal() float64 {
+	if gn.Rand == nil {
+		return rand.NormFloat64()
+	}
+	return gn.Rand.NormFloat64()
+}
+
+// SetRand makes the noise be drawn from r, implementing engine.RandSetter so
+// that Trainer.SetSeed makes it reproducible.
+func (gn *GradNoise) SetRand(r *rand.Rand) {
+	gn.Rand = r
+}
+
+// ZeroGrad resets the gradients through the inner optimizer.
+func (gn *GradNoise) ZeroGrad() {
+	gn.Inner.ZeroGrad()
+}
+
+// Parameters returns the parameters the optimizer updates.
+func (gn *GradNoise) Parameters() []*engine.Value {
+	return gn.Params
+}
+
+// LearningRate returns the learning rate of the inner optimizer.
+func (gn *GradNoise) LearningRate() float64 {
+	return gn.Inner.LearningRate()
+}
+
+// SetLearningRate changes the learning rate of the inner optimizer.
+func (gn *GradNoise) SetLearningRate(lr float64) {
+	gn.Inner.SetLearningRate(lr)
+}
+
+// SetMomentum changes the momentum of the inner optimizer, if it has one.
+func (gn *GradNoise) SetMomentum(momentum float64) {
+	if ms, ok := gn.Inner.(MomentumSetter); ok {
+		ms.SetMomentum(momentum)
+	}
+}
+
+// State returns the annealing step count along with the inner state.
+func (gn *GradNoise) State() (State, error) {
+	inner, err := innerState(gn.Inner)
+	if err != nil {
+		return State{}, err
+	}
+	return State{Kind: "gradnoise", LR: gn.LearningRate(), Step: gn.t, Groups: []State{inner}}, nil
+}
+
+// LoadState restores a state returned by State.
+func (gn *GradNoise) LoadState(s State) error {
+	if err := checkKind(s, "gradnoise"); err != nil {
+		return err
+	}
+	st, ok := gn.Inner.(Stateful)
+	if !ok {
+		return fmt.Errorf("optim: inner optimizer %T does not support loading its state", gn.Inner)
+	}
+	if len(s.Groups) != 1 {
+		return fmt.Errorf("optim: gradnoise state is missing the inner state")
+	}
+	if err := st.LoadState(s.Groups[0]); err != nil {
+		return fmt.Errorf("inner: %w", err)
+	}
+	gn.t = s.Step
+	return nil
+}
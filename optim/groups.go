@@ -0,0 +1,102 @@
+package optim
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// ParamGroup is a set of parameters optimized with its own hyperparameters,
+// e.g. a pretrained backbone with a small learning rate and a fresh head with
+// a large one. Select the parameters by name with engine.ParametersWithPrefix.
+type ParamGroup struct {
+	Params      []*engine.Value
+	LR          float64 // Learning rate of the group; 0 uses the optimizer's base rate
+	WeightDecay float64
+}
+
+// Grouped optimizes several parameter groups, each with its own instance of an
+// underlying optimizer built by a factory function. Its learning rate is the
+// base rate: SetLearningRate rescales every group proportionally, so
+// schedulers keep the ratios between the groups.
+type Grouped struct {
+	Groups     []ParamGroup
+	Optimizers []Optimizer // One optimizer per group
+
+	baseLR float64
+	ratios []float64 // Group learning rate relative to the base rate
+}
+
+// NewGrouped creates one optimizer per group with newOpt, passing the group's
+// parameters, learning rate and weight decay, e.g.
+//
+//	optim.NewGrouped(1e-3, groups, func(p []*engine.Value, lr, wd float64) optim.Optimizer {
+//		return optim.NewAdamW(p, lr, wd)
+//	})
+//
+// It panics if lr is not positive, as the group rates are kept relative to it.
+func NewGrouped(lr float64, groups []ParamGroup, newOpt func(params []*engine.Value, lr, weightDecay float64) Optimizer) *Grouped {
+	if lr <= 0 {
+		panic(fmt.Sprintf("optim: Grouped base learning rate must be positive, got %v", lr))
+	}
+	g := Grouped{
+		Groups:     groups,
+		Optimizers: make([]Optimizer, len(groups)),
+		baseLR:     lr,
+		ratios:     make([]float64, len(groups)),
+	}
+	for i, group := range groups {
+		groupLR := group.LR
+		if groupLR == 0 {
+			groupLR = lr
+		}
+		g.ratios[i] = groupLR / lr
+		g.Optimizers[i] = newOpt(group.Params, groupLR, group.WeightDecay)
+	}
+	return &g
+}
+
+// Step steps every group's optimizer.
+func (g *Grouped) Step() {
+	for _, opt := range g.Optimizers {
+		opt.Step()
+	}
+}
+
+// ZeroGrad resets the gradients of every group.
+func (g *Grouped) ZeroGrad() {
+	for _, opt := range g.Optimizers {
+		opt.ZeroGrad()
+	}
+}
+
+// Parameters returns the parameters of every group, in order.
+func (g *Grouped) Parameters() []*engine.Value {
+	var params []*engine.Value
+	for _, group := range g.Groups {
+		params = append(params, group.Params...)
+	}
+	return params
+}
+
+// LearningRate returns the base learning rate.
+func (g *Grouped) LearningRate() float64 {
+	return g.baseLR
+}
+
+// SetLearningRate changes the base learning rate and scales every group's rate with it.
+func (g *Grouped) SetLearningRate(lr float64) {
+	g.baseLR = lr
+	for i, opt := range g.Optimizers {
+		opt.SetLearningRate(lr * g.ratios[i])
+	}
+}
+
+// SetMomentum changes the momentum of every group whose optimizer supports it.
+func (g *Grouped) SetMomentum(momentum float64) {
+	for _, opt := range g.Optimizers {
+		if ms, ok := opt.(MomentumSetter); ok {
+			ms.SetMomentum(momentum)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package optim
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Lookahead wraps an inner optimizer (the "fast" weights) and keeps a second,
+// "slow" copy of the parameters. Every K inner steps the slow weights move a
+// fraction Alpha towards the fast weights, p_slow += Alpha * (p_fast - p_slow),
+// and the fast weights are reset to them. This smooths out the noisy
+// trajectory of the inner optimizer at little extra cost.
+type Lookahead struct {
+	Inner  Optimizer
+	Params []*engine.Value // The parameters optimized by Inner
+	K      int             // Number of inner steps between synchronizations
+	Alpha  float64         // Slow weights step size
+
+	slow  []float64
+	steps int // Inner steps since the last synchronization
+}
+
+// NewLookahead wraps inner, which must optimize params, with the usual
+// defaults of k = 5 and alpha = 0.5.
+func NewLookahead(inner Optimizer, params []*engine.Value) *Lookahead {
+	la := Lookahead{
+		Inner:  inner,
+		Params: params,
+		K:      5,
+		Alpha:  0.5,
+		slow:   make([]float64, len(params)),
+	}
+	for i, p := range params {
+		la.slow[i] = p.Data
+	}
+	return &la
+}
+
+// Step takes one inner step and synchronizes the slow and fast weights every K steps.
+func (la *Lookahead) Step() {
+	la.Inner.Step()
+	la.steps++
+	if la.steps < la.K {
+		return
+	}
+
+	la.steps = 0
+	for i, p := range la.Params {
+		la.slow[i] += la.Alpha * (p.Data - la.slow[i])
+		p.Data = la.slow[i]
+	}
+}
+
+// ZeroGrad resets the gradients through the inner optimizer.
+func (la *Lookahead) ZeroGrad() {
+	la.Inner.ZeroGrad()
+}
+
+// Parameters returns the parameters the optimizer updates.
+func (la *Lookahead) Parameters() []*engine.Value {
+	return la.Params
+}
+
+// LearningRate returns the learning rate of the inner optimizer.
+func (la *Lookahead) LearningRate() float64 {
+	return la.Inner.LearningRate()
+}
+
+// SetLearningRate changes the learning rate of the inner optimizer.
+func (la *Lookahead) SetLearningRate(lr float64) {
+	la.Inner.SetLearningRate(lr)
+}
+
+// SetMomentum changes the momentum of the inner optimizer, if it has one.
+func (la *Lookahead) SetMomentum(momentum float64) {
+	if ms, ok := la.Inner.(MomentumSetter); ok {
+		ms.SetMomentum(momentum)
+	}
+}
+
+// State returns the slow weights and step counter along with the inner state.
+func (la *Lookahead) State() (State, error) {
+	inner, err := innerState(la.Inner)
+	if err != nil {
+		return State{}, err
+	}
+	return State{
+		Kind:    "lookahead",
+		LR:      la.LearningRate(),
+		Step:    la.steps,
+		Buffers: buffers(map[string][]float64{"slow": la.slow}),
+		Groups:  []State{inner},
+	}, nil
+}
+
+// LoadState restores a state returned by State.
+func (la *Lookahead) LoadState(s State) error {
+	if err := checkKind(s, "lookahead"); err != nil {
+		return err
+	}
+	st, ok := la.Inner.(Stateful)
+	if !ok {
+		return fmt.Errorf("optim: inner optimizer %T does not support loading its state", la.Inner)
+	}
+	if len(s.Groups) != 1 {
+		return fmt.Errorf("optim: lookahead state is missing the inner state")
+	}
+	slow, err := buffer(s, "slow", len(la.Params))
+	if err != nil {
+		return err
+	}
+	if slow == nil {
+		return fmt.Errorf("optim: lookahead state is missing the slow weights")
+	}
+	if err := st.LoadState(s.Groups[0]); err != nil {
+		return fmt.Errorf("inner: %w", err)
+	}
+	la.slow, la.steps = slow, s.Step
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package optim provides optimizers that update the trainable parameters of
+// engine modules from the gradients computed by FullBackward.
+package optim
+
+import "github.com/Rmehta-sudo/neural-net/engine"
+
+// Optimizer updates a fixed set of parameters from their gradients.
+// A training step is: compute the loss, call FullBackward on it, call Step,
+// then ZeroGrad before the next iteration. The learning rate is exposed so
+// that schedulers can adjust it during training. Optimizers that must
+// re-evaluate the loss within a step also implement ClosureStepper; StepWith
+// drives both kinds with the same closure.
+type Optimizer interface {
+	Step()
+	ZeroGrad()
+	LearningRate() float64
+	SetLearningRate(lr float64)
+}
+
+// Parameterized is implemented by optimizers that report the parameters they
+// update, e.g. for the trainer to accumulate gradients over all of them,
+// including those outside the model such as the log-variances of
+// losses.UncertaintyWeighting.
+type Parameterized interface {
+	Parameters() []*engine.Value
+}
+
+// ParametersOf returns the parameters updated by opt and whether opt reports
+// them. A GradCentralization reports those of its inner optimizer.
+func ParametersOf(opt Optimizer) ([]*engine.Value, bool) {
+	switch opt := opt.(type) {
+	case Parameterized:
+		return opt.Parameters(), true
+	case *GradCentralization:
+		return ParametersOf(opt.Inner)
+	}
+	return nil, false
+}
+
+// zeroGrad sets the gradient of every parameter to zero.
+func zeroGrad(params []*engine.Value) {
+	for _, p := range params {
+		p.Grad = 0
+	}
+}
+
+// SGD is (stochastic) gradient descent, optionally with momentum.
+// Without momentum each step is p -= LR * p.Grad. With momentum a velocity
+// buffer accumulates past gradients, v = Momentum * v + (1 - Dampening) * p.Grad,
+// and the step is p -= LR * v (or p -= LR * (p.Grad + Momentum * v) with Nesterov),
+// which speeds up progress along consistent directions and damps oscillations.
+// For SGD, coupled weight decay is equivalent to decoupled decay, so it is
+// simply added to the gradient.
+type SGD struct {
+	Params      []*engine.Value
+	LR          float64
+	Momentum    float64 // Velocity decay factor (beta); 0 disables momentum
+	Dampening   float64 // Fraction of each new gradient withheld from the velocity
+	Nesterov    bool    // Use Nesterov accelerated gradient
+	WeightDecay float64 // L2 penalty: WeightDecay * p is added to each gradient
+
+	velocity []float64
+}
+
+// NewSGD creates a plain SGD optimizer for params with learning rate lr.
+// Pass module.Parameters() to optimize a whole Module.
+func NewSGD(params []*engine.Value, lr float64) *SGD {
+	return &SGD{
+		Params: params,
+		LR:     lr,
+	}
+}
+
+// NewSGDMomentum creates an SGD optimizer with the given momentum (typically 0.9).
+func NewSGDMomentum(params []*engine.Value, lr, momentum float64) *SGD {
+	opt := NewSGD(params, lr)
+	opt.Momentum = momentum
+	return opt
+}
+
+// grad returns the gradient of p including the weight decay term.
+func (opt *SGD) grad(p *engine.Value) float64 {
+	return p.Grad + opt.WeightDecay*p.Data
+}
+
+// Step moves every parameter against its (momentum-smoothed) gradient.
+func (opt *SGD) Step() {
+	if opt.Momentum == 0 {
+		for _, p := range opt.Params {
+			p.Data -= opt.LR * opt.grad(p)
+		}
+		return
+	}
+
+	first := opt.velocity == nil
+	if first {
+		opt.velocity = make([]float64, len(opt.Params))
+	}
+	for i, p := range opt.Params {
+		grad := opt.grad(p)
+		if first {
+			opt.velocity[i] = grad // The first step starts the velocity at the gradient
+		} else {
+			opt.velocity[i] = opt.Momentum*opt.velocity[i] + (1-opt.Dampening)*grad
+		}
+
+		update := opt.velocity[i]
+		if opt.Nesterov {
+			update = grad + opt.Momentum*opt.velocity[i]
+		}
+		p.Data -= opt.LR * update
+	}
+}
+
+// ZeroGrad resets the gradients of all parameters.
+func (opt *SGD) ZeroGrad() {
+	zeroGrad(opt.Params)
+}
+
+// Parameters returns the parameters the optimizer updates.
+func (opt *SGD) Parameters() []*engine.Value {
+	return opt.Params
+}
+
+// LearningRate returns the current learning rate.
+func (opt *SGD) LearningRate() float64 {
+	return opt.LR
+}
+
+// SetLearningRate changes the learning rate.
+func (opt *SGD) SetLearningRate(lr float64) {
+	opt.LR = lr
+}
+
+// SetMomentum changes the momentum (Momentum).
+func (opt *SGD) SetMomentum(momentum float64) {
+	opt.Momentum = momentum
+}
@@ -0,0 +1,84 @@
+package optim
+
+import (
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// RMSProp divides each gradient by a moving average of its recent magnitude,
+// sq = Alpha * sq + (1 - Alpha) * p.Grad^2, and steps p -= LR * p.Grad / (sqrt(sq) + Eps).
+// With Momentum the scaled steps are additionally accumulated in a velocity
+// buffer; with Centered the squared average is replaced by an estimate of the
+// gradient variance, which can stabilize training further.
+type RMSProp struct {
+	Params   []*engine.Value
+	LR       float64
+	Alpha    float64 // Decay rate of the squared-gradient average
+	Eps      float64 // Added to the denominator for numerical stability
+	Momentum float64 // 0 disables momentum
+	Centered bool    // Normalize by the estimated variance instead of the raw second moment
+
+	sq       []float64 // Moving average of squared gradients
+	mean     []float64 // Moving average of gradients (centered only)
+	velocity []float64
+}
+
+// NewRMSProp creates an RMSProp optimizer for params with learning rate lr and
+// the usual defaults alpha = 0.99, eps = 1e-8.
+func NewRMSProp(params []*engine.Value, lr float64) *RMSProp {
+	return &RMSProp{
+		Params:   params,
+		LR:       lr,
+		Alpha:    0.99,
+		Eps:      1e-8,
+		sq:       make([]float64, len(params)),
+		mean:     make([]float64, len(params)),
+		velocity: make([]float64, len(params)),
+	}
+}
+
+// Step updates the moving averages and moves every parameter.
+func (opt *RMSProp) Step() {
+	for i, p := range opt.Params {
+		opt.sq[i] = opt.Alpha*opt.sq[i] + (1-opt.Alpha)*p.Grad*p.Grad
+
+		avg := opt.sq[i]
+		if opt.Centered {
+			opt.mean[i] = opt.Alpha*opt.mean[i] + (1-opt.Alpha)*p.Grad
+			avg -= opt.mean[i] * opt.mean[i]
+		}
+		update := p.Grad / (math.Sqrt(avg) + opt.Eps)
+
+		if opt.Momentum != 0 {
+			opt.velocity[i] = opt.Momentum*opt.velocity[i] + update
+			update = opt.velocity[i]
+		}
+		p.Data -= opt.LR * update
+	}
+}
+
+// ZeroGrad resets the gradients of all parameters.
+func (opt *RMSProp) ZeroGrad() {
+	zeroGrad(opt.Params)
+}
+
+// Parameters returns the parameters the optimizer updates.
+func (opt *RMSProp) Parameters() []*engine.Value {
+	return opt.Params
+}
+
+// LearningRate returns the current learning rate.
+func (opt *RMSProp) LearningRate() float64 {
+	return opt.LR
+}
+
+// SetLearningRate changes the learning rate.
+func (opt *RMSProp) SetLearningRate(lr float64) {
+	opt.LR = lr
+}
+
+// SetMomentum changes the momentum (Momentum).
+func (opt *RMSProp) SetMomentum(momentum float64) {
+	opt.Momentum = momentum
+}
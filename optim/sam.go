@@ -0,0 +1,136 @@
+package optim
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Closure recomputes the loss at the current parameter values, calls
+// FullBackward on it so the gradients are up to date, and returns the loss.
+// Optimizers that need to evaluate the loss more than once per step take one.
+type Closure func() float64
+
+// ClosureStepper is implemented by optimizers whose step needs to re-evaluate
+// the loss, such as SAM.
+type ClosureStepper interface {
+	StepClosure(closure Closure) float64
+}
+
+// StepWith takes one step of opt with the given closure and returns the loss
+// before the step. Optimizers that are not ClosureSteppers simply evaluate the
+// closure once and Step, so training loops can use StepWith for every optimizer.
+func StepWith(opt Optimizer, closure Closure) float64 {
+	if cs, ok := opt.(ClosureStepper); ok {
+		return cs.StepClosure(closure)
+	}
+	loss := closure()
+	opt.Step()
+	return loss
+}
+
+// SAM (sharpness-aware minimization) wraps an inner optimizer and seeks
+// parameters whose whole neighbourhood has a low loss. Each step first moves
+// the weights uphill to the worst point within radius Rho, e = Rho * g / ||g||,
+// computes the gradient there, then returns to the original weights and lets
+// the inner optimizer step with that gradient. With Adaptive the perturbation
+// is scaled per parameter by |p| (ASAM), making it invariant to weight scale.
+// It costs two forward and backward passes per step, so it must be driven
+// through StepClosure (or StepWith).
+type SAM struct {
+	Inner    Optimizer
+	Params   []*engine.Value // The parameters optimized by Inner
+	Rho      float64         // Neighbourhood radius
+	Adaptive bool
+}
+
+// NewSAM wraps inner, which must optimize params, with neighbourhood radius rho
+// (0.05 is the usual default; use around 0.5 or more with Adaptive).
+func NewSAM(inner Optimizer, params []*engine.Value, rho float64) *SAM {
+	return &SAM{
+		Inner:  inner,
+		Params: params,
+		Rho:    rho,
+	}
+}
+
+// StepClosure evaluates the loss and gradients, perturbs the weights to the
+// sharpest nearby point, re-evaluates the gradients there and takes the inner
+// step from the original weights. It returns the loss at the original weights.
+func (sam *SAM) StepClosure(closure Closure) float64 {
+	loss := closure()
+
+	scale := func(p *engine.Value) float64 {
+		if sam.Adaptive {
+			return math.Abs(p.Data)
+		}
+		return 1
+	}
+	norm := 0.0
+	for _, p := range sam.Params {
+		g := scale(p) * p.Grad
+		norm += g * g
+	}
+	norm = math.Sqrt(norm) + 1e-12
+
+	e := make([]float64, len(sam.Params))
+	for i, p := range sam.Params {
+		s := scale(p)
+		e[i] = sam.Rho * s * s * p.Grad / norm
+		p.Data += e[i] // Ascend to the perturbed weights
+	}
+
+	closure() // Gradients at the perturbed weights
+	for i, p := range sam.Params {
+		p.Data -= e[i]
+	}
+	sam.Inner.Step()
+	return loss
+}
+
+// Step panics: SAM needs to re-evaluate the loss, so use StepClosure or StepWith.
+func (sam *SAM) Step() {
+	panic("optim: SAM needs a closure; use StepClosure or StepWith")
+}
+
+// ZeroGrad resets the gradients through the inner optimizer.
+func (sam *SAM) ZeroGrad() {
+	sam.Inner.ZeroGrad()
+}
+
+// Parameters returns the parameters the optimizer updates.
+func (sam *SAM) Parameters() []*engine.Value {
+	return sam.Params
+}
+
+// LearningRate returns the learning rate of the inner optimizer.
+func (sam *SAM) LearningRate() float64 {
+	return sam.Inner.LearningRate()
+}
+
+// SetLearningRate changes the learning rate of the inner optimizer.
+func (sam *SAM) SetLearningRate(lr float64) {
+	sam.Inner.SetLearningRate(lr)
+}
+
+// SetMomentum changes the momentum of the inner optimizer, if it has one.
+func (sam *SAM) SetMomentum(momentum float64) {
+	if ms, ok := sam.Inner.(MomentumSetter); ok {
+		ms.SetMomentum(momentum)
+	}
+}
+
+// State returns the state of the inner optimizer; SAM itself is stateless.
+func (sam *SAM) State() (State, error) {
+	return innerState(sam.Inner)
+}
+
+// LoadState restores the state of the inner optimizer.
+func (sam *SAM) LoadState(s State) error {
+	st, ok := sam.Inner.(Stateful)
+	if !ok {
+		return fmt.Errorf("optim: inner optimizer %T does not support loading its state", sam.Inner)
+	}
+	return st.LoadState(s)
+}
@@ -0,0 +1,386 @@
+package optim
+
+import (
+	"fmt"
+	"math"
+)
+
+// Scheduler adjusts an optimizer's learning rate as training progresses.
+// Step is called once per epoch (by the Trainer, or by hand) and applies the
+// rate for the next epoch. At reports the rate for any epoch without side
+// effects, which lets schedulers be composed (see Warmup).
+type Scheduler interface {
+	Step()
+	At(epoch int) float64
+}
+
+// StepLR multiplies the learning rate by Gamma every StepSize epochs.
+type StepLR struct {
+	Optimizer Optimizer
+	StepSize  int
+	Gamma     float64
+	BaseLR    float64 // Learning rate at epoch 0
+	Epoch     int     // Number of completed epochs
+}
+
+// NewStepLR creates a StepLR scheduler starting from opt's current learning rate.
+// It panics if stepSize is not positive.
+func NewStepLR(opt Optimizer, stepSize int, gamma float64) *StepLR {
+	if stepSize <= 0 {
+		panic(fmt.Sprintf("optim: StepLR step size must be positive, got %d", stepSize))
+	}
+	return &StepLR{
+		Optimizer: opt,
+		StepSize:  stepSize,
+		Gamma:     gamma,
+		BaseLR:    opt.LearningRate(),
+	}
+}
+
+// At returns BaseLR * Gamma^(epoch / StepSize).
+func (s *StepLR) At(epoch int) float64 {
+	return s.BaseLR * math.Pow(s.Gamma, float64(epoch/s.StepSize))
+}
+
+// Step advances one epoch and updates the optimizer's learning rate.
+func (s *StepLR) Step() {
+	s.Epoch++
+	s.Optimizer.SetLearningRate(s.At(s.Epoch))
+}
+
+// ExponentialLR multiplies the learning rate by Gamma every epoch.
+type ExponentialLR struct {
+	Optimizer Optimizer
+	Gamma     float64
+	BaseLR    float64 // Learning rate at epoch 0
+	Epoch     int     // Number of completed epochs
+}
+
+// NewExponentialLR creates an ExponentialLR scheduler starting from opt's current learning rate.
+func NewExponentialLR(opt Optimizer, gamma float64) *ExponentialLR {
+	return &ExponentialLR{
+		Optimizer: opt,
+		Gamma:     gamma,
+		BaseLR:    opt.LearningRate(),
+	}
+}
+
+// At returns BaseLR * Gamma^epoch.
+func (s *ExponentialLR) At(epoch int) float64 {
+	return s.BaseLR * math.Pow(s.Gamma, float64(epoch))
+}
+
+// Step advances one epoch and updates the optimizer's learning rate.
+func (s *ExponentialLR) Step() {
+	s.Epoch++
+	s.Optimizer.SetLearningRate(s.At(s.Epoch))
+}
+
+// CosineAnnealingLR decays the learning rate from BaseLR to MinLR along half a
+// cosine wave over TMax epochs. With warm restarts (SGDR) the rate then jumps
+// back to BaseLR and the next cycle starts, each cycle TMult times longer than
+// the previous one; without restarts it stays at MinLR after TMax epochs.
+type CosineAnnealingLR struct {
+	Optimizer Optimizer
+	TMax      int // Length of the (first) cycle in epochs
+	TMult     int // Cycle length multiplier after each restart (restarts only)
+	Restarts  bool
+	MinLR     float64
+	BaseLR    float64 // Learning rate at epoch 0
+	Epoch     int     // Number of completed epochs
+}
+
+// NewCosineAnnealingLR creates a single cosine decay over tMax epochs,
+// starting from opt's current learning rate. It panics if tMax is not positive.
+func NewCosineAnnealingLR(opt Optimizer, tMax int, minLR float64) *CosineAnnealingLR {
+	if tMax <= 0 {
+		panic(fmt.Sprintf("optim: CosineAnnealingLR cycle length must be positive, got %d", tMax))
+	}
+	return &CosineAnnealingLR{
+		Optimizer: opt,
+		TMax:      tMax,
+		TMult:     1,
+		MinLR:     minLR,
+		BaseLR:    opt.LearningRate(),
+	}
+}
+
+// NewCosineAnnealingWarmRestarts creates a cosine schedule that restarts after
+// t0 epochs, then after t0*tMult more epochs, and so on. It panics if t0 is
+// not positive or tMult is below 1.
+func NewCosineAnnealingWarmRestarts(opt Optimizer, t0, tMult int, minLR float64) *CosineAnnealingLR {
+	if tMult < 1 {
+		panic(fmt.Sprintf("optim: CosineAnnealingLR cycle multiplier must be at least 1, got %d", tMult))
+	}
+	s := NewCosineAnnealingLR(opt, t0, minLR)
+	s.TMult = tMult
+	s.Restarts = true
+	return s
+}
+
+// At returns MinLR + (BaseLR - MinLR) * (1 + cos(pi * t / T)) / 2, where t is the
+// position of epoch within its cycle and T the cycle length.
+func (s *CosineAnnealingLR) At(epoch int) float64 {
+	t, period := epoch, s.TMax
+	if s.Restarts {
+		for t >= period {
+			t -= period
+			period *= max(s.TMult, 1)
+		}
+	} else {
+		t = min(t, period)
+	}
+	return s.MinLR + (s.BaseLR-s.MinLR)*(1+math.Cos(math.Pi*float64(t)/float64(period)))/2
+}
+
+// Step advances one epoch and updates the optimizer's learning rate.
+func (s *CosineAnnealingLR) Step() {
+	s.Epoch++
+	s.Optimizer.SetLearningRate(s.At(s.Epoch))
+}
+
+// MomentumSetter is implemented by optimizers whose momentum can be scheduled:
+// SGD and RMSProp (Momentum) and Adam (Beta1).
+type MomentumSetter interface {
+	SetMomentum(momentum float64)
+}
+
+// annealCos interpolates from start to end along half a cosine wave as pct goes from 0 to 1.
+func annealCos(start, end, pct float64) float64 {
+	return end + (start-end)*(1+math.Cos(math.Pi*pct))/2
+}
+
+// OneCycleLR implements the one-cycle policy: the learning rate warms up from
+// MaxLR/DivFactor to MaxLR over the first PctStart of TotalSteps, then anneals
+// down to MaxLR/(DivFactor*FinalDivFactor) for the rest, both along cosine
+// curves. If CycleMomentum is set and the optimizer implements MomentumSetter,
+// momentum moves the opposite way, from MaxMomentum down to BaseMomentum and back.
+// Unlike the per-epoch schedulers it is meant to be stepped after every batch;
+// TotalSteps is the total number of Step calls.
+type OneCycleLR struct {
+	Optimizer      Optimizer
+	MaxLR          float64
+	TotalSteps     int
+	PctStart       float64 // Fraction of the steps spent increasing the learning rate
+	DivFactor      float64 // Initial learning rate is MaxLR / DivFactor
+	FinalDivFactor float64 // Final learning rate is the initial one / FinalDivFactor
+	CycleMomentum  bool
+	BaseMomentum   float64
+	MaxMomentum    float64
+	Epoch          int // Number of completed steps
+}
+
+// NewOneCycleLR creates a one-cycle schedule with the usual defaults: 30% of the
+// steps warming up, DivFactor 25, FinalDivFactor 1e4 and momentum cycling between
+// 0.85 and 0.95. The optimizer's learning rate (and momentum) are set to their
+// initial values immediately.
+func NewOneCycleLR(opt Optimizer, maxLR float64, totalSteps int) *OneCycleLR {
+	s := &OneCycleLR{
+		Optimizer:      opt,
+		MaxLR:          maxLR,
+		TotalSteps:     totalSteps,
+		PctStart:       0.3,
+		DivFactor:      25,
+		FinalDivFactor: 1e4,
+		CycleMomentum:  true,
+		BaseMomentum:   0.85,
+		MaxMomentum:    0.95,
+	}
+	s.apply()
+	return s
+}
+
+// phase returns whether step lies in the warm-up phase and how far through its phase it is.
+func (s *OneCycleLR) phase(step int) (bool, float64) {
+	warmup := s.PctStart * float64(s.TotalSteps-1)
+	if float64(step) <= warmup {
+		if warmup == 0 {
+			return true, 1
+		}
+		return true, float64(step) / warmup
+	}
+	return false, math.Min(1, (float64(step)-warmup)/(float64(s.TotalSteps-1)-warmup))
+}
+
+// At returns the learning rate for the given step.
+func (s *OneCycleLR) At(step int) float64 {
+	initial := s.MaxLR / s.DivFactor
+	warming, pct := s.phase(step)
+	if warming {
+		return annealCos(initial, s.MaxLR, pct)
+	}
+	return annealCos(s.MaxLR, initial/s.FinalDivFactor, pct)
+}
+
+// MomentumAt returns the momentum for the given step.
+func (s *OneCycleLR) MomentumAt(step int) float64 {
+	warming, pct := s.phase(step)
+	if warming {
+		return annealCos(s.MaxMomentum, s.BaseMomentum, pct)
+	}
+	return annealCos(s.BaseMomentum, s.MaxMomentum, pct)
+}
+
+// apply sets the optimizer's learning rate and momentum for the current step.
+func (s *OneCycleLR) apply() {
+	s.Optimizer.SetLearningRate(s.At(s.Epoch))
+	if ms, ok := s.Optimizer.(MomentumSetter); ok && s.CycleMomentum {
+		ms.SetMomentum(s.MomentumAt(s.Epoch))
+	}
+}
+
+// Step advances one step and updates the optimizer's learning rate and momentum.
+func (s *OneCycleLR) Step() {
+	s.Epoch++
+	s.apply()
+}
+
+// Warmup linearly ramps the learning rate up to the inner scheduler's initial
+// rate over the first Steps steps, then hands off to the inner scheduler, whose
+// schedule starts from its own step 0 at that point. Construct the inner
+// scheduler first (so it records the full base rate), then wrap it.
+type Warmup struct {
+	Optimizer Optimizer
+	Inner     Scheduler
+	Steps     int // Number of warm-up steps
+	Epoch     int // Number of completed steps
+}
+
+// NewWarmup wraps inner with a linear warm-up of the given number of steps and
+// immediately lowers opt's learning rate to the first warm-up value.
+func NewWarmup(opt Optimizer, inner Scheduler, steps int) *Warmup {
+	w := &Warmup{
+		Optimizer: opt,
+		Inner:     inner,
+		Steps:     steps,
+	}
+	opt.SetLearningRate(w.At(0))
+	return w
+}
+
+// At returns Inner.At(0) * (step+1) / Steps during the warm-up and
+// Inner.At(step - Steps) afterwards.
+func (w *Warmup) At(step int) float64 {
+	if step < w.Steps {
+		return w.Inner.At(0) * float64(step+1) / float64(w.Steps)
+	}
+	return w.Inner.At(step - w.Steps)
+}
+
+// Step advances one step and updates the optimizer's learning rate. After the
+// warm-up the inner scheduler is stepped too, so its own state stays in sync.
+func (w *Warmup) Step() {
+	w.Epoch++
+	if w.Epoch > w.Steps {
+		w.Inner.Step()
+	}
+	w.Optimizer.SetLearningRate(w.At(w.Epoch))
+}
+
+// CyclicMode selects how the amplitude of a CyclicLR evolves between cycles.
+type CyclicMode int
+
+const (
+	// Triangular keeps the same amplitude in every cycle.
+	Triangular CyclicMode = iota
+	// Triangular2 halves the amplitude after every cycle.
+	Triangular2
+)
+
+// CyclicLR moves the learning rate linearly back and forth between BaseLR and
+// MaxLR, taking StepSize steps for each half cycle. Like OneCycleLR it is
+// usually stepped after every batch.
+type CyclicLR struct {
+	Optimizer Optimizer
+	BaseLR    float64
+	MaxLR     float64
+	StepSize  int // Steps per half cycle
+	Mode      CyclicMode
+	Epoch     int // Number of completed steps
+}
+
+// NewCyclicLR creates a cyclical schedule and sets opt's learning rate to baseLR.
+// It panics if stepSize is not positive.
+func NewCyclicLR(opt Optimizer, baseLR, maxLR float64, stepSize int, mode CyclicMode) *CyclicLR {
+	if stepSize <= 0 {
+		panic(fmt.Sprintf("optim: CyclicLR step size must be positive, got %d", stepSize))
+	}
+	opt.SetLearningRate(baseLR)
+	return &CyclicLR{
+		Optimizer: opt,
+		BaseLR:    baseLR,
+		MaxLR:     maxLR,
+		StepSize:  stepSize,
+		Mode:      mode,
+	}
+}
+
+// At returns the learning rate for the given step.
+func (s *CyclicLR) At(step int) float64 {
+	cycle := math.Floor(1 + float64(step)/float64(2*s.StepSize))
+	x := math.Abs(float64(step)/float64(s.StepSize) - 2*cycle + 1)
+	scale := 1.0
+	if s.Mode == Triangular2 {
+		scale = 1 / math.Pow(2, cycle-1)
+	}
+	return s.BaseLR + (s.MaxLR-s.BaseLR)*math.Max(0, 1-x)*scale
+}
+
+// Step advances one step and updates the optimizer's learning rate.
+func (s *CyclicLR) Step() {
+	s.Epoch++
+	s.Optimizer.SetLearningRate(s.At(s.Epoch))
+}
+
+// LRPoint is one measurement of an LR range test.
+type LRPoint struct {
+	LR   float64
+	Loss float64
+}
+
+// FindLR runs a learning-rate range test: it performs up to numSteps optimizer
+// steps while increasing the learning rate exponentially from minLR to maxLR,
+// and records the loss observed at each rate. step must run one forward pass,
+// call FullBackward on the loss and return the loss value; FindLR then calls
+// opt.Step and opt.ZeroGrad. The test stops early once the loss exceeds four
+// times the best loss seen. It trains the model, so run it on a clone (or
+// re-initialize afterwards); the optimizer's learning rate is restored at the end.
+func FindLR(opt Optimizer, step func() float64, minLR, maxLR float64, numSteps int) []LRPoint {
+	original := opt.LearningRate()
+	defer opt.SetLearningRate(original)
+
+	growth := math.Pow(maxLR/minLR, 1/float64(max(numSteps-1, 1)))
+	lr := minLR
+	best := math.Inf(1)
+	var points []LRPoint
+	for i := 0; i < numSteps; i++ {
+		opt.SetLearningRate(lr)
+		loss := step()
+		opt.Step()
+		opt.ZeroGrad()
+
+		points = append(points, LRPoint{LR: lr, Loss: loss})
+		if math.IsNaN(loss) || loss > 4*best {
+			break // Diverged: larger rates are not worth measuring
+		}
+		best = math.Min(best, loss)
+		lr *= growth
+	}
+	return points
+}
+
+// SuggestLR picks a learning rate from an LR range test: the rate at which the
+// loss was falling fastest, a common and conservative choice.
+func SuggestLR(points []LRPoint) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	bestLR, steepest := points[0].LR, 0.0
+	for i := 1; i < len(points); i++ {
+		slope := (points[i].Loss - points[i-1].Loss) / math.Log(points[i].LR/points[i-1].LR)
+		if slope < steepest {
+			bestLR, steepest = points[i].LR, slope
+		}
+	}
+	return bestLR
+}
@@ -0,0 +1,79 @@
+package optim
+
+// Seeker is implemented by schedulers that can report their position and jump
+// to any position, applying the learning rate for it. Trainers use it to
+// resume a schedule from a checkpoint.
+type Seeker interface {
+	Position() int
+	Seek(epoch int)
+}
+
+// Position returns the number of completed epochs.
+func (s *StepLR) Position() int {
+	return s.Epoch
+}
+
+// Seek moves to the given epoch and applies its learning rate.
+func (s *StepLR) Seek(epoch int) {
+	s.Epoch = epoch
+	s.Optimizer.SetLearningRate(s.At(epoch))
+}
+
+// Position returns the number of completed epochs.
+func (s *ExponentialLR) Position() int {
+	return s.Epoch
+}
+
+// Seek moves to the given epoch and applies its learning rate.
+func (s *ExponentialLR) Seek(epoch int) {
+	s.Epoch = epoch
+	s.Optimizer.SetLearningRate(s.At(epoch))
+}
+
+// Position returns the number of completed epochs.
+func (s *CosineAnnealingLR) Position() int {
+	return s.Epoch
+}
+
+// Seek moves to the given epoch and applies its learning rate.
+func (s *CosineAnnealingLR) Seek(epoch int) {
+	s.Epoch = epoch
+	s.Optimizer.SetLearningRate(s.At(epoch))
+}
+
+// Position returns the number of completed steps.
+func (s *OneCycleLR) Position() int {
+	return s.Epoch
+}
+
+// Seek moves to the given step and applies its learning rate and momentum.
+func (s *OneCycleLR) Seek(step int) {
+	s.Epoch = step
+	s.apply()
+}
+
+// Position returns the number of completed steps.
+func (w *Warmup) Position() int {
+	return w.Epoch
+}
+
+// Seek moves to the given step, positions the inner scheduler accordingly
+// (if it is a Seeker) and applies the learning rate.
+func (w *Warmup) Seek(step int) {
+	w.Epoch = step
+	if inner, ok := w.Inner.(Seeker); ok {
+		inner.Seek(max(0, step-w.Steps))
+	}
+	w.Optimizer.SetLearningRate(w.At(step))
+}
+
+// Position returns the number of completed steps.
+func (s *CyclicLR) Position() int {
+	return s.Epoch
+}
+
+// Seek moves to the given step and applies its learning rate.
+func (s *CyclicLR) Seek(step int) {
+	s.Epoch = step
+	s.Optimizer.SetLearningRate(s.At(step))
+}
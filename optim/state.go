@@ -0,0 +1,224 @@
+package optim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// State is a snapshot of an optimizer's internal state: its current learning
+// rate, step count and per-parameter buffers (momentum, moment estimates, ...).
+// Restoring it into a freshly constructed optimizer over the same parameters
+// resumes training exactly where the snapshot was taken. Hyperparameters set at
+// construction (betas, momentum, weight decay) are not part of the state.
+type State struct {
+	Kind    string               `json:"kind"`
+	LR      float64              `json:"lr"`
+	Step    int                  `json:"step,omitempty"`
+	Buffers map[string][]float64 `json:"buffers,omitempty"` // Keyed by buffer name, one entry per parameter
+	Groups  []State              `json:"groups,omitempty"`  // States of wrapped optimizers (per group for Grouped)
+}
+
+// Stateful is implemented by optimizers whose state can be saved and restored.
+// State fails if the optimizer wraps one that cannot save its state.
+type Stateful interface {
+	State() (State, error)
+	LoadState(s State) error
+}
+
+// Save writes the state of opt to w as JSON.
+func Save(w io.Writer, opt Optimizer) error {
+	st, ok := opt.(Stateful)
+	if !ok {
+		return fmt.Errorf("optim: %T does not support saving its state", opt)
+	}
+	s, err := st.State()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Load reads a state written by Save from r and restores it into opt, which
+// must be the same kind of optimizer over the same number of parameters.
+func Load(r io.Reader, opt Optimizer) error {
+	st, ok := opt.(Stateful)
+	if !ok {
+		return fmt.Errorf("optim: %T does not support loading its state", opt)
+	}
+	var s State
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("optim: decoding state: %w", err)
+	}
+	return st.LoadState(s)
+}
+
+// checkKind returns an error if a state was saved by a different kind of optimizer.
+func checkKind(s State, kind string) error {
+	if s.Kind != kind {
+		return fmt.Errorf("optim: cannot load %s state into %s", s.Kind, kind)
+	}
+	return nil
+}
+
+// innerState returns the state of an optimizer wrapped by another one.
+func innerState(inner Optimizer) (State, error) {
+	st, ok := inner.(Stateful)
+	if !ok {
+		return State{}, fmt.Errorf("optim: inner optimizer %T does not support saving its state", inner)
+	}
+	return st.State()
+}
+
+// buffer returns a copy of the named buffer of s, or nil if it was not saved.
+// A saved buffer must have one entry per parameter.
+func buffer(s State, name string, n int) ([]float64, error) {
+	b, ok := s.Buffers[name]
+	if !ok {
+		return nil, nil
+	}
+	if len(b) != n {
+		return nil, fmt.Errorf("optim: %s buffer %q has %d entries for %d parameters", s.Kind, name, len(b), n)
+	}
+	return append([]float64(nil), b...), nil
+}
+
+// buffers collects the non-nil buffers into a map for State.
+func buffers(named map[string][]float64) map[string][]float64 {
+	out := map[string][]float64{}
+	for name, b := range named {
+		if b != nil {
+			out[name] = append([]float64(nil), b...)
+		}
+	}
+	return out
+}
+
+// State returns the learning rate and velocity buffer.
+func (opt *SGD) State() (State, error) {
+	return State{Kind: "sgd", LR: opt.LR, Buffers: buffers(map[string][]float64{"velocity": opt.velocity})}, nil
+}
+
+// LoadState restores a state returned by State.
+func (opt *SGD) LoadState(s State) error {
+	if err := checkKind(s, "sgd"); err != nil {
+		return err
+	}
+	velocity, err := buffer(s, "velocity", len(opt.Params))
+	if err != nil {
+		return err
+	}
+	opt.LR, opt.velocity = s.LR, velocity
+	return nil
+}
+
+// State returns the learning rate, step count and moment estimates.
+func (opt *Adam) State() (State, error) {
+	return State{
+		Kind: "adam",
+		LR:   opt.LR,
+		Step: opt.t,
+		Buffers: buffers(map[string][]float64{
+			"m":     opt.m,
+			"v":     opt.v,
+			"v_max": opt.vMax,
+		}),
+	}, nil
+}
+
+// LoadState restores a state returned by State.
+func (opt *Adam) LoadState(s State) error {
+	if err := checkKind(s, "adam"); err != nil {
+		return err
+	}
+	n := len(opt.Params)
+	m, err := buffer(s, "m", n)
+	if err != nil {
+		return err
+	}
+	v, err := buffer(s, "v", n)
+	if err != nil {
+		return err
+	}
+	vMax, err := buffer(s, "v_max", n)
+	if err != nil {
+		return err
+	}
+	if m == nil || v == nil {
+		return fmt.Errorf("optim: adam state is missing its moment estimates")
+	}
+	opt.LR, opt.t, opt.m, opt.v, opt.vMax = s.LR, s.Step, m, v, vMax
+	return nil
+}
+
+// State returns the learning rate and moving averages.
+func (opt *RMSProp) State() (State, error) {
+	return State{
+		Kind: "rmsprop",
+		LR:   opt.LR,
+		Buffers: buffers(map[string][]float64{
+			"sq":       opt.sq,
+			"mean":     opt.mean,
+			"velocity": opt.velocity,
+		}),
+	}, nil
+}
+
+// LoadState restores a state returned by State.
+func (opt *RMSProp) LoadState(s State) error {
+	if err := checkKind(s, "rmsprop"); err != nil {
+		return err
+	}
+	restored := make([][]float64, 3)
+	for i, name := range []string{"sq", "mean", "velocity"} {
+		b, err := buffer(s, name, len(opt.Params))
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return fmt.Errorf("optim: rmsprop state is missing buffer %q", name)
+		}
+		restored[i] = b
+	}
+	opt.LR = s.LR
+	opt.sq, opt.mean, opt.velocity = restored[0], restored[1], restored[2]
+	return nil
+}
+
+// State returns the base learning rate and the state of every group's optimizer.
+func (g *Grouped) State() (State, error) {
+	s := State{Kind: "grouped", LR: g.baseLR, Groups: make([]State, len(g.Optimizers))}
+	for i, opt := range g.Optimizers {
+		st, ok := opt.(Stateful)
+		if !ok {
+			return State{}, fmt.Errorf("optim: %T in group %d does not support saving its state", opt, i)
+		}
+		gs, err := st.State()
+		if err != nil {
+			return State{}, fmt.Errorf("group %d: %w", i, err)
+		}
+		s.Groups[i] = gs
+	}
+	return s, nil
+}
+
+// LoadState restores a state returned by State into every group.
+func (g *Grouped) LoadState(s State) error {
+	if err := checkKind(s, "grouped"); err != nil {
+		return err
+	}
+	if len(s.Groups) != len(g.Optimizers) {
+		return fmt.Errorf("optim: grouped state has %d groups, optimizer has %d", len(s.Groups), len(g.Optimizers))
+	}
+	for i, opt := range g.Optimizers {
+		st, ok := opt.(Stateful)
+		if !ok {
+			return fmt.Errorf("optim: %T in group %d does not support loading its state", opt, i)
+		}
+		if err := st.LoadState(s.Groups[i]); err != nil {
+			return fmt.Errorf("group %d: %w", i, err)
+		}
+	}
+	g.baseLR = s.LR
+	return nil
+}
@@ -0,0 +1,285 @@
+// Package registry manages versions of named models in a directory, so
+// applications can publish retrained models, load a specific version and
+// roll a deployment back to an earlier one.
+//
+// Every model has a subdirectory holding its versions as v1.json, v2.json,
+// ... in the format of engine.MLP.SaveWithMetadata, and a file named CURRENT
+// with the number of the deployed version:
+//
+//	models/
+//	└── spam/
+//	    ├── CURRENT
+//	    ├── v1.json
+//	    └── v2.json
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Current passed as a version to Pull loads the deployed version of a model.
+const Current = 0
+
+// currentFile is the name of the file holding the deployed version.
+const currentFile = "CURRENT"
+
+// Registry stores models in the directory Dir.
+type Registry struct {
+	Dir string
+}
+
+// Open returns a Registry over dir, creating the directory if needed.
+func Open(dir string) (*Registry, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	return &Registry{Dir: dir}, nil
+}
+
+// Version describes one stored version of a model.
+type Version struct {
+	Number   int
+	Saved    time.Time
+	Metadata *engine.ModelMetadata
+}
+
+// Model lists the stored versions of a named model.
+type Model struct {
+	Name     string
+	Current  int       // Deployed version
+	Versions []Version // In increasing order
+}
+
+// Push stores model with meta (which may be nil) as the next version of name,
+// makes it the deployed version and returns its number.
+func (r *Registry) Push(name string, model *engine.MLP, meta *engine.ModelMetadata) (int, error) {
+	if err := checkName(name); err != nil {
+		return 0, err
+	}
+	if meta == nil {
+		meta = &engine.ModelMetadata{} // Still records the package version and save time
+	}
+	dir := filepath.Join(r.Dir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("registry: %w", err)
+	}
+	versions, err := r.versions(name)
+	if err != nil {
+		return 0, err
+	}
+	next := 1
+	if len(versions) > 0 {
+		next = versions[len(versions)-1] + 1
+	}
+
+	// O_EXCL claims the version number, so concurrent pushes cannot overwrite
+	// each other; whoever loses the race tries the next number.
+	var f *os.File
+	for {
+		f, err = os.OpenFile(r.path(name, next), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, fs.ErrExist) {
+			break
+		}
+		next++
+	}
+	if err != nil {
+		return 0, fmt.Errorf("registry: %w", err)
+	}
+	err = model.SaveWithMetadata(f, meta)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return 0, fmt.Errorf("registry: pushing %s v%d: %w", name, next, err)
+	}
+	if err := r.Deploy(name, next); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// Pull loads the given version of name, or its deployed version if version
+// is Current.
+func (r *Registry) Pull(name string, version int) (*engine.MLP, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	if version == Current {
+		v, err := r.Current(name)
+		if err != nil {
+			return nil, err
+		}
+		version = v
+	}
+	f, err := os.Open(r.path(name, version))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("registry: %s has no version %d", name, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	defer f.Close()
+	mlp, err := engine.LoadMLP(f)
+	if err != nil {
+		return nil, fmt.Errorf("registry: pulling %s v%d: %w", name, version, err)
+	}
+	return mlp, nil
+}
+
+// Current returns the deployed version of name.
+func (r *Registry) Current(name string) (int, error) {
+	if err := checkName(name); err != nil {
+		return 0, err
+	}
+	b, err := os.ReadFile(filepath.Join(r.Dir, name, currentFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("registry: no model named %q", name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("registry: %w", err)
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, fmt.Errorf("registry: corrupt %s for %s: %w", currentFile, name, err)
+	}
+	return v, nil
+}
+
+// Deploy makes version the deployed version of name, e.g. to roll back to a
+// version known to be good. The file is replaced atomically, so concurrent
+// readers see either the old or the new version.
+func (r *Registry) Deploy(name string, version int) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if _, err := os.Stat(r.path(name, version)); err != nil {
+		return fmt.Errorf("registry: %s has no version %d", name, version)
+	}
+	dir := filepath.Join(r.Dir, name)
+	tmp, err := os.CreateTemp(dir, currentFile+".*")
+	if err != nil {
+		return fmt.Errorf("registry: %w", err)
+	}
+	_, err = fmt.Fprintln(tmp, version)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, currentFile))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("registry: %w", err)
+	}
+	return nil
+}
+
+// Rollback deploys the newest version of name older than the deployed one
+// and returns its number.
+func (r *Registry) Rollback(name string) (int, error) {
+	cur, err := r.Current(name)
+	if err != nil {
+		return 0, err
+	}
+	versions, err := r.versions(name)
+	if err != nil {
+		return 0, err
+	}
+	prev := 0
+	for _, v := range versions {
+		if v < cur {
+			prev = v
+		}
+	}
+	if prev == 0 {
+		return 0, fmt.Errorf("registry: %s has no version before %d", name, cur)
+	}
+	return prev, r.Deploy(name, prev)
+}
+
+// List returns every model in the registry by name, with the metadata of its
+// versions read by engine.InspectModel.
+func (r *Registry) List() ([]Model, error) {
+	entries, err := os.ReadDir(r.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	var models []Model
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		versions, err := r.versions(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		m := Model{Name: e.Name()}
+		if m.Current, err = r.Current(m.Name); err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			info, err := engine.InspectModel(r.path(m.Name, v))
+			if err != nil {
+				return nil, fmt.Errorf("registry: %s v%d: %w", m.Name, v, err)
+			}
+			version := Version{Number: v, Metadata: info.Metadata}
+			if info.Metadata != nil {
+				version.Saved = info.Metadata.Saved
+			}
+			m.Versions = append(m.Versions, version)
+		}
+		models = append(models, m)
+	}
+	return models, nil
+}
+
+// versions returns the stored version numbers of name in increasing order.
+func (r *Registry) versions(name string) ([]int, error) {
+	entries, err := os.ReadDir(filepath.Join(r.Dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("registry: %w", err)
+	}
+	var versions []int
+	for _, e := range entries {
+		num, ok := strings.CutPrefix(e.Name(), "v")
+		num, ok2 := strings.CutSuffix(num, ".json")
+		if !ok || !ok2 {
+			continue
+		}
+		if v, err := strconv.Atoi(num); err == nil && v > 0 {
+			versions = append(versions, v)
+		}
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// path returns the file of a version of name.
+func (r *Registry) path(name string, version int) string {
+	return filepath.Join(r.Dir, name, fmt.Sprintf("v%d.json", version))
+}
+
+// checkName rejects names that are not a single path element.
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("registry: invalid model name %q", name)
+	}
+	return nil
+}
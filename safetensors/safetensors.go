@@ -0,0 +1,231 @@
+// Package safetensors reads and writes the safetensors format of Hugging
+// Face: a JSON header with the dtype, shape and byte range of every tensor,
+// followed by the raw little-endian data. Unlike pickle-based checkpoints it
+// cannot run code when loaded, and the flat layout allows zero-copy loading
+// in other frameworks. Tensors are keyed by the names of
+// engine.NamedParameters (see SaveModel and LoadModel).
+package safetensors
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/npy"
+)
+
+// DType is the element type of a stored tensor.
+type DType string
+
+const (
+	F64  DType = "F64"
+	F32  DType = "F32" // The usual type of PyTorch weights
+	F16  DType = "F16"
+	BF16 DType = "BF16"
+	I64  DType = "I64"
+	I32  DType = "I32"
+	I16  DType = "I16"
+	I8   DType = "I8"
+	U8   DType = "U8"
+	BOOL DType = "BOOL"
+)
+
+// size returns the number of bytes per element, or 0 for unknown types.
+func (d DType) size() int {
+	switch d {
+	case F64, I64:
+		return 8
+	case F32, I32:
+		return 4
+	case F16, BF16, I16:
+		return 2
+	case I8, U8, BOOL:
+		return 1
+	}
+	return 0
+}
+
+// entry describes one tensor in the header.
+type entry struct {
+	DType   DType    `json:"dtype"`
+	Shape   []int    `json:"shape"`
+	Offsets [2]int64 `json:"data_offsets"` // Byte range in the data following the header
+}
+
+// maxHeader bounds the header size to reject corrupt files before allocating.
+const maxHeader = 100 << 20
+
+// Read decodes every tensor of a safetensors file from r, converted to
+// float64, and the free-form metadata of the header, if any.
+func Read(r io.Reader) (tensors map[string]*npy.Array, metadata map[string]string, err error) {
+	var n uint64
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, nil, fmt.Errorf("safetensors: reading header size: %w", err)
+	}
+	if n > maxHeader {
+		return nil, nil, fmt.Errorf("safetensors: header of %d bytes is too large", n)
+	}
+	header := make([]byte, n)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, fmt.Errorf("safetensors: reading header: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(header, &raw); err != nil {
+		return nil, nil, fmt.Errorf("safetensors: decoding header: %w", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("safetensors: reading data: %w", err)
+	}
+
+	tensors = map[string]*npy.Array{}
+	for name, msg := range raw {
+		if name == "__metadata__" {
+			if err := json.Unmarshal(msg, &metadata); err != nil {
+				return nil, nil, fmt.Errorf("safetensors: decoding metadata: %w", err)
+			}
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(msg, &e); err != nil {
+			return nil, nil, fmt.Errorf("safetensors: tensor %q: %w", name, err)
+		}
+		a, err := decode(e, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("safetensors: tensor %q: %w", name, err)
+		}
+		tensors[name] = a
+	}
+	return tensors, metadata, nil
+}
+
+// decode converts the bytes of one tensor to float64 values.
+func decode(e entry, data []byte) (*npy.Array, error) {
+	a := &npy.Array{Shape: e.Shape}
+	size := e.DType.size()
+	if size == 0 {
+		return nil, fmt.Errorf("unsupported dtype %s", e.DType)
+	}
+	begin, end := e.Offsets[0], e.Offsets[1]
+	if begin < 0 || end < begin || end > int64(len(data)) || end-begin != int64(a.Size()*size) {
+		return nil, fmt.Errorf("data offsets %v do not hold %d %s values", e.Offsets, a.Size(), e.DType)
+	}
+	b := data[begin:end]
+	a.Data = make([]float64, a.Size())
+	for i := range a.Data {
+		v := b[i*size : (i+1)*size]
+		switch e.DType {
+		case F64:
+			a.Data[i] = math.Float64frombits(binary.LittleEndian.Uint64(v))
+		case F32:
+			a.Data[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(v)))
+		case F16:
+			a.Data[i] = halfToFloat(binary.LittleEndian.Uint16(v))
+		case BF16:
+			a.Data[i] = float64(math.Float32frombits(uint32(binary.LittleEndian.Uint16(v)) << 16))
+		case I64:
+			a.Data[i] = float64(int64(binary.LittleEndian.Uint64(v)))
+		case I32:
+			a.Data[i] = float64(int32(binary.LittleEndian.Uint32(v)))
+		case I16:
+			a.Data[i] = float64(int16(binary.LittleEndian.Uint16(v)))
+		case I8:
+			a.Data[i] = float64(int8(v[0]))
+		case U8, BOOL:
+			a.Data[i] = float64(v[0])
+		}
+	}
+	return a, nil
+}
+
+// halfToFloat converts an IEEE 754 half-precision number to float64.
+func halfToFloat(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp, frac := int(h>>10&0x1f), float64(h&0x3ff)
+	switch exp {
+	case 0: // Subnormal
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(1+frac/1024, exp-15)
+}
+
+// Write encodes tensors to w as a safetensors file with elements of type
+// dtype (F64 or F32; F32 rounds the values) and the given metadata, which
+// may be nil. Tensors are stored in order of name.
+func Write(w io.Writer, tensors map[string]*npy.Array, dtype DType, metadata map[string]string) error {
+	if dtype != F64 && dtype != F32 {
+		return fmt.Errorf("safetensors: writing %s is not supported", dtype)
+	}
+	names := make([]string, 0, len(tensors))
+	for name := range tensors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := map[string]any{}
+	if len(metadata) > 0 {
+		header["__metadata__"] = metadata
+	}
+	var data []byte
+	for _, name := range names {
+		a := tensors[name]
+		if len(a.Data) != a.Size() {
+			return fmt.Errorf("safetensors: tensor %q has %d values for shape %v", name, len(a.Data), a.Shape)
+		}
+		begin := int64(len(data))
+		for _, v := range a.Data {
+			if dtype == F64 {
+				data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+			} else {
+				data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(v)))
+			}
+		}
+		shape := a.Shape
+		if shape == nil {
+			shape = []int{} // Scalars have the empty shape, not null
+		}
+		header[name] = entry{DType: dtype, Shape: shape, Offsets: [2]int64{begin, int64(len(data))}}
+	}
+
+	h, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("safetensors: encoding header: %w", err)
+	}
+	for len(h)%8 != 0 {
+		h = append(h, ' ') // Pad so the data starts 8-byte aligned
+	}
+	b := binary.LittleEndian.AppendUint64(nil, uint64(len(h)))
+	b = append(b, h...)
+	b = append(b, data...)
+	_, err = w.Write(b)
+	return err
+}
+
+// SaveModel writes the parameters of m to w, keyed by the names of
+// engine.NamedParameters, with elements of type dtype.
+func SaveModel(w io.Writer, m engine.Module, dtype DType) error {
+	// "format" tells Hugging Face loaders the tensors follow PyTorch conventions
+	return Write(w, npy.Params(m), dtype, map[string]string{"format": "pt"})
+}
+
+// LoadModel reads a safetensors file from r into the parameters of m with the
+// same names; every parameter must be present with its shape (see npy.LoadParams).
+func LoadModel(m engine.Module, r io.Reader) error {
+	tensors, _, err := Read(r)
+	if err != nil {
+		return err
+	}
+	return npy.LoadParams(m, tensors)
+}
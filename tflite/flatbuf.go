@@ -0,0 +1,159 @@
+package tflite
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// A minimal FlatBuffers serializer, enough for the TFLite schema: tables of
+// scalar and offset fields, strings and vectors of scalars or tables. Objects
+// are described as a tree and laid out front to back, every child after the
+// field that refers to it, so all offsets are the positive distances the
+// format prescribes.
+
+// object is a value stored out of line and referred to by an offset.
+type object interface {
+	// write appends the object to b, aligned as it requires, and returns the
+	// extended buffer and the position offsets to it must point to.
+	write(b []byte) ([]byte, int)
+}
+
+// field is one field of a table: a scalar of size bytes, or an offset to obj.
+type field struct {
+	size  int
+	value uint32
+	obj   object
+}
+
+// table is a flatbuffer table; fields are indexed by their id in the schema
+// and nil entries are absent (default) fields.
+type table []*field
+
+// byteField, uintField, intField and objField create table fields.
+func byteField(v uint8) *field   { return &field{size: 1, value: uint32(v)} }
+func uintField(v uint32) *field  { return &field{size: 4, value: v} }
+func intField(v int32) *field    { return &field{size: 4, value: uint32(v)} }
+func objField(obj object) *field { return &field{size: 4, obj: obj} }
+
+// write lays out the vtable, then the table with its 4-byte fields first,
+// then the objects its fields refer to.
+func (t table) write(b []byte) ([]byte, int) {
+	// Table layout: soffset to the vtable, then the fields by decreasing size
+	offsets := make([]int, len(t))
+	size := 4
+	for _, width := range []int{4, 1} {
+		for i, f := range t {
+			if f != nil && f.size == width {
+				offsets[i] = size
+				size += width
+			}
+		}
+	}
+
+	b = pad(b, 2)
+	vtable := len(b)
+	b = binary.LittleEndian.AppendUint16(b, uint16(4+2*len(t)))
+	b = binary.LittleEndian.AppendUint16(b, uint16(size))
+	for _, off := range offsets {
+		b = binary.LittleEndian.AppendUint16(b, uint16(off))
+	}
+
+	b = pad(b, 4)
+	start := len(b)
+	b = append(b, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b[start:], uint32(int32(start-vtable)))
+	for i, f := range t {
+		if f == nil || f.obj != nil {
+			continue
+		}
+		if f.size == 1 {
+			b[start+offsets[i]] = byte(f.value)
+		} else {
+			binary.LittleEndian.PutUint32(b[start+offsets[i]:], f.value)
+		}
+	}
+	for i, f := range t {
+		if f == nil || f.obj == nil {
+			continue
+		}
+		var pos int
+		b, pos = f.obj.write(b)
+		at := start + offsets[i]
+		binary.LittleEndian.PutUint32(b[at:], uint32(pos-at))
+	}
+	return b, start
+}
+
+// str is a flatbuffer string: length, UTF-8 bytes and a terminating zero.
+type str string
+
+func (s str) write(b []byte) ([]byte, int) {
+	b = pad(b, 4)
+	start := len(b)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	b = append(b, s...)
+	return append(b, 0), start
+}
+
+// ints is a vector of int32.
+type ints []int32
+
+func (v ints) write(b []byte) ([]byte, int) {
+	b = pad(b, 4)
+	start := len(b)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(v)))
+	for _, x := range v {
+		b = binary.LittleEndian.AppendUint32(b, uint32(x))
+	}
+	return b, start
+}
+
+// float32Bytes is a vector of ubyte holding little-endian float32 values,
+// with its data aligned to 16 bytes as TFLite requests for buffers.
+type float32Bytes []float64
+
+func (v float32Bytes) write(b []byte) ([]byte, int) {
+	for (len(b)+4)%16 != 0 {
+		b = append(b, 0)
+	}
+	start := len(b)
+	b = binary.LittleEndian.AppendUint32(b, uint32(4*len(v)))
+	for _, x := range v {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(x)))
+	}
+	return b, start
+}
+
+// tables is a vector of tables, stored as offsets followed by the tables.
+type tables []table
+
+func (v tables) write(b []byte) ([]byte, int) {
+	b = pad(b, 4)
+	start := len(b)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(v)))
+	b = append(b, make([]byte, 4*len(v))...)
+	for i, t := range v {
+		var pos int
+		b, pos = t.write(b)
+		at := start + 4 + 4*i
+		binary.LittleEndian.PutUint32(b[at:], uint32(pos-at))
+	}
+	return b, start
+}
+
+// finish serializes root as a complete buffer with the given file identifier.
+func finish(root table, identifier string) []byte {
+	b := make([]byte, 8)
+	copy(b[4:], identifier)
+	b, pos := root.write(b)
+	binary.LittleEndian.PutUint32(b, uint32(pos))
+	return b
+}
+
+// pad appends zero bytes until len(b) is a multiple of n.
+func pad(b []byte, n int) []byte {
+	for len(b)%n != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
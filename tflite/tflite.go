@@ -0,0 +1,150 @@
+// Package tflite exports trained MLPs as TensorFlow Lite models, so they can
+// run for inference on mobile and edge runtimes that load .tflite files.
+// Every layer becomes a FULLY_CONNECTED operator with float32 weights; ReLU is
+// fused into it and Tanh and Sigmoid follow as TANH and LOGISTIC operators.
+package tflite
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Values of the TFLite schema (schema.fbs) used by the exporter.
+const (
+	schemaVersion = 3
+
+	opFullyConnected = 9
+	opLogistic       = 14
+	opTanh           = 28
+
+	tensorFloat32 = 0
+
+	optionsFullyConnected = 8 // BuiltinOptions union member FullyConnectedOptions
+
+	actNone = 0
+	actRelu = 1
+)
+
+// Export writes mlp to w as a TFLite flatbuffer with one float32 input of
+// shape [1, inputs] and one output of shape [1, outputs]. description is
+// stored in the model. All neurons of a layer must share one activation.
+func Export(w io.Writer, mlp *engine.MLP, description string) error {
+	if len(mlp.Layers) == 0 || len(mlp.Layers[0].Neurons) == 0 {
+		return fmt.Errorf("tflite: MLP has no layers")
+	}
+	e := exporter{buffers: tables{{}}} // Buffer 0 is the empty sentinel
+	numIn := len(mlp.Layers[0].Neurons[0].Weights)
+	cur := e.tensor("input", []int32{1, int32(numIn)}, nil)
+	input := cur
+
+	for i, layer := range mlp.Layers {
+		if len(layer.Neurons) == 0 {
+			return fmt.Errorf("tflite: layer %d has no neurons", i+1)
+		}
+		act := layer.Neurons[0].Activation
+		numOut := len(layer.Neurons)
+		weights := make([]float64, 0, numOut*numIn)
+		biases := make([]float64, numOut)
+		for j, neuron := range layer.Neurons {
+			if neuron.Activation != act {
+				return fmt.Errorf("tflite: layer %d mixes %v and %v activations", i+1, act, neuron.Activation)
+			}
+			for _, w := range neuron.Weights {
+				weights = append(weights, w.Data)
+			}
+			biases[j] = neuron.Bias.Data
+		}
+
+		name := fmt.Sprintf("layers.%d", i)
+		w := e.tensor(name+".weight", []int32{int32(numOut), int32(numIn)}, weights)
+		b := e.tensor(name+".bias", []int32{int32(numOut)}, biases)
+		out := e.tensor(name+".output", []int32{1, int32(numOut)}, nil)
+		fused := uint8(actNone)
+		if act == engine.ReLU {
+			fused = actRelu
+		}
+		e.operator(opFullyConnected, []int32{cur, w, b}, out, table{byteField(fused)})
+		cur = out
+
+		switch act {
+		case engine.Tanh, engine.Sigmoid:
+			op, suffix := int32(opTanh), ".tanh"
+			if act == engine.Sigmoid {
+				op, suffix = opLogistic, ".sigmoid"
+			}
+			out = e.tensor(name+suffix, []int32{1, int32(numOut)}, nil)
+			e.operator(op, []int32{cur}, out, nil)
+			cur = out
+		case engine.ReLU, engine.Linear:
+		default:
+			return fmt.Errorf("tflite: activation %v is not supported", act)
+		}
+		numIn = numOut
+	}
+
+	subgraph := table{
+		objField(e.tensors),
+		objField(ints{input}),
+		objField(ints{cur}),
+		objField(e.operators),
+		objField(str("main")),
+	}
+	model := table{
+		uintField(schemaVersion),
+		objField(e.opcodes),
+		objField(tables{subgraph}),
+		objField(str(description)),
+		objField(e.buffers),
+	}
+	_, err := w.Write(finish(model, "TFL3"))
+	return err
+}
+
+// exporter collects the tensors, buffers and operators of the subgraph.
+type exporter struct {
+	tensors, buffers, operators, opcodes tables
+	opcodeIndex                          map[int32]uint32
+}
+
+// tensor adds a float32 tensor and returns its index. data, if not nil,
+// holds constant values stored in a buffer of their own.
+func (e *exporter) tensor(name string, shape []int32, data []float64) int32 {
+	buffer := uint32(0) // The empty buffer marks a computed tensor
+	if data != nil {
+		buffer = uint32(len(e.buffers))
+		e.buffers = append(e.buffers, table{objField(float32Bytes(data))})
+	}
+	e.tensors = append(e.tensors, table{
+		objField(ints(shape)),
+		byteField(tensorFloat32),
+		uintField(buffer),
+		objField(str(name)),
+	})
+	return int32(len(e.tensors) - 1)
+}
+
+// operator adds a builtin operator applied to inputs, writing output, with
+// FullyConnectedOptions if options is not nil.
+func (e *exporter) operator(code int32, inputs []int32, output int32, options table) {
+	if e.opcodeIndex == nil {
+		e.opcodeIndex = map[int32]uint32{}
+	}
+	idx, ok := e.opcodeIndex[code]
+	if !ok {
+		idx = uint32(len(e.opcodes))
+		e.opcodeIndex[code] = idx
+		e.opcodes = append(e.opcodes, table{
+			byteField(uint8(code)), // deprecated_builtin_code, still read by older runtimes
+			nil,
+			intField(1), // version
+			intField(code),
+		})
+	}
+	op := table{uintField(idx), objField(ints(inputs)), objField(ints{output})}
+	if options != nil {
+		op = append(op, byteField(optionsFullyConnected), objField(options))
+	}
+	e.operators = append(e.operators, op)
+}
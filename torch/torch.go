@@ -0,0 +1,161 @@
+// Package torch loads the weights of PyTorch models into models of this
+// package, so small pretrained networks can be served from Go.
+//
+// A state_dict is exported from Python without pickle, either as an .npz
+// archive (read with npy.OpenNPZ):
+//
+//	numpy.savez("model.npz", **{k: v.numpy() for k, v in model.state_dict().items()})
+//
+// or as a JSON dump (read with ReadJSON):
+//
+//	json.dump({k: {"shape": list(v.shape), "data": v.flatten().tolist()}
+//	           for k, v in model.state_dict().items()}, f)
+package torch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/npy"
+)
+
+// StateDict maps the parameter names of a PyTorch model to their values.
+type StateDict map[string]*npy.Array
+
+// ReadJSON reads a state_dict dumped as JSON objects with "shape" and flat,
+// row-major "data" per parameter name.
+func ReadJSON(r io.Reader) (StateDict, error) {
+	var raw map[string]struct {
+		Shape []int     `json:"shape"`
+		Data  []float64 `json:"data"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("torch: decoding state_dict: %w", err)
+	}
+	sd := StateDict{}
+	for name, t := range raw {
+		a := &npy.Array{Shape: t.Shape, Data: t.Data}
+		if a.Size() != len(a.Data) {
+			return nil, fmt.Errorf("torch: %q has %d values for shape %v", name, len(a.Data), a.Shape)
+		}
+		sd[name] = a
+	}
+	return sd, nil
+}
+
+// Load copies the weights of sd into m. Names that match the names of
+// engine.NamedParameters are copied directly. Otherwise the modules of both
+// are matched in order: the parameters of sd are grouped by module prefix
+// (e.g. "0." and "2." of an nn.Sequential whose activations sit at 1 and 3,
+// or "fc1." and "fc2." of named nn.Linear attributes), sorted with numbers
+// compared by value, and paired with the modules of m in their order, such as
+// the layers of an MLP. Paired modules must have the same parameter names
+// ("weight", "bias") and every parameter the same shape; nothing is copied
+// otherwise.
+func Load(m engine.Module, sd StateDict) error {
+	if err := npy.LoadParams(m, sd); err == nil {
+		return nil
+	}
+	mapped, err := byOrder(m, sd)
+	if err != nil {
+		return err
+	}
+	return npy.LoadParams(m, mapped)
+}
+
+// module is the parameters of one module, keyed by their last name component.
+type module struct {
+	prefix string
+	params map[string]string // Last name component to full name
+}
+
+// byOrder renames the entries of sd to the names of m by pairing their modules in order.
+func byOrder(m engine.Module, sd StateDict) (StateDict, error) {
+	var names []string
+	for _, np := range engine.NamedParameters(m) {
+		names = append(names, np.Name)
+	}
+	ours := group(names)
+
+	var keys []string
+	for name := range sd {
+		keys = append(keys, name)
+	}
+	theirs := group(keys)
+	sort.SliceStable(theirs, func(i, j int) bool { return naturalLess(theirs[i].prefix, theirs[j].prefix) })
+
+	if len(ours) != len(theirs) {
+		return nil, fmt.Errorf("torch: state_dict has %d modules with parameters, model has %d", len(theirs), len(ours))
+	}
+	mapped := StateDict{}
+	for i, mod := range ours {
+		other := theirs[i]
+		if len(other.params) != len(mod.params) {
+			return nil, fmt.Errorf("torch: module %q has %d parameters, %q has %d",
+				other.prefix, len(other.params), mod.prefix, len(mod.params))
+		}
+		for last, name := range mod.params {
+			from, ok := other.params[last]
+			if !ok {
+				return nil, fmt.Errorf("torch: module %q has no %q for %s", other.prefix, last, name)
+			}
+			mapped[name] = sd[from]
+		}
+	}
+	return mapped, nil
+}
+
+// group groups parameter names by everything before their last dot, keeping
+// the order in which the prefixes first appear.
+func group(names []string) []module {
+	var mods []module
+	index := map[string]int{}
+	for _, name := range names {
+		prefix, last := "", name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			prefix, last = name[:i], name[i+1:]
+		}
+		i, ok := index[prefix]
+		if !ok {
+			i = len(mods)
+			index[prefix] = i
+			mods = append(mods, module{prefix: prefix, params: map[string]string{}})
+		}
+		mods[i].params[last] = name
+	}
+	return mods
+}
+
+// naturalLess compares dotted names component by component, numerically
+// where both components are numbers and by the numbers they end in otherwise,
+// so "layer2" < "layer10" and "2" < "10".
+func naturalLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		aStem, aNum, aOK := splitNumber(as[i])
+		bStem, bNum, bOK := splitNumber(bs[i])
+		if aOK && bOK && aStem == bStem && aNum != bNum {
+			return aNum < bNum
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
+
+// splitNumber splits a trailing decimal number off s.
+func splitNumber(s string) (stem string, n int, ok bool) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(s[i:])
+	return s[:i], n, err == nil
+}
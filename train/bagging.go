@@ -0,0 +1,62 @@
+package train
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Bagging trains n models on bootstrap resamples of ds (bootstrap
+// aggregating) and returns them as an engine.Ensemble averaging their outputs;
+// set its Combine field to vote instead. Member i is built by builder with a
+// generator seeded by cfg.Seed + i, is trained with the same seed on its
+// resample and validated on its out-of-bag examples, whose scores are
+// returned per member; a member without out-of-bag examples has no scores,
+// rather than a misleading val_loss of 0. Up to workers members train concurrently (0 means 1).
+// If ctx is cancelled or a member fails, the first error is returned.
+func Bagging(ctx context.Context, builder func(r *rand.Rand) engine.Module, ds data.Dataset, n, workers int, cfg TrainConfig) (*engine.Ensemble, []FoldResult, error) {
+	if n < 1 {
+		return nil, nil, fmt.Errorf("train: bagging needs at least one member")
+	}
+	members := make([]engine.Module, n)
+	results := make([]FoldResult, n)
+	errs := make([]error, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				seed := cfg.Seed + int64(i)
+				sample, oob := data.Bootstrap(ds, seed)
+				memberCfg := cfg
+				memberCfg.Seed = seed
+				members[i] = builder(rand.New(rand.NewSource(seed)))
+				t := memberCfg.NewTrainer(members[i], sample, oob)
+				if err := t.Fit(ctx); err != nil {
+					errs[i] = fmt.Errorf("train: bagging member %d: %w", i+1, err)
+					continue
+				}
+				results[i] = FoldResult{Scores: validationScores(t), History: t.History}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, results, err
+		}
+	}
+	return engine.NewEnsemble(members...), results, nil
+}
@@ -0,0 +1,144 @@
+package train
+
+import (
+	"fmt"
+	"math"
+)
+
+// Callback hooks into the stages of Trainer.Fit. Early stopping, logging,
+// checkpointing and user code all plug in through it. Returning an error from
+// any hook aborts Fit with that error. Embed BaseCallback to implement only
+// the hooks you need.
+type Callback interface {
+	OnTrainBegin(t *Trainer) error
+	OnEpochBegin(t *Trainer, epoch int) error
+	OnBatchEnd(t *Trainer, batch int, loss float64) error // loss is the batch loss before the step
+	OnEpochEnd(t *Trainer, stats EpochStats) error
+	OnTrainEnd(t *Trainer) error
+}
+
+// BaseCallback implements every Callback hook as a no-op.
+type BaseCallback struct{}
+
+// OnTrainBegin does nothing.
+func (BaseCallback) OnTrainBegin(t *Trainer) error { return nil }
+
+// OnEpochBegin does nothing.
+func (BaseCallback) OnEpochBegin(t *Trainer, epoch int) error { return nil }
+
+// OnBatchEnd does nothing.
+func (BaseCallback) OnBatchEnd(t *Trainer, batch int, loss float64) error { return nil }
+
+// OnEpochEnd does nothing.
+func (BaseCallback) OnEpochEnd(t *Trainer, stats EpochStats) error { return nil }
+
+// OnTrainEnd does nothing.
+func (BaseCallback) OnTrainEnd(t *Trainer) error { return nil }
+
+// EpochEndFunc adapts a function to a Callback that is called after every epoch.
+type EpochEndFunc func(stats EpochStats)
+
+// OnTrainBegin does nothing.
+func (f EpochEndFunc) OnTrainBegin(t *Trainer) error { return nil }
+
+// OnEpochBegin does nothing.
+func (f EpochEndFunc) OnEpochBegin(t *Trainer, epoch int) error { return nil }
+
+// OnBatchEnd does nothing.
+func (f EpochEndFunc) OnBatchEnd(t *Trainer, batch int, loss float64) error { return nil }
+
+// OnEpochEnd calls f with the epoch statistics.
+func (f EpochEndFunc) OnEpochEnd(t *Trainer, stats EpochStats) error {
+	f(stats)
+	return nil
+}
+
+// OnTrainEnd does nothing.
+func (f EpochEndFunc) OnTrainEnd(t *Trainer) error { return nil }
+
+// EarlyStopping stops training once the monitored quantity has not improved
+// by at least MinDelta for Patience consecutive epochs. A NaN value never
+// counts as an improvement. With RestoreBest the model weights of the best
+// epoch are put back when it stops training, or when training ends otherwise.
+type EarlyStopping struct {
+	BaseCallback
+	Monitor     string // Name of the monitored quantity, see EpochStats.Metric
+	Maximize    bool   // Higher is better (e.g. accuracy) instead of lower (losses)
+	Patience    int
+	MinDelta    float64
+	RestoreBest bool
+
+	Best         float64 // Best value seen so far
+	StoppedEpoch int     // Epoch after which training was stopped, -1 if it was not
+
+	wait int
+	seen bool
+	best *Checkpoint // Weights of the best epoch (RestoreBest only)
+}
+
+// NewEarlyStopping creates an EarlyStopping callback monitoring the
+// validation loss with the given patience.
+func NewEarlyStopping(patience int) *EarlyStopping {
+	return &EarlyStopping{
+		Monitor:      "val_loss",
+		Patience:     patience,
+		StoppedEpoch: -1,
+	}
+}
+
+// OnTrainBegin resets the callback for a new run.
+func (es *EarlyStopping) OnTrainBegin(t *Trainer) error {
+	es.wait, es.seen, es.best, es.StoppedEpoch = 0, false, nil, -1
+	return nil
+}
+
+// OnEpochEnd tracks the monitored quantity and stops the trainer when it stalls.
+func (es *EarlyStopping) OnEpochEnd(t *Trainer, stats EpochStats) error {
+	value, ok := stats.Metric(es.Monitor)
+	if !ok {
+		return fmt.Errorf("train: early stopping monitors %q, which is not available", es.Monitor)
+	}
+
+	delta := value - es.Best
+	if es.Maximize {
+		delta = -delta
+	}
+	if !math.IsNaN(value) && (!es.seen || delta < -es.MinDelta) {
+		es.Best, es.seen, es.wait = value, true, 0
+		if es.RestoreBest {
+			best, err := NewCheckpoint(t.Model, nil, stats.Epoch+1)
+			if err != nil {
+				return err
+			}
+			es.best = best
+		}
+		return nil
+	}
+
+	es.wait++
+	if es.wait >= es.Patience {
+		es.StoppedEpoch = stats.Epoch
+		t.logger().Info("early stopping", es.Monitor, value, "best", es.Best, "patience", es.Patience)
+		t.Stop()
+		// Restore now, as Fit skips OnTrainEnd if it ends with an error.
+		return es.restoreBest(t)
+	}
+	return nil
+}
+
+// OnTrainEnd restores the best weights if RestoreBest is set and they were
+// not restored when training was stopped.
+func (es *EarlyStopping) OnTrainEnd(t *Trainer) error {
+	return es.restoreBest(t)
+}
+
+// restoreBest puts back the weights of the best epoch once.
+func (es *EarlyStopping) restoreBest(t *Trainer) error {
+	if !es.RestoreBest || es.best == nil {
+		return nil
+	}
+	t.logger().Info("restoring best weights", es.Monitor, es.Best)
+	best := es.best
+	es.best = nil
+	return best.Restore(t.Model, nil)
+}
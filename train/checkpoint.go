@@ -0,0 +1,220 @@
+package train
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/optim"
+)
+
+// Checkpoint is a snapshot of a training run: the model's parameter values,
+// keyed by their names from engine.NamedParameters, the optimizer state, the
+// position of the learning-rate scheduler and, when taken by Trainer.Snapshot,
+// the random state of the run.
+type Checkpoint struct {
+	Epoch     int                  `json:"epoch"` // Number of completed epochs
+	Params    map[string][]float64 `json:"params"`
+	Optimizer *optim.State         `json:"optimizer,omitempty"`
+	Scheduler *int                 `json:"scheduler,omitempty"` // Position of an optim.Seeker scheduler
+
+	Seed      *int64            `json:"seed,omitempty"`       // Seed passed to Trainer.SetSeed, if it was called
+	RandDraws uint64            `json:"rand_draws,omitempty"` // Values drawn from the seeded generator so far
+	Loader    *data.LoaderState `json:"loader,omitempty"`     // Seed, epoch and shuffle order of the Loader
+}
+
+// NewCheckpoint captures the current parameters of model and the state of opt.
+// The optimizer state is omitted if opt is nil or not optim.Stateful; it is an
+// error if opt wraps an optimizer that cannot save its state.
+func NewCheckpoint(model engine.Module, opt optim.Optimizer, epoch int) (*Checkpoint, error) {
+	c := Checkpoint{Epoch: epoch, Params: map[string][]float64{}}
+	for _, np := range engine.NamedParameters(model) {
+		values := make([]float64, len(np.Values))
+		for i, v := range np.Values {
+			values[i] = v.Data
+		}
+		c.Params[np.Name] = values
+	}
+	if st, ok := opt.(optim.Stateful); ok {
+		s, err := st.State()
+		if err != nil {
+			return nil, fmt.Errorf("train: saving optimizer state: %w", err)
+		}
+		c.Optimizer = &s
+	}
+	return &c, nil
+}
+
+// Save writes the checkpoint to path as JSON.
+func (c *Checkpoint) Save(path string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("train: encoding checkpoint: %w", err)
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// LoadCheckpoint reads a checkpoint written by Save.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Checkpoint
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("train: decoding checkpoint %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Restore copies the saved parameter values into model and, if both the
+// checkpoint and opt have one, the saved optimizer state into opt. The model
+// must have the architecture the checkpoint was taken from.
+func (c *Checkpoint) Restore(model engine.Module, opt optim.Optimizer) error {
+	named := engine.NamedParameters(model)
+	if len(named) != len(c.Params) {
+		return fmt.Errorf("train: checkpoint has %d parameter tensors, model has %d", len(c.Params), len(named))
+	}
+	for _, np := range named {
+		values, ok := c.Params[np.Name]
+		if !ok {
+			return fmt.Errorf("train: checkpoint has no parameter %q", np.Name)
+		}
+		if len(values) != len(np.Values) {
+			return fmt.Errorf("train: parameter %q has %d values in checkpoint, %d in model", np.Name, len(values), len(np.Values))
+		}
+	}
+	for _, np := range named {
+		for i, v := range c.Params[np.Name] {
+			np.Values[i].Data = v
+		}
+	}
+
+	if st, ok := opt.(optim.Stateful); ok && c.Optimizer != nil {
+		return st.LoadState(*c.Optimizer)
+	}
+	return nil
+}
+
+// ModelCheckpoint is a Callback that saves a Checkpoint whenever the monitored quantity improves
+// on its best value so far. Path is a template in which "{epoch}" is replaced
+// by the epoch index and "{metric}" by the monitored value, e.g.
+// "model-{epoch}-{metric}.json"; a constant path keeps only the best model.
+type ModelCheckpoint struct {
+	BaseCallback
+	Path     string
+	Monitor  string // Name of the monitored quantity, see EpochStats.Metric
+	Maximize bool   // Higher is better (e.g. accuracy) instead of lower (losses)
+
+	Best     float64 // Best value seen so far
+	BestPath string  // File of the best checkpoint saved so far
+	seen     bool
+}
+
+// NewModelCheckpoint creates a ModelCheckpoint writing to the path template
+// whenever the validation loss reaches a new minimum.
+func NewModelCheckpoint(path string) *ModelCheckpoint {
+	return &ModelCheckpoint{
+		Path:    path,
+		Monitor: "val_loss",
+	}
+}
+
+// OnEpochEnd saves a checkpoint of the trainer's model and optimizer if the
+// monitored quantity improved during the epoch.
+func (mc *ModelCheckpoint) OnEpochEnd(t *Trainer, stats EpochStats) error {
+	value, ok := stats.Metric(mc.Monitor)
+	if !ok {
+		return fmt.Errorf("train: checkpoint monitors %q, which is not available", mc.Monitor)
+	}
+	improved := !mc.seen || (mc.Maximize && value > mc.Best) || (!mc.Maximize && value < mc.Best)
+	if !improved || math.IsNaN(value) {
+		return nil
+	}
+
+	path := strings.NewReplacer(
+		"{epoch}", strconv.Itoa(stats.Epoch),
+		"{metric}", strconv.FormatFloat(value, 'f', 4, 64),
+	).Replace(mc.Path)
+	c, err := t.Snapshot()
+	if err != nil {
+		return err
+	}
+	if err := c.Save(path); err != nil {
+		return fmt.Errorf("train: saving checkpoint: %w", err)
+	}
+	mc.Best, mc.BestPath, mc.seen = value, path, true
+	t.logger().Info("checkpoint saved", "path", path, mc.Monitor, value, "epoch", stats.Epoch)
+	return nil
+}
+
+// Snapshot captures the current state of the training run as a Checkpoint,
+// including the Loader's state and the position of the generator installed by
+// SetSeed.
+func (t *Trainer) Snapshot() (*Checkpoint, error) {
+	c, err := NewCheckpoint(t.Model, t.Optimizer, t.completed)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := t.Scheduler.(optim.Seeker); ok {
+		pos := s.Position()
+		c.Scheduler = &pos
+	}
+	if t.Loader != nil {
+		ls := t.Loader.State()
+		c.Loader = &ls
+	}
+	if t.source != nil {
+		seed := t.source.seed
+		c.Seed, c.RandDraws = &seed, t.source.draws
+	}
+	return c, nil
+}
+
+// Resume restores the model weights, optimizer state, scheduler position,
+// epoch counter and random state from the checkpoint at path, so that the next
+// Fit continues the run with the remaining epochs up to Epochs exactly as if it
+// had never been interrupted: the Loader serves the same examples in the same
+// order and the model's stochastic modules draw the same values.
+func (t *Trainer) Resume(path string) error {
+	c, err := LoadCheckpoint(path)
+	if err != nil {
+		return err
+	}
+	if err := t.restore(c); err != nil {
+		return err
+	}
+	t.logger().Info("training resumed", "path", path, "epoch", c.Epoch)
+	return nil
+}
+
+// restore applies a checkpoint to the model, optimizer, scheduler, epoch
+// counter, Loader and seeded generator.
+func (t *Trainer) restore(c *Checkpoint) error {
+	if err := c.Restore(t.Model, t.Optimizer); err != nil {
+		return err
+	}
+	if c.Scheduler != nil {
+		s, ok := t.Scheduler.(optim.Seeker)
+		if !ok {
+			return fmt.Errorf("train: checkpoint has a scheduler position but %T cannot seek", t.Scheduler)
+		}
+		s.Seek(*c.Scheduler)
+	}
+	if c.Seed != nil {
+		if t.source == nil {
+			t.SetSeed(*c.Seed)
+		}
+		t.source.seek(*c.Seed, c.RandDraws)
+	}
+	if c.Loader != nil && t.Loader != nil {
+		t.Loader.LoadState(*c.Loader) // After SetSeed, which reseeds the Loader
+	}
+	t.completed = c.Epoch
+	return nil
+}
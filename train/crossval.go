@@ -0,0 +1,130 @@
+package train
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/losses"
+	"github.com/Rmehta-sudo/neural-net/metrics"
+	"github.com/Rmehta-sudo/neural-net/optim"
+)
+
+// TrainConfig describes how to train a model, independently of any model or
+// dataset, so the same recipe can be applied repeatedly (to every fold of a
+// cross-validation, to every trial of a hyperparameter search, ...).
+type TrainConfig struct {
+	Loss         losses.Loss
+	NewOptimizer func(params []*engine.Value) optim.Optimizer
+	NewMetrics   func() []metrics.Metric // Optional; called once per trainer so metric state is not shared
+	Epochs       int
+	BatchSize    int // 0 trains full-batch
+	Accumulate   int // Batches per optimizer step, see Trainer.AccumulateSteps
+	Prefetch     int // Batches prepared ahead in the background, see Trainer.Prefetch
+	Reduction    losses.Reduction
+	Seed         int64 // Seeds the loader's shuffling and the model's stochastic modules (see Trainer.SetSeed)
+}
+
+// NewTrainer builds a silent Trainer for model on the given training and
+// (optional) validation data following the configuration.
+func (cfg TrainConfig) NewTrainer(model engine.Module, train, val data.Dataset) *Trainer {
+	t := NewTrainer(model, cfg.Loss, cfg.NewOptimizer(model.Parameters()))
+	t.Loader = data.NewLoader(train, cfg.BatchSize)
+	t.SetSeed(cfg.Seed)
+	t.Validation = val
+	t.Epochs = cfg.Epochs
+	t.Reduction = cfg.Reduction
+	t.AccumulateSteps = cfg.Accumulate
+	t.Prefetch = cfg.Prefetch
+	t.Progress = Silent{}
+	if cfg.NewMetrics != nil {
+		t.Metrics = cfg.NewMetrics()
+	}
+	return t
+}
+
+// FoldResult holds the outcome of training on one fold.
+type FoldResult struct {
+	Scores  map[string]float64 // Validation scores after the last epoch: "val_loss" and "val_<metric>"
+	History History
+}
+
+// CVResult aggregates the folds of a cross-validation.
+type CVResult struct {
+	Folds []FoldResult
+	Mean  map[string]float64 // Mean of every validation score over the folds
+	Std   map[string]float64 // Population standard deviation of every score over the folds
+}
+
+// String lists the mean and standard deviation of every score.
+func (r CVResult) String() string {
+	names := make([]string, 0, len(r.Mean))
+	for name := range r.Mean {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s := fmt.Sprintf("%d-fold cross-validation:", len(r.Folds))
+	for _, name := range names {
+		s += fmt.Sprintf(" %s=%.4f±%.4f", name, r.Mean[name], r.Std[name])
+	}
+	return s
+}
+
+// CrossValidate trains a fresh model from builder on each of k folds of ds
+// (split with cfg.Seed), evaluates it on the held-out fold and aggregates the
+// validation scores. It returns early with the context's error if ctx is cancelled.
+func CrossValidate(ctx context.Context, builder func() engine.Module, ds data.Dataset, k int, cfg TrainConfig) (CVResult, error) {
+	var result CVResult
+	if k < 2 || k > ds.Len() {
+		return result, fmt.Errorf("train: cannot split %d examples into %d folds", ds.Len(), k)
+	}
+	for i, fold := range data.KFold(ds, k, cfg.Seed) {
+		t := cfg.NewTrainer(builder(), fold.Train, fold.Val)
+		if err := t.Fit(ctx); err != nil {
+			return result, fmt.Errorf("train: fold %d: %w", i+1, err)
+		}
+		result.Folds = append(result.Folds, FoldResult{Scores: validationScores(t), History: t.History})
+	}
+	result.Mean, result.Std = aggregate(result.Folds)
+	return result, nil
+}
+
+// validationScores returns "val_loss" and every "val_" metric of the last
+// epoch of t, or no "val_loss" if t had no validation examples.
+func validationScores(t *Trainer) map[string]float64 {
+	last, _ := t.History.Last()
+	scores := map[string]float64{}
+	if last.hasVal {
+		scores["val_loss"] = last.ValLoss
+	}
+	for name, v := range last.Metrics {
+		if strings.HasPrefix(name, "val_") {
+			scores[name] = v
+		}
+	}
+	return scores
+}
+
+// aggregate computes the mean and standard deviation of every fold score.
+func aggregate(folds []FoldResult) (mean, std map[string]float64) {
+	mean, std = map[string]float64{}, map[string]float64{}
+	for _, f := range folds {
+		for name, v := range f.Scores {
+			mean[name] += v / float64(len(folds))
+		}
+	}
+	for _, f := range folds {
+		for name, v := range f.Scores {
+			d := v - mean[name]
+			std[name] += d * d / float64(len(folds))
+		}
+	}
+	for name := range std {
+		std[name] = math.Sqrt(std[name])
+	}
+	return mean, std
+}
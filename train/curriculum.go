@@ -0,0 +1,92 @@
+package train
+
+import (
+	"fmt"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Curriculum is a Callback implementing curriculum learning: it installs a
+// data.CurriculumSampler in the trainer's Loader and, before every epoch, sets
+// the share of (easiest) examples to train on from Pacing. If Score is set,
+// the difficulties are recomputed from the current model every RescoreEvery
+// epochs, so the curriculum follows what the model finds hard (self-paced
+// learning, e.g. with ExampleLoss).
+type Curriculum struct {
+	BaseCallback
+	Sampler *data.CurriculumSampler
+	Pacing  func(epoch int) float64 // Fraction of the examples used in an epoch
+
+	Score        func(t *Trainer, ex data.Example) float64 // Optional difficulty of an example under the current model
+	RescoreEvery int                                       // Epochs between rescorings; 0 means every epoch
+}
+
+// NewCurriculum creates a curriculum over examples with fixed difficulty
+// scores (one per example of the training dataset, lower is easier).
+func NewCurriculum(scores []float64, pacing func(epoch int) float64) *Curriculum {
+	return &Curriculum{
+		Sampler: data.NewCurriculumSampler(scores),
+		Pacing:  pacing,
+	}
+}
+
+// NewSelfPacedCurriculum creates a curriculum scoring examples by their
+// current loss (see ExampleLoss), initially scored with the untrained model.
+func NewSelfPacedCurriculum(pacing func(epoch int) float64) *Curriculum {
+	return &Curriculum{
+		Sampler: data.NewCurriculumSampler(nil),
+		Pacing:  pacing,
+		Score:   ExampleLoss,
+	}
+}
+
+// LinearPacing starts with the given fraction of the examples and grows it
+// linearly to all of them at epoch epochs.
+func LinearPacing(start float64, epochs int) func(epoch int) float64 {
+	return func(epoch int) float64 {
+		if epoch >= epochs {
+			return 1
+		}
+		return start + (1-start)*float64(epoch)/float64(epochs)
+	}
+}
+
+// ExampleLoss scores an example by the loss of the trainer's model on it.
+func ExampleLoss(t *Trainer, ex data.Example) float64 {
+	preds := t.Model.Output(engine.ToValue1D(ex.Input))
+	return t.Loss.Compute(preds, engine.ToValue1D(ex.Target)).Data
+}
+
+// OnTrainBegin installs the sampler in the trainer's Loader.
+func (c *Curriculum) OnTrainBegin(t *Trainer) error {
+	if c.Score == nil && len(c.Sampler.Scores) != t.Loader.Dataset.Len() {
+		return fmt.Errorf("train: curriculum has %d scores for %d examples", len(c.Sampler.Scores), t.Loader.Dataset.Len())
+	}
+	t.Loader.Sampler = c.Sampler
+	return nil
+}
+
+// OnEpochBegin rescores the examples if due and sets the fraction of the epoch.
+func (c *Curriculum) OnEpochBegin(t *Trainer, epoch int) error {
+	if c.Score != nil && (c.RescoreEvery <= 0 || epoch%c.RescoreEvery == 0 || len(c.Sampler.Scores) == 0) {
+		c.rescore(t)
+	}
+	c.Sampler.Fraction = c.Pacing(epoch)
+	t.logger().Debug("curriculum", "epoch", epoch, "fraction", c.Sampler.Fraction, "examples", c.Sampler.Len())
+	return nil
+}
+
+// rescore recomputes the difficulty of every training example with the model
+// in evaluation mode.
+func (c *Curriculum) rescore(t *Trainer) {
+	engine.SetTraining(t.Model, false)
+	defer engine.SetTraining(t.Model, true)
+
+	ds := t.Loader.Dataset
+	scores := make([]float64, ds.Len())
+	for i := range scores {
+		scores[i] = c.Score(t, ds.Get(i))
+	}
+	c.Sampler.Scores = scores
+}
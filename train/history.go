@@ -0,0 +1,123 @@
+package train
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// History records the statistics of every epoch trained by a Trainer.
+type History struct {
+	Epochs []EpochStats
+}
+
+// Add appends the statistics of one epoch.
+func (h *History) Add(stats EpochStats) {
+	h.Epochs = append(h.Epochs, stats)
+}
+
+// Last returns the statistics of the most recent epoch and false if there are none.
+func (h *History) Last() (EpochStats, bool) {
+	if len(h.Epochs) == 0 {
+		return EpochStats{}, false
+	}
+	return h.Epochs[len(h.Epochs)-1], true
+}
+
+// Series returns the named quantity (see EpochStats.Metric) for every epoch
+// in which it is available, e.g. for plotting a learning curve.
+func (h *History) Series(name string) []float64 {
+	var out []float64
+	for _, s := range h.Epochs {
+		if v, ok := s.Metric(name); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// columns returns the quantity names present in the history, in a stable
+// order: loss, val_loss, then the metrics alphabetically.
+func (h *History) columns() []string {
+	cols := []string{"loss"}
+	seen := map[string]bool{}
+	for _, s := range h.Epochs {
+		if s.hasVal && !seen["val_loss"] {
+			seen["val_loss"] = true
+			cols = append(cols, "val_loss")
+		}
+	}
+	var names []string
+	for _, s := range h.Epochs {
+		for name := range s.Metrics {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return append(cols, names...)
+}
+
+// ToCSV writes one row per epoch with the columns epoch, the losses and
+// metrics, lr and seconds. Quantities missing in an epoch are left empty.
+func (h *History) ToCSV(w io.Writer) error {
+	cols := h.columns()
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(append([]string{"epoch"}, cols...), "lr", "seconds")); err != nil {
+		return err
+	}
+	for _, s := range h.Epochs {
+		row := []string{strconv.Itoa(s.Epoch)}
+		for _, col := range cols {
+			v, ok := s.Metric(col)
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		row = append(row,
+			strconv.FormatFloat(s.LR, 'g', -1, 64),
+			strconv.FormatFloat(s.Duration.Seconds(), 'g', -1, 64))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// epochRecord is the JSON form of an epoch.
+type epochRecord struct {
+	Epoch   int                `json:"epoch"`
+	Loss    float64            `json:"loss"`
+	ValLoss *float64           `json:"val_loss,omitempty"`
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+	LR      float64            `json:"lr"`
+	Seconds float64            `json:"seconds"`
+}
+
+// ToJSON writes the history as a JSON array with one object per epoch.
+func (h *History) ToJSON(w io.Writer) error {
+	records := make([]epochRecord, len(h.Epochs))
+	for i, s := range h.Epochs {
+		records[i] = epochRecord{
+			Epoch:   s.Epoch,
+			Loss:    s.Loss,
+			Metrics: s.Metrics,
+			LR:      s.LR,
+			Seconds: s.Duration.Seconds(),
+		}
+		if s.hasVal {
+			v := s.ValLoss
+			records[i].ValLoss = &v
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
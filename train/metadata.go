@@ -0,0 +1,48 @@
+package train
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Metadata describes the training run for saving with the model (see
+// engine.MLP.SaveWithMetadata): the trainer's configuration, a fingerprint
+// of the training dataset, the losses and metrics of the last epoch and when
+// Fit started and last finished.
+func (t *Trainer) Metadata() *engine.ModelMetadata {
+	meta := &engine.ModelMetadata{
+		Training: map[string]string{
+			"epochs":        strconv.Itoa(t.completed),
+			"loss":          fmt.Sprintf("%T", t.Loss),
+			"optimizer":     fmt.Sprintf("%T", t.Optimizer),
+			"learning_rate": strconv.FormatFloat(t.Optimizer.LearningRate(), 'g', -1, 64),
+			"reduction":     fmt.Sprint(t.Reduction),
+		},
+		TrainingStarted:  t.started,
+		TrainingFinished: t.finished,
+	}
+	if t.AccumulateSteps > 1 {
+		meta.Training["accumulate_steps"] = strconv.Itoa(t.AccumulateSteps)
+	}
+	if t.Scheduler != nil {
+		meta.Training["scheduler"] = fmt.Sprintf("%T", t.Scheduler)
+	}
+	if t.Loader != nil {
+		meta.Training["batch_size"] = strconv.Itoa(t.Loader.BatchSize)
+		meta.Training["shuffle"] = strconv.FormatBool(t.Loader.Shuffle)
+		meta.DatasetFingerprint = data.Fingerprint(t.Loader.Dataset)
+	}
+	if last, ok := t.History.Last(); ok {
+		meta.Metrics = map[string]float64{"loss": last.Loss}
+		if last.hasVal {
+			meta.Metrics["val_loss"] = last.ValLoss
+		}
+		for name, v := range last.Metrics {
+			meta.Metrics[name] = v
+		}
+	}
+	return meta
+}
@@ -0,0 +1,192 @@
+package train
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Rmehta-sudo/neural-net/losses"
+)
+
+// Privacy configures differentially private training (DP-SGD): the gradient
+// of every example is clipped to an L2 norm of at most ClipNorm, and Gaussian
+// noise with standard deviation NoiseMultiplier * ClipNorm is added to their
+// sum before the optimizer step. No single example can then influence an
+// update by more than the noise hides. Set Trainer.Privacy to enable it; the
+// privacy spent so far is tracked by Accountant and reported as the
+// "epsilon" metric of every epoch.
+//
+// Per-example gradients need one backward pass per example, so private
+// training is slower than ordinary training by about the batch size.
+type Privacy struct {
+	ClipNorm        float64    // Bound on the L2 norm of every example's gradient
+	NoiseMultiplier float64    // Noise standard deviation relative to ClipNorm
+	Delta           float64    // Target delta of the reported (epsilon, delta) guarantee
+	Rand            *rand.Rand // Source of the noise; nil uses the global math/rand source
+
+	Accountant PrivacyAccountant
+}
+
+// NewPrivacy creates a DP-SGD configuration with the given clipping norm and
+// noise multiplier, reporting epsilon for delta = 1e-5.
+func NewPrivacy(clipNorm, noiseMultiplier float64) *Privacy {
+	return &Privacy{
+		ClipNorm:        clipNorm,
+		NoiseMultiplier: noiseMultiplier,
+		Delta:           1e-5,
+	}
+}
+
+// validate checks the configuration before training.
+func (p *Privacy) validate() error {
+	if !(p.ClipNorm > 0) {
+		return fmt.Errorf("train: privacy clipping norm %v must be positive", p.ClipNorm)
+	}
+	if !(p.NoiseMultiplier >= 0) {
+		return fmt.Errorf("train: privacy noise multiplier %v must not be negative", p.NoiseMultiplier)
+	}
+	return nil
+}
+
+// Epsilon returns the privacy spent so far for the configured Delta.
+func (p *Privacy) Epsilon() float64 {
+	return p.Accountant.Epsilon(p.Delta)
+}
+
+// PrivacyAccountant tracks the privacy loss of repeated DP-SGD steps with the
+// Rényi differential privacy (RDP) analysis of the sampled Gaussian mechanism.
+// Each step is assumed to sample its batch as a random fraction of the data;
+// shuffled batches are the usual approximation of that.
+type PrivacyAccountant struct {
+	rdp []float64 // Accumulated RDP epsilon at every order of rdpOrders
+
+	// RDP of the last kind of step, which usually repeats for a whole run
+	lastRate, lastNoise float64
+	lastRDP             []float64
+}
+
+// rdpOrders are the Rényi orders the guarantee is optimized over.
+var rdpOrders = func() []int {
+	var orders []int
+	for a := 2; a <= 64; a++ {
+		orders = append(orders, a)
+	}
+	return append(orders, 80, 96, 128, 192, 256)
+}()
+
+// Step records one step sampling the given fraction of the dataset, with the
+// given noise multiplier.
+func (a *PrivacyAccountant) Step(sampleRate, noiseMultiplier float64) {
+	if a.rdp == nil {
+		a.rdp = make([]float64, len(rdpOrders))
+	}
+	if a.lastRDP == nil || sampleRate != a.lastRate || noiseMultiplier != a.lastNoise {
+		a.lastRate, a.lastNoise = sampleRate, noiseMultiplier
+		a.lastRDP = make([]float64, len(rdpOrders))
+		for i, order := range rdpOrders {
+			a.lastRDP[i] = sampledGaussianRDP(sampleRate, noiseMultiplier, order)
+		}
+	}
+	for i := range a.rdp {
+		a.rdp[i] += a.lastRDP[i]
+	}
+}
+
+// Epsilon converts the accumulated RDP into an (epsilon, delta) guarantee,
+// minimizing over the orders. It is 0 before the first step.
+func (a *PrivacyAccountant) Epsilon(delta float64) float64 {
+	if a.rdp == nil {
+		return 0
+	}
+	eps := math.Inf(1)
+	for i, order := range rdpOrders {
+		eps = math.Min(eps, a.rdp[i]+math.Log(1/delta)/float64(order-1))
+	}
+	return eps
+}
+
+// sampledGaussianRDP returns the RDP epsilon at an integer order of one step
+// of the Gaussian mechanism with noise multiplier sigma applied to a batch
+// sampled with rate q (Mironov, Talwar and Zhang, 2019):
+// log(sum_k C(order, k) (1-q)^(order-k) q^k exp((k^2 - k) / (2 sigma^2))) / (order - 1).
+func sampledGaussianRDP(q, sigma float64, order int) float64 {
+	if q == 0 {
+		return 0
+	}
+	if sigma == 0 {
+		return math.Inf(1)
+	}
+	if q >= 1 {
+		return float64(order) / (2 * sigma * sigma)
+	}
+	terms := make([]float64, order+1)
+	for k := 0; k <= order; k++ {
+		logBinom := lgamma(order+1) - lgamma(k+1) - lgamma(order-k+1)
+		terms[k] = logBinom + float64(order-k)*math.Log1p(-q) + float64(k)*math.Log(q) +
+			float64(k*k-k)/(2*sigma*sigma)
+	}
+	return logSumExp(terms) / float64(order-1)
+}
+
+// lgamma returns log(Gamma(n)).
+func lgamma(n int) float64 {
+	v, _ := math.Lgamma(float64(n))
+	return v
+}
+
+// logSumExp returns log(sum(exp(xs))) computed stably.
+func logSumExp(xs []float64) float64 {
+	m := math.Inf(-1)
+	for _, x := range xs {
+		m = math.Max(m, x)
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += math.Exp(x - m)
+	}
+	return m + math.Log(sum)
+}
+
+// privateBackward computes the DP-SGD gradient of a group of micro-batches,
+// treated as one batch, into the parameters and returns the loss.
+func (t *Trainer) privateBackward(group []preparedBatch, record bool) float64 {
+	p := t.Privacy
+	params := t.Model.Parameters()
+	grads := make([]float64, len(params))
+	total, n := 0.0, 0
+	for _, batch := range group {
+		for j, ex := range batch.Batch {
+			preds := t.Model.Output(batch.inputs[j])
+			loss := t.Loss.Compute(preds, batch.targets[j])
+			if record {
+				t.updateMetrics(preds, ex.Target)
+			}
+			loss.FullBackward()
+			total += loss.Data
+			n++
+
+			norm := 0.0
+			for _, param := range params {
+				norm += param.Grad * param.Grad
+			}
+			scale := math.Min(1, p.ClipNorm/(math.Sqrt(norm)+1e-12))
+			for i, param := range params {
+				grads[i] += scale * param.Grad
+			}
+		}
+	}
+
+	stddev := p.NoiseMultiplier * p.ClipNorm
+	div := 1.0
+	if t.Reduction == losses.Mean {
+		div = float64(n)
+	}
+	normal := rand.NormFloat64
+	if p.Rand != nil {
+		normal = p.Rand.NormFloat64
+	}
+	for i, param := range params {
+		param.Grad = (grads[i] + normal()*stddev) / div
+	}
+	return total / div
+}
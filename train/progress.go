@@ -0,0 +1,126 @@
+package train
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProgressReporter displays the progress of Trainer.Fit.
+type ProgressReporter interface {
+	Start(epochs, batchesPerEpoch int)           // Before the first epoch
+	Batch(epoch, batch int, runningLoss float64) // After every batch; runningLoss averages the epoch so far
+	EpochDone(stats EpochStats)                  // After every epoch, including validation
+	Finish()                                     // After training ends, also on errors
+}
+
+// Silent is a ProgressReporter that prints nothing.
+type Silent struct{}
+
+// Start does nothing.
+func (Silent) Start(epochs, batchesPerEpoch int) {}
+
+// Batch does nothing.
+func (Silent) Batch(epoch, batch int, runningLoss float64) {}
+
+// EpochDone does nothing.
+func (Silent) EpochDone(stats EpochStats) {}
+
+// Finish does nothing.
+func (Silent) Finish() {}
+
+// formatStats renders the losses and metrics of an epoch as "name=value" pairs.
+func formatStats(stats EpochStats) string {
+	parts := []string{fmt.Sprintf("loss=%.4f", stats.Loss)}
+	if v, ok := stats.Metric("val_loss"); ok {
+		parts = append(parts, fmt.Sprintf("val_loss=%.4f", v))
+	}
+	names := make([]string, 0, len(stats.Metrics))
+	for name := range stats.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%.4f", name, stats.Metrics[name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ProgressBar redraws a single terminal line per epoch showing a bar over the
+// batches, the running loss and the estimated time left in the epoch, then
+// leaves a summary line with the epoch's losses and metrics.
+type ProgressBar struct {
+	W     io.Writer
+	Width int // Width of the bar in characters
+
+	epochs, batches int
+	epochStart      time.Time
+}
+
+// NewProgressBar creates a ProgressBar writing to w.
+func NewProgressBar(w io.Writer) *ProgressBar {
+	return &ProgressBar{W: w, Width: 30}
+}
+
+// Start records the size of the run.
+func (pb *ProgressBar) Start(epochs, batchesPerEpoch int) {
+	pb.epochs, pb.batches = epochs, batchesPerEpoch
+	pb.epochStart = time.Now()
+}
+
+// Batch redraws the bar of the current epoch.
+func (pb *ProgressBar) Batch(epoch, batch int, runningLoss float64) {
+	done := batch + 1
+	filled := pb.Width * done / max(1, pb.batches)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", max(0, pb.Width-filled))
+	elapsed := time.Since(pb.epochStart)
+	eta := time.Duration(float64(elapsed) / float64(done) * float64(max(0, pb.batches-done)))
+	fmt.Fprintf(pb.W, "\rEpoch %d/%d [%s] %d/%d loss=%.4f ETA %s ",
+		epoch+1, pb.epochs, bar, done, pb.batches, runningLoss, eta.Round(time.Millisecond))
+}
+
+// EpochDone replaces the bar by a summary line.
+func (pb *ProgressBar) EpochDone(stats EpochStats) {
+	fmt.Fprintf(pb.W, "\r\033[KEpoch %d/%d %s (%s)\n",
+		stats.Epoch+1, pb.epochs, formatStats(stats), stats.Duration.Round(time.Millisecond))
+	pb.epochStart = time.Now() // The next epoch starts now
+}
+
+// Finish does nothing; every epoch already ends its line.
+func (pb *ProgressBar) Finish() {}
+
+// EpochLog prints one plain line every Every epochs (and after the last one),
+// suitable for log files and non-interactive output.
+type EpochLog struct {
+	W     io.Writer
+	Every int // Print every Every-th epoch; 0 or 1 prints all
+
+	epochs int
+}
+
+// NewEpochLog creates an EpochLog writing every epoch to w.
+func NewEpochLog(w io.Writer) *EpochLog {
+	return &EpochLog{W: w, Every: 1}
+}
+
+// Start records the number of epochs.
+func (el *EpochLog) Start(epochs, batchesPerEpoch int) {
+	el.epochs = epochs
+}
+
+// Batch does nothing.
+func (el *EpochLog) Batch(epoch, batch int, runningLoss float64) {}
+
+// EpochDone prints the epoch summary if it is due.
+func (el *EpochLog) EpochDone(stats EpochStats) {
+	if el.Every > 1 && stats.Epoch%el.Every != 0 && stats.Epoch != el.epochs-1 {
+		return
+	}
+	fmt.Fprintf(el.W, "epoch %d/%d: %s (%s)\n",
+		stats.Epoch+1, el.epochs, formatStats(stats), stats.Duration.Round(time.Microsecond))
+}
+
+// Finish does nothing.
+func (el *EpochLog) Finish() {}
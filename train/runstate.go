@@ -0,0 +1,155 @@
+package train
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/Rmehta-sudo/neural-net/losses"
+)
+
+// countingSource is the seeded random source behind Trainer.SetSeed. It counts
+// the values drawn so its position can be saved and later restored by
+// reseeding and replaying the same number of draws.
+type countingSource struct {
+	src   rand.Source64
+	seed  int64
+	draws uint64
+}
+
+// newCountingSource creates a source seeded with seed.
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64), seed: seed}
+}
+
+// Int63 draws a value and counts it.
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+// Uint64 draws a value and counts it.
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+// Seed reseeds the source and resets the count.
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.seed, s.draws = seed, 0
+}
+
+// seek reseeds the source and skips the given number of draws.
+func (s *countingSource) seek(seed int64, draws uint64) {
+	s.Seed(seed)
+	for s.draws < draws {
+		s.Int63()
+	}
+}
+
+// RunConfig records the settings of a training run that a RunState depends on.
+// Loss, Optimizer and Model are type names: they identify the components
+// without being able to recreate them.
+type RunConfig struct {
+	Epochs     int              `json:"epochs"`
+	BatchSize  int              `json:"batch_size"`
+	Shuffle    bool             `json:"shuffle"`
+	DropLast   bool             `json:"drop_last"`
+	Accumulate int              `json:"accumulate_steps"`
+	Reduction  losses.Reduction `json:"reduction"`
+	Loss       string           `json:"loss"`
+	Optimizer  string           `json:"optimizer"`
+	Model      string           `json:"model"`
+}
+
+// RunState is everything that determines how a training run continues from a
+// point: the checkpoint (weights, optimizer state, scheduler position, epoch,
+// the seed and position of the generator installed by SetSeed and the
+// loader's seed and shuffle order) and the configuration. Restoring it with
+// RestoreRunState and calling Fit replays the rest of the run bit-for-bit,
+// provided the loader is seeded and the model draws no randomness from the
+// global math/rand source, whose state cannot be captured.
+type RunState struct {
+	Checkpoint *Checkpoint `json:"checkpoint"`
+	Config     RunConfig   `json:"config"`
+}
+
+// CaptureRunState records the current state of t's training run.
+func CaptureRunState(t *Trainer) (*RunState, error) {
+	if t.Loader == nil {
+		return nil, fmt.Errorf("train: cannot capture a run without a Loader")
+	}
+	c, err := t.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &RunState{Checkpoint: c, Config: t.runConfig()}, nil
+}
+
+// RestoreRunState returns t to a state captured by CaptureRunState. t must
+// have been set up with the same kind of model, loss and optimizer; the
+// numeric settings (epochs, batch size, shuffling, reduction) are restored
+// from the state.
+func RestoreRunState(t *Trainer, s *RunState) error {
+	if t.Loader == nil {
+		return fmt.Errorf("train: cannot restore a run without a Loader")
+	}
+	cfg := t.runConfig()
+	if cfg.Loss != s.Config.Loss || cfg.Optimizer != s.Config.Optimizer || cfg.Model != s.Config.Model {
+		return fmt.Errorf("train: run state is for %s/%s/%s, trainer has %s/%s/%s",
+			s.Config.Model, s.Config.Loss, s.Config.Optimizer, cfg.Model, cfg.Loss, cfg.Optimizer)
+	}
+	if err := t.restore(s.Checkpoint); err != nil {
+		return err
+	}
+
+	t.Epochs = s.Config.Epochs
+	t.Reduction = s.Config.Reduction
+	t.AccumulateSteps = s.Config.Accumulate
+	t.Loader.BatchSize = s.Config.BatchSize
+	t.Loader.Shuffle = s.Config.Shuffle
+	t.Loader.DropLast = s.Config.DropLast
+	return nil
+}
+
+// runConfig describes the trainer's current settings.
+func (t *Trainer) runConfig() RunConfig {
+	return RunConfig{
+		Epochs:     t.Epochs,
+		BatchSize:  t.Loader.BatchSize,
+		Shuffle:    t.Loader.Shuffle,
+		DropLast:   t.Loader.DropLast,
+		Accumulate: t.AccumulateSteps,
+		Reduction:  t.Reduction,
+		Loss:       fmt.Sprintf("%T", t.Loss),
+		Optimizer:  fmt.Sprintf("%T", t.Optimizer),
+		Model:      fmt.Sprintf("%T", t.Model),
+	}
+}
+
+// Save writes the run state to path as JSON.
+func (s *RunState) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("train: encoding run state: %w", err)
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// LoadRunState reads a run state written by RunState.Save.
+func LoadRunState(path string) (*RunState, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s RunState
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("train: decoding run state %s: %w", path, err)
+	}
+	if s.Checkpoint == nil {
+		return nil, fmt.Errorf("train: run state %s has no checkpoint", path)
+	}
+	return &s, nil
+}
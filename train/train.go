@@ -0,0 +1,501 @@
+// Package train provides a Trainer that runs the usual forward, backward and
+// optimizer-step loop over a dataset, so models built from engine modules can
+// be fitted with a single call instead of a hand-written loop.
+package train
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/losses"
+	"github.com/Rmehta-sudo/neural-net/metrics"
+	"github.com/Rmehta-sudo/neural-net/optim"
+)
+
+// Trainer fits a model to the examples of a data.Loader with mini-batch
+// gradient descent. Every epoch walks through the loader's batches; for each
+// batch the per-example losses are combined by Reduction (by default their
+// mean, so the step size does not depend on the batch size), backpropagated
+// with FullBackward and applied with the optimizer (through optim.StepWith,
+// so closure-based optimizers such as SAM work too). A Scheduler, if set, is
+// stepped after every epoch. Callbacks are invoked at every stage of training.
+type Trainer struct {
+	Model     engine.Module
+	Loss      losses.Loss
+	Optimizer optim.Optimizer
+	Scheduler optim.Scheduler // Optional learning-rate schedule, stepped once per epoch
+
+	Loader     *data.Loader // Training examples, batch size, shuffling and last-batch handling
+	Validation data.Dataset // Optional held-out examples, evaluated after every epoch
+	Epochs     int
+	Reduction  losses.Reduction // Mean (default) or Sum of the per-example losses in a batch
+
+	// AccumulateSteps, if above 1, combines the gradients of that many
+	// consecutive batches (micro-batches) before each optimizer step, which
+	// simulates a batch AccumulateSteps times larger while only building the
+	// graph of one micro-batch at a time.
+	AccumulateSteps int
+
+	// Prefetch, if positive, is the number of batches loaded, transformed and
+	// converted to Values ahead of time on background goroutines (see
+	// data.Prefetch), so slow datasets or augmentation do not stall training.
+	Prefetch int
+
+	Privacy *Privacy // Optional differentially private training (DP-SGD)
+
+	Metrics   []metrics.Metric // Reported on the training data and the Validation dataset every epoch
+	Callbacks []Callback       // Invoked in order at every stage of training
+
+	Progress ProgressReporter // Displays training progress; use Silent{} for no output
+	Logger   *slog.Logger     // Optional structured log of training events; nil discards them
+	History  History          // Statistics of every epoch trained so far
+
+	// InterruptCheckpoint, if set, is the path Fit saves a Snapshot to when
+	// its context is cancelled, so the run can be continued with Resume.
+	InterruptCheckpoint string
+
+	completed int             // Number of completed epochs
+	stop      bool            // Set by Stop to end training after the current epoch
+	source    *countingSource // Generator installed in the model by SetSeed
+
+	started, finished time.Time // Start of the first and end of the last call to Fit, see Metadata
+}
+
+// EpochStats summarizes one training epoch. Losses are the average loss per
+// example with Mean reduction, or the total over all examples with Sum.
+type EpochStats struct {
+	Epoch   int     // Index of the epoch, starting at 0
+	Loss    float64 // Training loss, accumulated while the weights were updated
+	ValLoss float64 // Loss on the Validation dataset after the epoch; 0 without one
+
+	LR       float64       // Learning rate used during the epoch
+	Duration time.Duration // Wall time of the epoch, including validation
+
+	// Metrics holds the value of every trainer metric by name on the training
+	// data (accumulated while the weights were updated) and, prefixed with
+	// "val_", on the Validation dataset.
+	Metrics map[string]float64
+
+	hasVal bool
+}
+
+// Metric returns the named quantity of the epoch ("loss", "val_loss" or a
+// key of Metrics such as "val_accuracy") and whether it is available.
+func (s EpochStats) Metric(name string) (float64, bool) {
+	switch name {
+	case "loss":
+		return s.Loss, true
+	case "val_loss":
+		return s.ValLoss, s.hasVal
+	}
+	v, ok := s.Metrics[name]
+	return v, ok
+}
+
+// NewTrainer creates a Trainer for the given model, loss and optimizer,
+// training for a single epoch and showing a progress bar on standard output
+// until configured otherwise. Set Loader before calling Fit.
+func NewTrainer(model engine.Module, loss losses.Loss, opt optim.Optimizer) *Trainer {
+	return &Trainer{
+		Model:     model,
+		Loss:      loss,
+		Optimizer: opt,
+		Epochs:    1,
+		Progress:  NewProgressBar(os.Stdout),
+	}
+}
+
+// Fit trains the model until t.Epochs epochs have been completed, counting
+// epochs from earlier calls and from Resume; raise Epochs to train further.
+// It ends early when a callback calls Stop or returns an error.
+//
+// Cancelling ctx (e.g. with signal.NotifyContext on os.Interrupt) or reaching
+// its deadline stops training at the next batch boundary with an error
+// wrapping ctx.Err(). If InterruptCheckpoint is set, a Snapshot is saved there
+// first. It holds the weights and optimizer state as they are, partway through
+// the interrupted epoch, which Resume then repeats from the start.
+func (t *Trainer) Fit(ctx context.Context) error {
+	if t.Loader == nil || t.Loader.NumBatches() == 0 {
+		return fmt.Errorf("train: no training batches")
+	}
+	if t.Reduction != losses.Mean && t.Reduction != losses.Sum {
+		return fmt.Errorf("train: unsupported batch reduction %v", t.Reduction)
+	}
+	if t.Privacy != nil {
+		if err := t.Privacy.validate(); err != nil {
+			return err
+		}
+	}
+
+	progress := t.Progress
+	if progress == nil {
+		progress = Silent{}
+	}
+	progress.Start(t.Epochs, t.Loader.NumBatches())
+	defer progress.Finish()
+
+	t.stop = false
+	if t.started.IsZero() {
+		t.started = time.Now().UTC()
+	}
+	engine.SetTraining(t.Model, true)
+	log := t.logger()
+	log.Info("training started", "epochs", t.Epochs, "start_epoch", t.completed, "batches_per_epoch", t.Loader.NumBatches())
+	if err := t.each(func(cb Callback) error { return cb.OnTrainBegin(t) }); err != nil {
+		return err
+	}
+	for epoch := t.completed; epoch < t.Epochs && !t.stop; epoch++ {
+		if err := ctx.Err(); err != nil {
+			return t.interrupted(epoch, err)
+		}
+		if err := t.each(func(cb Callback) error { return cb.OnEpochBegin(t, epoch) }); err != nil {
+			return err
+		}
+		start, lr := time.Now(), t.Optimizer.LearningRate()
+		t.Loader.SetEpoch(epoch)
+		t.resetMetrics()
+		loss, err := t.epoch(ctx, epoch, progress)
+		if err != nil {
+			if ctx.Err() != nil {
+				return t.interrupted(epoch, err)
+			}
+			log.Warn("training aborted", "epoch", epoch, "error", err)
+			return err
+		}
+		stats := EpochStats{Epoch: epoch, Loss: loss, LR: lr, Metrics: t.metricValues("")}
+		if t.Privacy != nil {
+			stats.Metrics["epsilon"] = t.Privacy.Epsilon()
+		}
+		if t.Validation != nil && t.Validation.Len() > 0 {
+			stats.ValLoss, stats.hasVal = t.Evaluate(t.Validation), true
+			for name, v := range t.metricValues("val_") {
+				stats.Metrics[name] = v
+			}
+		}
+		stats.Duration = time.Since(start)
+		t.History.Add(stats)
+		progress.EpochDone(stats)
+		log.Info("epoch finished", statsAttrs(stats)...)
+		if t.Scheduler != nil {
+			t.Scheduler.Step()
+		}
+		t.completed = epoch + 1
+		if err := t.each(func(cb Callback) error { return cb.OnEpochEnd(t, stats) }); err != nil {
+			return err
+		}
+		if t.stop {
+			log.Info("training stopped early", "epoch", epoch)
+		}
+	}
+	t.finished = time.Now().UTC()
+	log.Info("training finished", "epochs", t.completed)
+	return t.each(func(cb Callback) error { return cb.OnTrainEnd(t) })
+}
+
+// interrupted handles the cancellation of Fit's context during epoch, saving
+// a checkpoint if InterruptCheckpoint is set.
+func (t *Trainer) interrupted(epoch int, cause error) error {
+	err := fmt.Errorf("train: interrupted in epoch %d: %w", epoch, cause)
+	if t.InterruptCheckpoint != "" {
+		c, saveErr := t.Snapshot()
+		if saveErr == nil {
+			saveErr = c.Save(t.InterruptCheckpoint)
+		}
+		if saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+	}
+	t.logger().Warn("training interrupted", "epoch", epoch, "checkpoint", t.InterruptCheckpoint, "error", cause)
+	return err
+}
+
+// logger returns the trainer's logger, or one that discards everything.
+func (t *Trainer) logger() *slog.Logger {
+	if t.Logger == nil {
+		return engine.DiscardLogger()
+	}
+	return t.Logger
+}
+
+// statsAttrs converts epoch statistics into log attributes.
+func statsAttrs(stats EpochStats) []any {
+	attrs := []any{"epoch", stats.Epoch, "loss", stats.Loss, "lr", stats.LR, "duration", stats.Duration}
+	if v, ok := stats.Metric("val_loss"); ok {
+		attrs = append(attrs, "val_loss", v)
+	}
+	names := make([]string, 0, len(stats.Metrics))
+	for name := range stats.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attrs = append(attrs, name, stats.Metrics[name])
+	}
+	return attrs
+}
+
+// SetSeed makes the run reproducible independently of any other randomness
+// in the process: the Loader shuffles with seed, and the model's stochastic
+// modules (noise, weight sampling) and an optimizer drawing noise (one that
+// implements engine.RandSetter, such as optim.GradNoise) draw from a
+// generator seeded with it. Set the Loader first. The initial weights are drawn when the model is built; use
+// a constructor taking a generator, such as engine.NewMLPRand, for those.
+func (t *Trainer) SetSeed(seed int64) {
+	if t.Loader != nil {
+		t.Loader.SetSeed(seed)
+	}
+	t.source = newCountingSource(seed)
+	r := rand.New(t.source)
+	engine.SetRand(t.Model, r)
+	if rs, ok := t.Optimizer.(engine.RandSetter); ok {
+		rs.SetRand(r)
+	}
+}
+
+// Stop asks Fit to end training after the current epoch. Callbacks such as
+// EarlyStopping call it.
+func (t *Trainer) Stop() {
+	t.stop = true
+}
+
+// each invokes fn for every callback, stopping at the first error.
+func (t *Trainer) each(fn func(Callback) error) error {
+	for _, cb := range t.Callbacks {
+		if err := fn(cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// epoch runs one pass over the dataset and returns the epoch loss. Every
+// optimizer step covers AccumulateSteps batches; callbacks and the progress
+// reporter see the index of the last batch of each step.
+func (t *Trainer) epoch(ctx context.Context, epoch int, progress ProgressReporter) (float64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Stops prefetching if the epoch ends early
+	next := t.batches(ctx)
+	steps := max(t.AccumulateSteps, 1)
+	total, seen, i := 0.0, 0, -1
+	for {
+		var group []preparedBatch
+		for len(group) < steps {
+			batch, ok := next()
+			if !ok {
+				break
+			}
+			group = append(group, batch)
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if len(group) == 0 {
+			break
+		}
+		i += len(group)
+		size := 0
+		for _, batch := range group {
+			size += len(batch.Batch)
+		}
+
+		first := true // Closure optimizers evaluate the batches more than once; record metrics only for the first pass
+		loss := optim.StepWith(t.Optimizer, func() float64 {
+			loss := t.backward(group, first)
+			first = false
+			return loss
+		})
+		if t.Privacy != nil {
+			t.Privacy.Accountant.Step(float64(size)/float64(t.Loader.Dataset.Len()), t.Privacy.NoiseMultiplier)
+		}
+		t.Optimizer.ZeroGrad()
+		t.logger().Debug("batch finished", "epoch", epoch, "batch", i, "size", size, "loss", loss)
+		if err := t.each(func(cb Callback) error { return cb.OnBatchEnd(t, i, loss) }); err != nil {
+			return 0, err
+		}
+
+		if t.Reduction == losses.Mean {
+			loss *= float64(size) // Weight batch means by size; the last batch may be smaller
+		}
+		total += loss
+		seen += size
+		progress.Batch(epoch, i, t.epochLoss(total, seen))
+	}
+	return t.epochLoss(total, seen), nil
+}
+
+// preparedBatch is a batch together with its inputs and targets converted to Values.
+type preparedBatch struct {
+	data.Batch
+	inputs, targets [][]*engine.Value
+}
+
+// prepare converts the examples of a batch to Values.
+func prepare(batch data.Batch) preparedBatch {
+	p := preparedBatch{
+		Batch:   batch,
+		inputs:  make([][]*engine.Value, len(batch)),
+		targets: make([][]*engine.Value, len(batch)),
+	}
+	for i, ex := range batch {
+		p.inputs[i] = engine.ToValue1D(ex.Input)
+		p.targets[i] = engine.ToValue1D(ex.Target)
+	}
+	return p
+}
+
+// batches starts the next epoch of the loader and returns a function that
+// yields its prepared batches in order, reporting false after the last one.
+// With Prefetch the batches are prepared in the background until ctx is done.
+func (t *Trainer) batches(ctx context.Context) func() (preparedBatch, bool) {
+	if t.Prefetch > 0 {
+		ch := data.Prefetch(ctx, t.Loader, t.Prefetch, prepare)
+		return func() (preparedBatch, bool) {
+			batch, ok := <-ch
+			return batch, ok
+		}
+	}
+	batches := t.Loader.Batches()
+	return func() (preparedBatch, bool) {
+		if len(batches) == 0 {
+			return preparedBatch{}, false
+		}
+		batch := prepare(batches[0])
+		batches = batches[1:]
+		return batch, true
+	}
+}
+
+// backward computes the gradients of the loss over a group of micro-batches
+// and returns the loss. FullBackward resets the gradients of its graph, so the
+// gradients of each micro-batch are summed separately and written back to the
+// parameters at the end: weighted by batch size and averaged with Mean
+// reduction, added up with Sum, as if the group were one batch. The parameters
+// are those of the optimizer if it reports them (see optim.Parameterized), so
+// trainable parameters of the loss are accumulated too, and the model's otherwise.
+func (t *Trainer) backward(group []preparedBatch, record bool) float64 {
+	if t.Privacy != nil {
+		return t.privateBackward(group, record)
+	}
+	if len(group) == 1 {
+		loss := t.batchLoss(group[0], record)
+		loss.FullBackward()
+		return loss.Data
+	}
+
+	params, ok := optim.ParametersOf(t.Optimizer)
+	if !ok {
+		params = t.Model.Parameters()
+	}
+	grads := make([]float64, len(params))
+	total, norm := 0.0, 0.0
+	for _, batch := range group {
+		loss := t.batchLoss(batch, record)
+		loss.FullBackward()
+		w := 1.0
+		if t.Reduction == losses.Mean {
+			w = float64(len(batch.Batch))
+			norm += w
+		}
+		for i, p := range params {
+			grads[i] += w * p.Grad
+		}
+		total += w * loss.Data
+	}
+	if t.Reduction != losses.Mean {
+		norm = 1
+	}
+	for i, p := range params {
+		p.Grad = grads[i] / norm
+	}
+	return total / norm
+}
+
+// epochLoss turns the summed loss over seen examples into the reported epoch
+// loss: the average per example with Mean reduction, the total with Sum.
+func (t *Trainer) epochLoss(total float64, seen int) float64 {
+	if t.Reduction == losses.Mean {
+		return total / float64(seen)
+	}
+	return total
+}
+
+// batchLoss builds the graph of the reduced loss over a batch of examples,
+// updating the metrics with the predictions if record is set.
+func (t *Trainer) batchLoss(batch preparedBatch, record bool) *engine.Value {
+	terms := make([]*engine.Value, len(batch.Batch))
+	for i, ex := range batch.Batch {
+		preds := t.Model.Output(batch.inputs[i])
+		terms[i] = t.Loss.Compute(preds, batch.targets[i])
+		if record {
+			t.updateMetrics(preds, ex.Target)
+		}
+	}
+	loss := losses.Reduce(terms, t.Reduction)[0]
+	loss.Label = "batch_loss"
+	return loss
+}
+
+// Evaluate returns the loss of the model over ds, reduced like the training
+// loss, with the model in evaluation mode and without updating any weights.
+// The trainer's metrics are reset and accumulated over ds, so their values
+// afterwards describe ds.
+func (t *Trainer) Evaluate(ds data.Dataset) float64 {
+	engine.SetTraining(t.Model, false)
+	defer engine.SetTraining(t.Model, true)
+
+	t.resetMetrics()
+	total := 0.0
+	for i := 0; i < ds.Len(); i++ {
+		ex := ds.Get(i)
+		preds := t.Model.Output(engine.ToValue1D(ex.Input))
+		total += t.Loss.Compute(preds, engine.ToValue1D(ex.Target)).Data
+		t.updateMetrics(preds, ex.Target)
+	}
+	if t.Reduction == losses.Mean && ds.Len() > 0 {
+		return total / float64(ds.Len())
+	}
+	return total
+}
+
+// CompletedEpochs returns the number of epochs trained so far.
+func (t *Trainer) CompletedEpochs() int {
+	return t.completed
+}
+
+// resetMetrics resets every metric.
+func (t *Trainer) resetMetrics() {
+	for _, m := range t.Metrics {
+		m.Reset()
+	}
+}
+
+// updateMetrics feeds one example's predictions to every metric.
+func (t *Trainer) updateMetrics(preds []*engine.Value, target []float64) {
+	if len(t.Metrics) == 0 {
+		return
+	}
+	out := make([]float64, len(preds))
+	for i, p := range preds {
+		out[i] = p.Data
+	}
+	for _, m := range t.Metrics {
+		m.Update(out, target)
+	}
+}
+
+// metricValues returns the current value of every metric, keyed by prefix + name.
+func (t *Trainer) metricValues(prefix string) map[string]float64 {
+	values := make(map[string]float64, len(t.Metrics))
+	for _, m := range t.Metrics {
+		values[prefix+m.Name()] = m.Value()
+	}
+	return values
+}
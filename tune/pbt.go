@@ -0,0 +1,184 @@
+package tune
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/optim"
+	"github.com/Rmehta-sudo/neural-net/train"
+)
+
+// PBT configures population-based training: a population of models trains
+// concurrently, and every Interval epochs the worst members are replaced by
+// copies of the best ones (weights and optimizer state) whose learning rate
+// and weight decay are then perturbed. Good hyperparameters thus spread
+// through the population while being refined, yielding a schedule rather than
+// a single setting, at the cost of one training run per member.
+type PBT struct {
+	Population  int       // Number of members trained concurrently
+	Interval    int       // Epochs between exploit/explore rounds
+	Rounds      int       // Number of intervals to train
+	Truncation  float64   // Fraction of members replaced (and copied from) each round
+	Factors     []float64 // Multipliers a copied learning rate or weight decay is perturbed by
+	ValFraction float64   // Fraction of Search.Data held out to rank the members
+	Seed        int64     // Seeds the data split, the initial candidates and the perturbations
+}
+
+// NewPBT returns the usual PBT configuration: 8 members, exploiting every 5
+// epochs for 10 rounds, replacing the worst quarter with the best quarter and
+// perturbing by a factor of 0.8 or 1.2, ranked on 20% of the data.
+func NewPBT() PBT {
+	return PBT{
+		Population:  8,
+		Interval:    5,
+		Rounds:      10,
+		Truncation:  0.25,
+		Factors:     []float64{0.8, 1.2},
+		ValFraction: 0.2,
+	}
+}
+
+// Member is one model of a PBT population.
+type Member struct {
+	ID      int
+	Params  Params         // Current hyperparameters; Hidden and Activation are those of the model
+	Trainer *train.Trainer // Trains Trainer.Model; its History spans all rounds
+	Score   float64        // Objective on the held-out data after the last round
+	Parents []int          // IDs of the members copied from, in order
+}
+
+// Population is a list of members, ranked best first by Search.PBT.
+type Population []*Member
+
+// Best returns the first member.
+func (pop Population) Best() *Member {
+	return pop[0]
+}
+
+// Trials returns the current hyperparameters and score of every member, to
+// print with Trials.WriteTable.
+func (pop Population) Trials() Trials {
+	trials := make(Trials, len(pop))
+	for i, m := range pop {
+		trials[i] = Trial{Params: m.Params, Score: m.Score}
+	}
+	return trials
+}
+
+// PBT runs population-based training with members sampled from space and
+// returns the population ranked from best to worst after the last round. The
+// members train for cfg.Interval epochs at a time on goroutines of their own;
+// the objective is measured on a held-out split of the data. If ctx is
+// cancelled, the population is returned as it stands along with the error.
+func (s *Search) PBT(ctx context.Context, space Space, cfg PBT) (Population, error) {
+	if cfg.Population < 2 || cfg.Interval < 1 || len(cfg.Factors) == 0 {
+		return nil, fmt.Errorf("tune: PBT needs at least 2 members, an interval and perturbation factors")
+	}
+	trainSet, val := data.Split(s.Data, cfg.ValFraction, cfg.Seed)
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	pop := make(Population, cfg.Population)
+	for i := range pop {
+		p := space.Sample(rand.New(rand.NewSource(cfg.Seed + int64(i))))
+		model := s.Build(p, rand.New(rand.NewSource(cfg.Seed+int64(i))))
+		tc := s.Config
+		tc.NewOptimizer = func([]*engine.Value) optim.Optimizer { return s.optimizer(model, p) }
+		pop[i] = &Member{ID: i, Params: p, Trainer: tc.NewTrainer(model, trainSet, val)}
+	}
+
+	for round := 0; round < cfg.Rounds; round++ {
+		if err := s.trainRound(ctx, pop, cfg.Interval); err != nil {
+			return pop, err
+		}
+		s.rankMembers(pop)
+		if round < cfg.Rounds-1 {
+			if err := s.exploit(pop, cfg, rng); err != nil {
+				return pop, err
+			}
+		}
+	}
+	return pop, nil
+}
+
+// trainRound trains every member for another interval of epochs concurrently
+// and records its score.
+func (s *Search) trainRound(ctx context.Context, pop Population, interval int) error {
+	errs := make([]error, len(pop))
+	var wg sync.WaitGroup
+	for i, m := range pop {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Trainer.Epochs = m.Trainer.CompletedEpochs() + interval
+			if err := m.Trainer.Fit(ctx); err != nil {
+				errs[i] = fmt.Errorf("tune: member %d: %w", m.ID, err)
+				return
+			}
+			last, _ := m.Trainer.History.Last()
+			score, ok := last.Metric(s.objective())
+			if !ok {
+				errs[i] = fmt.Errorf("tune: objective %q was not reported; add the metric to Config.NewMetrics", s.objective())
+			}
+			m.Score = score
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rankMembers sorts the population from best to worst score.
+func (s *Search) rankMembers(pop Population) {
+	sort.SliceStable(pop, func(i, j int) bool {
+		if s.Maximize {
+			return pop[i].Score > pop[j].Score
+		}
+		return pop[i].Score < pop[j].Score
+	})
+}
+
+// exploit replaces each of the worst members of the ranked population by a
+// copy of a random top member, then perturbs the copied learning rate and
+// weight decay (explore).
+func (s *Search) exploit(pop Population, cfg PBT, rng *rand.Rand) error {
+	n := min(max(int(math.Ceil(cfg.Truncation*float64(len(pop)))), 1), len(pop)/2)
+	for _, loser := range pop[len(pop)-n:] {
+		donor := pop[rng.Intn(n)]
+
+		p := donor.Params
+		p.Hidden = append([]int(nil), donor.Params.Hidden...)
+		p.LR *= cfg.Factors[rng.Intn(len(cfg.Factors))]
+		p.WeightDecay *= cfg.Factors[rng.Intn(len(cfg.Factors))]
+
+		model := engine.CloneModule(donor.Trainer.Model)
+		opt := s.optimizer(model, p)
+		if from, ok := donor.Trainer.Optimizer.(optim.Stateful); ok {
+			if to, ok := opt.(optim.Stateful); ok {
+				state, err := from.State()
+				if err == nil {
+					err = to.LoadState(state)
+				}
+				if err != nil {
+					return fmt.Errorf("tune: copying optimizer of member %d: %w", donor.ID, err)
+				}
+			}
+		}
+		opt.SetLearningRate(p.LR)
+
+		loser.Params = p
+		loser.Trainer.Model = model
+		loser.Trainer.Optimizer = opt
+		loser.Parents = append(loser.Parents, donor.ID)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package tune
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Rmehta-sudo/neural-net/engine"
+)
+
+// Distribution draws values of a hyperparameter from an explicit random
+// number generator, so that every trial can use its own.
+type Distribution[T any] interface {
+	Sample(r *rand.Rand) T
+}
+
+// Uniform samples a float uniformly from [Min, Max).
+type Uniform struct {
+	Min, Max float64
+}
+
+// Sample draws a value.
+func (u Uniform) Sample(r *rand.Rand) float64 {
+	return u.Min + r.Float64()*(u.Max-u.Min)
+}
+
+// LogUniform samples a float whose logarithm is uniform on [log Min, log Max),
+// which suits scale parameters such as learning rates. Min must be positive.
+type LogUniform struct {
+	Min, Max float64
+}
+
+// Sample draws a value.
+func (u LogUniform) Sample(r *rand.Rand) float64 {
+	if u.Min <= 0 || u.Max <= 0 {
+		panic("tune: LogUniform bounds must be positive")
+	}
+	lo, hi := math.Log(u.Min), math.Log(u.Max)
+	return math.Exp(lo + r.Float64()*(hi-lo))
+}
+
+// Choice samples one of Values with equal probability.
+type Choice[T any] struct {
+	Values []T
+}
+
+// Sample draws a value.
+func (c Choice[T]) Sample(r *rand.Rand) T {
+	if len(c.Values) == 0 {
+		panic("tune: Choice has no values")
+	}
+	return c.Values[r.Intn(len(c.Values))]
+}
+
+// Layers samples hidden layer sizes: a depth uniform in [MinDepth, MaxDepth]
+// and, independently for every layer, a width uniform in [MinWidth, MaxWidth].
+type Layers struct {
+	MinDepth, MaxDepth int
+	MinWidth, MaxWidth int
+}
+
+// Sample draws a list of layer sizes.
+func (l Layers) Sample(r *rand.Rand) []int {
+	if l.MinDepth < 0 || l.MaxDepth < l.MinDepth || l.MinWidth < 1 || l.MaxWidth < l.MinWidth {
+		panic("tune: invalid Layers bounds")
+	}
+	sizes := make([]int, l.MinDepth+r.Intn(l.MaxDepth-l.MinDepth+1))
+	for i := range sizes {
+		sizes[i] = l.MinWidth + r.Intn(l.MaxWidth-l.MinWidth+1)
+	}
+	return sizes
+}
+
+// Space lists a sampling distribution for every hyperparameter. A nil
+// dimension always takes the same default as an empty Grid dimension.
+type Space struct {
+	LR          Distribution[float64]
+	Hidden      Distribution[[]int]
+	Activation  Distribution[engine.Activation]
+	WeightDecay Distribution[float64]
+}
+
+// Sample draws one candidate from the space.
+func (s Space) Sample(r *rand.Rand) Params {
+	return Params{
+		LR:          sample(s.LR, r, 0.01),
+		Hidden:      sample(s.Hidden, r, nil),
+		Activation:  sample(s.Activation, r, engine.Tanh),
+		WeightDecay: sample(s.WeightDecay, r, 0),
+	}
+}
+
+// sample draws from d, or returns def if d is nil.
+func sample[T any](d Distribution[T], r *rand.Rand, def T) T {
+	if d == nil {
+		return def
+	}
+	return d.Sample(r)
+}
+
+// Budget limits a random search. At least one of Trials and Time must be set;
+// the search ends at whichever limit is reached first.
+type Budget struct {
+	Trials  int           // Maximum number of trials; 0 means no limit
+	Time    time.Duration // Maximum wall time; 0 means no limit
+	Workers int           // Trials trained concurrently; 0 means 1
+	Seed    int64         // Trial i samples its candidate with a generator seeded by Seed + i
+}
+
+// Random cross-validates candidates sampled from space until the budget is
+// spent and returns the completed trials ranked from best to worst.
+//
+// Trials run on Budget.Workers goroutines. Every trial samples its candidate
+// from its own generator, so the candidate of trial i depends only on the seed,
+// not on scheduling. Trials still running when the time budget expires are
+// discarded; this is not an error. If ctx is cancelled, the trials completed so
+// far are returned along with the context's error.
+func (s *Search) Random(ctx context.Context, space Space, b Budget) (Trials, error) {
+	if b.Trials <= 0 && b.Time <= 0 {
+		return nil, fmt.Errorf("tune: random search needs a trial or time budget")
+	}
+	workers := max(b.Workers, 1)
+
+	budgetCtx, cancel := context.WithCancel(ctx) // Also cancelled by the first failing trial
+	defer cancel()
+	if b.Time > 0 {
+		var cancelTimer context.CancelFunc
+		budgetCtx, cancelTimer = context.WithTimeout(budgetCtx, b.Time)
+		defer cancelTimer()
+	}
+
+	var (
+		mu       sync.Mutex
+		trials   Trials
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				p := space.Sample(rand.New(rand.NewSource(b.Seed + int64(i))))
+				trial, err := s.Evaluate(budgetCtx, p)
+				mu.Lock()
+				switch {
+				case err == nil:
+					trials = append(trials, trial)
+				case budgetCtx.Err() != nil:
+					// Interrupted by the budget or ctx; handled below
+				case firstErr == nil:
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; b.Trials <= 0 || i < b.Trials; i++ {
+		select {
+		case jobs <- i:
+		case <-budgetCtx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	s.Rank(trials)
+	if firstErr != nil {
+		return trials, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return trials, err
+	}
+	if len(trials) == 0 && errors.Is(budgetCtx.Err(), context.DeadlineExceeded) {
+		return trials, fmt.Errorf("tune: time budget of %v expired before any trial completed", b.Time)
+	}
+	return trials, nil
+}
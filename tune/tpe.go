@@ -0,0 +1,255 @@
+package tune
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// TPE proposes candidates with the Tree-structured Parzen Estimator, a
+// sequential model-based (Bayesian) optimization method that needs far fewer
+// trials than a grid to find good settings.
+//
+// After Startup random trials, the observed trials are split into the best
+// Gamma fraction ("good") and the rest ("bad"), and a kernel density is fitted
+// to each group, dimension by dimension: Gaussian kernels for continuous
+// hyperparameters (in log space for LogUniform) and smoothed frequencies for
+// categorical ones (Choice, Layers and custom distributions of non-float
+// values). Of Candidates points drawn around the good trials, the one
+// maximizing the density ratio good/bad is suggested next.
+type TPE struct {
+	Space      Space
+	Maximize   bool    // Higher scores are better; Search.Bayesian sets it from the search
+	Startup    int     // Trials sampled at random from Space before modelling starts
+	Gamma      float64 // Fraction of trials considered good
+	Candidates int     // Candidates scored per suggestion
+
+	rng    *rand.Rand // Seeded with 0 on first use if nil, as in a zero TPE
+	trials []Trial
+}
+
+// NewTPE creates a TPE optimizer over space with the usual defaults: 10
+// startup trials, gamma = 0.25 and 24 candidates per suggestion. seed makes
+// the suggestions reproducible.
+func NewTPE(space Space, seed int64) *TPE {
+	return &TPE{
+		Space:      space,
+		Startup:    10,
+		Gamma:      0.25,
+		Candidates: 24,
+		rng:        rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Observe records the outcome of a trial.
+func (t *TPE) Observe(trial Trial) {
+	t.trials = append(t.trials, trial)
+}
+
+// Trials returns the observed trials in the order they were observed.
+func (t *TPE) Trials() []Trial {
+	return t.trials
+}
+
+// Suggest proposes the candidate to evaluate next.
+func (t *TPE) Suggest() Params {
+	if t.rng == nil {
+		t.rng = rand.New(rand.NewSource(0))
+	}
+	if len(t.trials) < max(t.Startup, 2) {
+		return t.Space.Sample(t.rng)
+	}
+	good, bad := t.split()
+	dims := t.dimensions()
+
+	var best Params
+	bestScore := math.Inf(-1)
+	for c := 0; c < max(t.Candidates, 1); c++ {
+		p := t.Space.Sample(t.rng) // Prior draw, moved towards a good trial below
+		base := good[t.rng.Intn(len(good))].Params
+		prior := 1 / float64(len(good)+1)
+		for _, d := range dims {
+			if t.rng.Float64() >= prior {
+				d.perturb(t.rng, &p, base, len(good))
+			}
+		}
+
+		score := 0.0
+		for _, d := range dims {
+			score += math.Log(d.density(p, good)) - math.Log(d.density(p, bad))
+		}
+		if score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	return best
+}
+
+// split divides the observed trials into the best Gamma fraction and the rest.
+func (t *TPE) split() (good, bad []Trial) {
+	sorted := append([]Trial(nil), t.trials...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if t.Maximize {
+			return sorted[i].Score > sorted[j].Score
+		}
+		return sorted[i].Score < sorted[j].Score
+	})
+	n := int(math.Ceil(t.Gamma * float64(len(sorted))))
+	n = min(max(n, 1), len(sorted)-1)
+	return sorted[:n], sorted[n:]
+}
+
+// dimension is one hyperparameter as modelled by TPE.
+type dimension interface {
+	// perturb moves the value of p towards the value of base, one of n good trials.
+	perturb(r *rand.Rand, p *Params, base Params, n int)
+	// density estimates how likely the value of p is under the given trials.
+	density(p Params, trials []Trial) float64
+}
+
+// dimensions returns the modelled hyperparameters of the space; dimensions
+// without a distribution are fixed and skipped.
+func (t *TPE) dimensions() []dimension {
+	var dims []dimension
+	if t.Space.LR != nil {
+		dims = append(dims, t.numeric(t.Space.LR, func(p *Params) *float64 { return &p.LR }))
+	}
+	if t.Space.WeightDecay != nil {
+		dims = append(dims, t.numeric(t.Space.WeightDecay, func(p *Params) *float64 { return &p.WeightDecay }))
+	}
+	if t.Space.Hidden != nil {
+		dims = append(dims, categorical{
+			key: func(p Params) string { return fmt.Sprint(p.Hidden) },
+			set: func(dst *Params, src Params) { dst.Hidden = append([]int(nil), src.Hidden...) },
+			all: t.trials,
+		})
+	}
+	if t.Space.Activation != nil {
+		dims = append(dims, categorical{
+			key: func(p Params) string { return p.Activation.String() },
+			set: func(dst *Params, src Params) { dst.Activation = src.Activation },
+			all: t.trials,
+		})
+	}
+	return dims
+}
+
+// numeric models a float hyperparameter. Uniform and LogUniform supply their
+// bounds; for other distributions the range of the observed values is used.
+func (t *TPE) numeric(d Distribution[float64], field func(*Params) *float64) dimension {
+	n := continuous{field: field}
+	switch d := d.(type) {
+	case Uniform:
+		n.lo, n.hi = d.Min, d.Max
+	case LogUniform:
+		n.log = true
+		n.lo, n.hi = math.Log(d.Min), math.Log(d.Max)
+	default:
+		n.lo, n.hi = math.Inf(1), math.Inf(-1)
+		for _, trial := range t.trials {
+			x := *field(&trial.Params)
+			n.lo, n.hi = math.Min(n.lo, x), math.Max(n.hi, x)
+		}
+	}
+	if n.hi <= n.lo {
+		n.hi = n.lo + 1 // Degenerate range; any positive width works
+	}
+	return n
+}
+
+// continuous is a float hyperparameter modelled with Gaussian kernels on
+// [lo, hi], in log space if log is set.
+type continuous struct {
+	field  func(*Params) *float64
+	log    bool
+	lo, hi float64
+}
+
+// get returns the (transformed) value of p.
+func (c continuous) get(p Params) float64 {
+	x := *c.field(&p)
+	if c.log {
+		return math.Log(x)
+	}
+	return x
+}
+
+// set stores the transformed value x in p.
+func (c continuous) set(p *Params, x float64) {
+	if c.log {
+		x = math.Exp(x)
+	}
+	*c.field(p) = x
+}
+
+// bandwidth returns the kernel width for n points, shrinking as they accumulate.
+func (c continuous) bandwidth(n int) float64 {
+	return (c.hi - c.lo) * math.Max(math.Pow(float64(n), -0.2)/2, 0.01)
+}
+
+// perturb draws around the value of base with the kernel bandwidth, within the bounds.
+func (c continuous) perturb(r *rand.Rand, p *Params, base Params, n int) {
+	x := c.get(base) + r.NormFloat64()*c.bandwidth(n)
+	c.set(p, math.Min(math.Max(x, c.lo), c.hi))
+}
+
+// density is the mean of the Gaussian kernels centred on the trials and a uniform prior.
+func (c continuous) density(p Params, trials []Trial) float64 {
+	x, sigma := c.get(p), c.bandwidth(len(trials))
+	sum := 1 / (c.hi - c.lo) // Uniform prior component keeps the density positive
+	for _, trial := range trials {
+		z := (x - c.get(trial.Params)) / sigma
+		sum += math.Exp(-z*z/2) / (sigma * math.Sqrt(2*math.Pi))
+	}
+	return sum / float64(len(trials)+1)
+}
+
+// categorical is a hyperparameter modelled by the smoothed frequency of each
+// distinct value, identified by key.
+type categorical struct {
+	key func(Params) string
+	set func(dst *Params, src Params)
+	all []Trial // Every observed trial, to count the distinct values
+}
+
+// perturb copies the value of base.
+func (c categorical) perturb(_ *rand.Rand, p *Params, base Params, _ int) {
+	c.set(p, base)
+}
+
+// density is the frequency of the value among the trials with add-one smoothing.
+func (c categorical) density(p Params, trials []Trial) float64 {
+	seen := map[string]bool{}
+	for _, trial := range c.all {
+		seen[c.key(trial.Params)] = true
+	}
+	count, k := 0, c.key(p)
+	for _, trial := range trials {
+		if c.key(trial.Params) == k {
+			count++
+		}
+	}
+	return float64(count+1) / float64(len(trials)+len(seen)+1) // +1 for values not seen yet
+}
+
+// Bayesian runs n trials suggested one after another by opt, which learns from
+// every completed trial, and returns them ranked from best to worst. Trials
+// already observed by opt count towards its model but are not returned. If ctx
+// is cancelled, the trials completed so far are returned along with the error.
+func (s *Search) Bayesian(ctx context.Context, opt *TPE, n int) (Trials, error) {
+	opt.Maximize = s.Maximize
+	var trials Trials
+	for i := 0; i < n; i++ {
+		trial, err := s.Evaluate(ctx, opt.Suggest())
+		if err != nil {
+			s.Rank(trials)
+			return trials, err
+		}
+		opt.Observe(trial)
+		trials = append(trials, trial)
+	}
+	s.Rank(trials)
+	return trials, nil
+}
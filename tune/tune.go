@@ -0,0 +1,200 @@
+// Package tune searches for good training hyperparameters by training MLPs
+// with candidate settings under cross-validation and ranking the results.
+package tune
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Rmehta-sudo/neural-net/data"
+	"github.com/Rmehta-sudo/neural-net/engine"
+	"github.com/Rmehta-sudo/neural-net/optim"
+	"github.com/Rmehta-sudo/neural-net/train"
+)
+
+// Params is one candidate setting of the tuned hyperparameters.
+type Params struct {
+	LR          float64
+	Hidden      []int             // Sizes of the hidden layers
+	Activation  engine.Activation // Activation of the hidden layers
+	WeightDecay float64
+}
+
+// String formats the parameters compactly, e.g. "lr=0.01 hidden=[8 8] act=relu wd=0".
+func (p Params) String() string {
+	return fmt.Sprintf("lr=%.4g hidden=%v act=%v wd=%.4g", p.LR, p.Hidden, p.Activation, p.WeightDecay)
+}
+
+// Search holds everything about a hyperparameter search except the
+// candidates: the data, the shape of the models, and how each candidate is
+// trained and scored.
+type Search struct {
+	Data             data.Dataset
+	NumIn, NumOut    int
+	OutputActivation engine.Activation // Activation of the output layer
+
+	Folds  int               // Number of cross-validation folds
+	Config train.TrainConfig // Loss, epochs, batch size, metrics and seed; the optimizer comes from NewOptimizer
+
+	// NewOptimizer builds the optimizer for a candidate; nil uses AdamW with
+	// the candidate's learning rate and weight decay, which does not decay
+	// biases (see optim.NewAdamWFor).
+	NewOptimizer func(params []*engine.Value, p Params) optim.Optimizer
+
+	Objective string // Cross-validated score to rank by; "" means "val_loss"
+	Maximize  bool   // Higher objective is better (e.g. "val_accuracy")
+}
+
+// Trial is the cross-validated outcome of one candidate.
+type Trial struct {
+	Params Params
+	Score  float64 // Mean of the objective over the folds
+	Std    float64 // Standard deviation of the objective over the folds
+	CV     train.CVResult
+}
+
+// objective returns the name of the ranked score.
+func (s *Search) objective() string {
+	if s.Objective == "" {
+		return "val_loss"
+	}
+	return s.Objective
+}
+
+// Build creates a fresh MLP for the candidate p with initial weights drawn from r.
+func (s *Search) Build(p Params, r *rand.Rand) engine.Module {
+	mlp := engine.NewMLPRand(append(append([]int(nil), p.Hidden...), s.NumOut), s.NumIn, r)
+	for i, layer := range mlp.Layers {
+		if i < len(p.Hidden) {
+			layer.WithActivation(p.Activation)
+		} else {
+			layer.WithActivation(s.OutputActivation)
+		}
+	}
+	return mlp
+}
+
+// optimizer builds the optimizer of the candidate p for model.
+func (s *Search) optimizer(model engine.Module, p Params) optim.Optimizer {
+	if s.NewOptimizer != nil {
+		return s.NewOptimizer(model.Parameters(), p)
+	}
+	return optim.NewAdamWFor(model, p.LR, p.WeightDecay)
+}
+
+// Evaluate cross-validates the candidate p and returns its trial.
+func (s *Search) Evaluate(ctx context.Context, p Params) (Trial, error) {
+	// CrossValidate builds the optimizer of each fold right after its model,
+	// so the optimizer can look up the model's biases.
+	var model engine.Module
+	cfg := s.Config
+	cfg.NewOptimizer = func([]*engine.Value) optim.Optimizer { return s.optimizer(model, p) }
+
+	build := func() engine.Module {
+		model = s.Build(p, rand.New(rand.NewSource(cfg.Seed)))
+		return model
+	}
+	cv, err := train.CrossValidate(ctx, build, s.Data, s.Folds, cfg)
+	if err != nil {
+		return Trial{}, fmt.Errorf("tune: %v: %w", p, err)
+	}
+	score, ok := cv.Mean[s.objective()]
+	if !ok {
+		return Trial{}, fmt.Errorf("tune: objective %q was not reported; add the metric to Config.NewMetrics", s.objective())
+	}
+	return Trial{Params: p, Score: score, Std: cv.Std[s.objective()], CV: cv}, nil
+}
+
+// Rank sorts trials from best to worst objective.
+func (s *Search) Rank(trials []Trial) {
+	sort.SliceStable(trials, func(i, j int) bool {
+		if s.Maximize {
+			return trials[i].Score > trials[j].Score
+		}
+		return trials[i].Score < trials[j].Score
+	})
+}
+
+// Grid lists candidate values for every hyperparameter. An empty dimension
+// contributes a single value: a learning rate of 0.01, no hidden layers, Tanh
+// or no weight decay.
+type Grid struct {
+	LR          []float64
+	Hidden      [][]int
+	Activation  []engine.Activation
+	WeightDecay []float64
+}
+
+// Combinations returns the cartesian product of the grid's dimensions.
+func (g Grid) Combinations() []Params {
+	lrs := orDefault(g.LR, 0.01)
+	hidden := orDefault(g.Hidden, nil)
+	acts := orDefault(g.Activation, engine.Tanh)
+	wds := orDefault(g.WeightDecay, 0)
+
+	var out []Params
+	for _, lr := range lrs {
+		for _, h := range hidden {
+			for _, act := range acts {
+				for _, wd := range wds {
+					out = append(out, Params{LR: lr, Hidden: h, Activation: act, WeightDecay: wd})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// orDefault returns vs, or a single default value if vs is empty.
+func orDefault[T any](vs []T, def T) []T {
+	if len(vs) == 0 {
+		return []T{def}
+	}
+	return vs
+}
+
+// Grid cross-validates every combination of the grid and returns the trials
+// ranked from best to worst.
+func (s *Search) Grid(ctx context.Context, g Grid) (Trials, error) {
+	var trials Trials
+	for _, p := range g.Combinations() {
+		trial, err := s.Evaluate(ctx, p)
+		if err != nil {
+			return trials, err
+		}
+		trials = append(trials, trial)
+	}
+	s.Rank(trials)
+	return trials, nil
+}
+
+// Trials is a list of trials, usually ranked best first.
+type Trials []Trial
+
+// Best returns the first trial.
+func (ts Trials) Best() Trial {
+	return ts[0]
+}
+
+// WriteTable writes the trials as an aligned text table with their rank.
+func (ts Trials) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "rank\tlr\thidden\tactivation\tweight decay\tscore")
+	for i, t := range ts {
+		fmt.Fprintf(tw, "%d\t%.4g\t%v\t%v\t%.4g\t%.4f ± %.4f\n",
+			i+1, t.Params.LR, t.Params.Hidden, t.Params.Activation, t.Params.WeightDecay, t.Score, t.Std)
+	}
+	return tw.Flush()
+}
+
+// String renders the table of WriteTable.
+func (ts Trials) String() string {
+	var sb strings.Builder
+	ts.WriteTable(&sb)
+	return sb.String()
+}